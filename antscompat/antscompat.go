@@ -0,0 +1,300 @@
+// Package antscompat 以 ants v2 的函数和选项名称提供 laborer 的池，
+// 现有的 ants 用户只需要修改导入路径即可迁移。
+//
+// Pool、PoolWithFunc、MultiPool 等类型是 laborer 对应类型的别名，
+// 方法集与 ants 相同，并且可以继续使用 laborer 提供的其他方法。
+// 构造函数和选项按 ants 的规则处理参数：容量小于等于 0 表示不限制容量，
+// 过期时间为 0 时使用 DefaultCleanIntervalTime，Logger 为 nil 时使用默认的日志记录器。
+// Submit、Running 等包级函数使用 laborer.DefaultPool() 返回的默认池。
+//
+// 示例:
+//
+//	import ants "github.com/kawaiirei0/laborer/antscompat"
+//
+//	pool, _ := ants.NewPool(100, ants.WithOptions(ants.Options{
+//	    ExpiryDuration: 10 * time.Second,
+//	    Nonblocking:    true,
+//	}))
+//	defer pool.Release()
+//
+//	_ = pool.Submit(task)
+//
+// 与 ants 的差异：错误值是 laborer 的哨兵错误，ReleaseTimeout 返回的超时错误包装了
+// ErrTimeout，需要用 errors.Is 判断；ants 中接收 *Options 的自定义 Option 需要改为
+// 接收 *laborer.Options；MultiPool 没有 RunningByIndex 等按索引查询的方法；
+// Pool.Free 等方法返回空闲队列中的 worker 数量，包级函数 Free 则与 ants 一样返回 Cap()-Running()。
+package antscompat
+
+import (
+	"errors"
+	"time"
+
+	"github.com/kawaiirei0/laborer"
+)
+
+const (
+	// DefaultAntsPoolSize 默认池的初始容量
+	DefaultAntsPoolSize = laborer.DefaultPoolSize
+
+	// DefaultCleanIntervalTime 过期时间设置为 0 时使用的过期时间
+	DefaultCleanIntervalTime = time.Second
+)
+
+const (
+	// OPENED 池处于打开状态
+	OPENED = laborer.OPENED
+
+	// CLOSED 池已关闭
+	CLOSED = laborer.CLOSED
+)
+
+const (
+	// RoundRobin 轮询分配任务的负载均衡策略
+	RoundRobin = laborer.RoundRobin
+
+	// LeastTasks 分配给运行任务最少的子池的负载均衡策略
+	LeastTasks = laborer.LeastTasks
+)
+
+type (
+	// Pool 通用的 goroutine 池
+	Pool = laborer.Pool
+
+	// PoolWithFunc 执行固定函数的 goroutine 池
+	PoolWithFunc = laborer.PoolWithFunc
+
+	// MultiPool 由多个 Pool 组成的分片池
+	MultiPool = laborer.MultiPool
+
+	// MultiPoolWithFunc 由多个 PoolWithFunc 组成的分片池
+	MultiPoolWithFunc = laborer.MultiPoolWithFunc
+
+	// LoadBalancingStrategy 分片池的负载均衡策略
+	LoadBalancingStrategy = laborer.LoadBalancingStrategy
+
+	// Logger 池使用的日志记录器
+	Logger = laborer.Logger
+
+	// Option 池的配置选项，可以与 laborer 的选项混用
+	Option = laborer.Option
+)
+
+var (
+	// ErrLackPoolFunc 创建函数池时没有提供函数
+	ErrLackPoolFunc = laborer.ErrInvalidPoolFunc
+
+	// ErrInvalidPoolExpiry 过期时间为负数
+	ErrInvalidPoolExpiry = laborer.ErrInvalidPoolExpiry
+
+	// ErrPoolClosed 向已关闭的池提交任务
+	ErrPoolClosed = laborer.ErrPoolClosed
+
+	// ErrPoolOverload 池已满且为非阻塞模式，或阻塞的提交数已达上限
+	ErrPoolOverload = laborer.ErrPoolOverload
+
+	// ErrInvalidPreAllocSize 预分配模式下容量不限制
+	ErrInvalidPreAllocSize = errors.New("can not set up a negative capacity under PreAlloc mode")
+
+	// ErrTimeout 操作超时
+	ErrTimeout = laborer.ErrTimeout
+
+	// ErrInvalidLoadBalancingStrategy 无效的负载均衡策略
+	ErrInvalidLoadBalancingStrategy = laborer.ErrInvalidLoadBalancingStrategy
+
+	// ErrInvalidMultiPoolSize 分片池的子池数量不是正数
+	ErrInvalidMultiPoolSize = laborer.ErrInvalidPoolSize
+)
+
+// Options ants 的配置项，通过 WithOptions 一次性设置
+type Options struct {
+	// ExpiryDuration 空闲 worker 的过期时间，为 0 时使用 DefaultCleanIntervalTime
+	ExpiryDuration time.Duration
+
+	// PreAlloc 是否预分配 worker 队列
+	PreAlloc bool
+
+	// MaxBlockingTasks 阻塞等待的提交数上限，为 0 时不限制
+	MaxBlockingTasks int
+
+	// Nonblocking 池已满时是否立即返回 ErrPoolOverload
+	Nonblocking bool
+
+	// PanicHandler 处理任务 panic 的函数
+	PanicHandler func(interface{})
+
+	// Logger 日志记录器，为 nil 时使用默认的日志记录器
+	Logger Logger
+
+	// DisablePurge 是否禁止回收空闲 worker
+	DisablePurge bool
+}
+
+// WithOptions 一次性设置 Options 中的所有配置项
+func WithOptions(options Options) Option {
+	return func(opts *laborer.Options) {
+		opts.ExpiryDuration = options.ExpiryDuration
+		opts.PreAlloc = options.PreAlloc
+		opts.MaxBlockingTasks = options.MaxBlockingTasks
+		opts.Nonblocking = options.Nonblocking
+		opts.PanicHandler = options.PanicHandler
+		if options.Logger != nil {
+			opts.Logger = options.Logger
+		}
+		opts.DisablePurge = options.DisablePurge
+	}
+}
+
+// WithExpiryDuration 设置空闲 worker 的过期时间，为 0 时使用 DefaultCleanIntervalTime
+func WithExpiryDuration(expiryDuration time.Duration) Option {
+	return laborer.WithExpiryDuration(expiryDuration)
+}
+
+// WithPreAlloc 设置是否预分配 worker 队列
+func WithPreAlloc(preAlloc bool) Option {
+	return laborer.WithPreAlloc(preAlloc)
+}
+
+// WithMaxBlockingTasks 设置阻塞等待的提交数上限
+func WithMaxBlockingTasks(maxBlockingTasks int) Option {
+	return laborer.WithMaxBlockingTasks(maxBlockingTasks)
+}
+
+// WithNonblocking 设置池已满时是否立即返回 ErrPoolOverload
+func WithNonblocking(nonblocking bool) Option {
+	return laborer.WithNonblocking(nonblocking)
+}
+
+// WithPanicHandler 设置处理任务 panic 的函数
+func WithPanicHandler(panicHandler func(interface{})) Option {
+	return laborer.WithPanicHandler(panicHandler)
+}
+
+// WithLogger 设置日志记录器，为 nil 时使用默认的日志记录器
+func WithLogger(logger Logger) Option {
+	return func(opts *laborer.Options) {
+		if logger != nil {
+			opts.Logger = logger
+		}
+	}
+}
+
+// WithDisablePurge 设置是否禁止回收空闲 worker
+func WithDisablePurge(disable bool) Option {
+	return laborer.WithDisablePurge(disable)
+}
+
+// NewPool 创建容量为 size 的池，size 小于等于 0 时不限制容量
+func NewPool(size int, options ...Option) (*Pool, error) {
+	size, options, err := poolOptions(size, options)
+	if err != nil {
+		return nil, err
+	}
+	return laborer.NewPool(size, options...)
+}
+
+// NewPoolWithFunc 创建执行 pf 的函数池，size 小于等于 0 时不限制容量
+func NewPoolWithFunc(size int, pf func(interface{}), options ...Option) (*PoolWithFunc, error) {
+	if pf == nil {
+		return nil, ErrLackPoolFunc
+	}
+	size, options, err := poolOptions(size, options)
+	if err != nil {
+		return nil, err
+	}
+	return laborer.NewPoolWithFunc(size, pf, options...)
+}
+
+// NewMultiPool 创建由 size 个池组成的分片池，sizePerPool 小于等于 0 时子池不限制容量
+func NewMultiPool(size, sizePerPool int, lbs LoadBalancingStrategy, options ...Option) (*MultiPool, error) {
+	sizePerPool, options, err := poolOptions(sizePerPool, options)
+	if err != nil {
+		return nil, err
+	}
+	return laborer.NewMultiPool(size, sizePerPool, lbs, options...)
+}
+
+// NewMultiPoolWithFunc 创建由 size 个函数池组成的分片池，sizePerPool 小于等于 0 时子池不限制容量
+func NewMultiPoolWithFunc(size, sizePerPool int, pf func(interface{}), lbs LoadBalancingStrategy, options ...Option) (*MultiPoolWithFunc, error) {
+	if pf == nil {
+		return nil, ErrLackPoolFunc
+	}
+	sizePerPool, options, err := poolOptions(sizePerPool, options)
+	if err != nil {
+		return nil, err
+	}
+	return laborer.NewMultiPoolWithFunc(size, sizePerPool, pf, lbs, options...)
+}
+
+// poolOptions 按 ants 的规则转换容量和选项
+// 容量小于等于 0 转换为 -1，过期时间为 0 时使用 DefaultCleanIntervalTime，
+// 预分配模式下不限制容量时返回 ErrInvalidPreAllocSize。
+// 没有指定队列类型时与 ants 一样只在预分配模式下使用循环队列，
+// 避免 DefaultAntsPoolSize 这样的大容量按容量自动选择循环队列而一次性分配。
+func poolOptions(size int, options []Option) (int, []Option, error) {
+	if size <= 0 {
+		size = -1
+	}
+	if size == -1 && laborer.NewOptions(options...).PreAlloc {
+		return 0, nil, ErrInvalidPreAllocSize
+	}
+
+	options = append(options[:len(options):len(options)], func(opts *laborer.Options) {
+		if opts.ExpiryDuration == 0 {
+			opts.ExpiryDuration = DefaultCleanIntervalTime
+		}
+		if opts.QueueType == laborer.AutoQueue {
+			opts.QueueType = laborer.Stack
+			if opts.PreAlloc {
+				opts.QueueType = laborer.LoopQueue
+			}
+		}
+	})
+	return size, options, nil
+}
+
+// defaultPool 返回包级函数使用的默认池，即 laborer.DefaultPool()
+// 宿主程序可以通过 laborer.DefaultPool 和 laborer.SetDefaultCapacity 观察和调整它。
+func defaultPool() *Pool {
+	return laborer.DefaultPool()
+}
+
+// Submit 向默认池提交任务
+func Submit(task func()) error {
+	return defaultPool().Submit(task)
+}
+
+// Running 返回默认池中正在运行的 worker 数量
+func Running() int {
+	return defaultPool().Running()
+}
+
+// Cap 返回默认池的容量
+func Cap() int {
+	return defaultPool().Cap()
+}
+
+// Free 返回默认池中可用的 worker 数量
+// 与 ants 一致为 Cap()-Running()，包括尚未创建的 worker；无限容量时返回 -1。
+func Free() int {
+	p := defaultPool()
+	c := p.Cap()
+	if c < 0 {
+		return -1
+	}
+	return c - p.Running()
+}
+
+// Release 关闭默认池
+func Release() {
+	defaultPool().Release()
+}
+
+// ReleaseTimeout 关闭默认池并等待任务完成，超时返回包装了 ErrTimeout 的错误
+func ReleaseTimeout(timeout time.Duration) error {
+	return defaultPool().ReleaseTimeout(timeout)
+}
+
+// Reboot 重新打开已关闭的默认池
+func Reboot() {
+	defaultPool().Reboot()
+}
@@ -0,0 +1,155 @@
+package antscompat
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestNewPool 测试按 ants 的规则处理容量和选项
+func TestNewPool(t *testing.T) {
+	var handled atomic.Value
+	pool, err := NewPool(0, WithOptions(Options{
+		Nonblocking:  true,
+		PanicHandler: func(v interface{}) { handled.Store(v) },
+	}), WithLogger(nil))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	if pool.Cap() != -1 {
+		t.Errorf("容量为 0 时期望不限制容量，实际 %d", pool.Cap())
+	}
+	opts := pool.Options()
+	if !opts.Nonblocking || opts.Logger == nil {
+		t.Errorf("WithOptions 的配置没有生效: %+v", opts)
+	}
+	if opts.ExpiryDuration != DefaultCleanIntervalTime {
+		t.Errorf("过期时间为 0 时期望使用 %v，实际 %v", DefaultCleanIntervalTime, opts.ExpiryDuration)
+	}
+
+	if err := pool.Submit(func() { panic("boom") }); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	pool.Wait()
+	if handled.Load() != "boom" {
+		t.Errorf("期望 PanicHandler 收到 boom，实际 %v", handled.Load())
+	}
+}
+
+// TestNewPoolErrors 测试构造函数返回 ants 的错误
+func TestNewPoolErrors(t *testing.T) {
+	if _, err := NewPool(-1, WithPreAlloc(true)); err != ErrInvalidPreAllocSize {
+		t.Errorf("期望返回 ErrInvalidPreAllocSize，实际 %v", err)
+	}
+	if _, err := NewPool(10, WithExpiryDuration(-time.Second)); err != ErrInvalidPoolExpiry {
+		t.Errorf("期望返回 ErrInvalidPoolExpiry，实际 %v", err)
+	}
+	if _, err := NewPoolWithFunc(10, nil); err != ErrLackPoolFunc {
+		t.Errorf("期望返回 ErrLackPoolFunc，实际 %v", err)
+	}
+	if _, err := NewMultiPool(0, 10, RoundRobin); err != ErrInvalidMultiPoolSize {
+		t.Errorf("期望返回 ErrInvalidMultiPoolSize，实际 %v", err)
+	}
+}
+
+// TestMaxBlockingTasks 测试等待的提交数达到 ants 的 MaxBlockingTasks 上限后返回 ErrPoolOverload
+func TestMaxBlockingTasks(t *testing.T) {
+	pool, err := NewPool(1, WithMaxBlockingTasks(1))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	release := make(chan struct{})
+	if err := pool.Submit(func() { <-release }); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	errs := make(chan error, 1)
+	go func() { errs <- pool.Submit(func() {}) }()
+	for pool.Waiting() != 1 {
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := pool.Submit(func() {}); !errors.Is(err, ErrPoolOverload) {
+		t.Errorf("期望返回 ErrPoolOverload，实际 %v", err)
+	}
+	close(release)
+	if err := <-errs; err != nil {
+		t.Errorf("等待中的提交期望成功，实际 %v", err)
+	}
+}
+
+// TestPoolWithFunc 测试函数池和分片函数池
+func TestPoolWithFunc(t *testing.T) {
+	var sum int64
+	pf := func(args interface{}) { atomic.AddInt64(&sum, int64(args.(int))) }
+
+	pool, err := NewPoolWithFunc(4, pf)
+	if err != nil {
+		t.Fatalf("创建函数池失败: %v", err)
+	}
+	defer pool.Release()
+	mp, err := NewMultiPoolWithFunc(2, 2, pf, LeastTasks)
+	if err != nil {
+		t.Fatalf("创建分片函数池失败: %v", err)
+	}
+	defer mp.Release()
+
+	for i := 1; i <= 100; i++ {
+		if err := pool.Invoke(i); err != nil {
+			t.Fatalf("提交调用失败: %v", err)
+		}
+		if err := mp.Invoke(i); err != nil {
+			t.Fatalf("提交调用失败: %v", err)
+		}
+	}
+	pool.Wait()
+	if err := mp.ReleaseTimeout(time.Second); err != nil {
+		t.Fatalf("关闭分片函数池失败: %v", err)
+	}
+	if got := atomic.LoadInt64(&sum); got != 10100 {
+		t.Errorf("期望总和为 10100，实际为 %d", got)
+	}
+}
+
+// TestDefaultPool 测试包级函数使用默认池
+func TestDefaultPool(t *testing.T) {
+	if Cap() != DefaultAntsPoolSize {
+		t.Errorf("期望默认池容量为 %d，实际 %d", DefaultAntsPoolSize, Cap())
+	}
+	// 与 ants 一致，尚未创建的 worker 也计为可用
+	if Free() != Cap()-Running() {
+		t.Errorf("期望 Free 为 Cap()-Running() = %d，实际 %d", Cap()-Running(), Free())
+	}
+
+	var wg sync.WaitGroup
+	var n atomic.Int64
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		if err := Submit(func() {
+			defer wg.Done()
+			n.Add(1)
+		}); err != nil {
+			t.Fatalf("提交任务失败: %v", err)
+		}
+	}
+	wg.Wait()
+	if n.Load() != 10 {
+		t.Errorf("期望执行 10 个任务，实际 %d", n.Load())
+	}
+
+	if err := ReleaseTimeout(time.Second); err != nil {
+		t.Fatalf("关闭默认池失败: %v", err)
+	}
+	if err := Submit(func() {}); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("关闭后期望返回 ErrPoolClosed，实际 %v", err)
+	}
+	Reboot()
+	if err := Submit(func() {}); err != nil {
+		t.Errorf("重启后提交失败: %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package laborer
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"sync/atomic"
+)
+
+// LoadBalancingStrategy 分片池在各个子池之间分配任务的策略
+type LoadBalancingStrategy int
+
+const (
+	// RoundRobin 按顺序轮流选择子池
+	RoundRobin LoadBalancingStrategy = iota + 1
+
+	// LeastBusy 选择正在运行的 worker 最少的子池
+	LeastBusy
+
+	// Random 随机选择子池
+	Random
+
+	// LeastTasks 选择正在运行和等待执行的任务总数最少的子池
+	// 与 LeastBusy 相比，在任务耗时差异较大、子池已满时也能避免负载集中到某个子池
+	LeastTasks
+
+	// KeyHash 按调用方提供的键的哈希选择子池
+	// 同一个键的任务总是落到同一个子池，提高 per-worker 资源等子池本地状态的缓存局部性。
+	// 通过 SubmitWithKey / InvokeWithKey 提供键，不带键的提交按轮询选择子池。
+	KeyHash
+)
+
+// valid 返回策略是否为已定义的值
+func (s LoadBalancingStrategy) valid() bool {
+	switch s {
+	case RoundRobin, LeastBusy, Random, LeastTasks, KeyHash:
+		return true
+	}
+	return false
+}
+
+// balancer 根据负载均衡策略选择子池的下标
+type balancer struct {
+	strategy LoadBalancingStrategy
+
+	// next 轮询使用的递增序号，使用 64 位避免回绕时打乱轮询顺序
+	next atomic.Uint64
+}
+
+// pickKey 返回带键的任务应当提交到的子池下标
+// KeyHash 策略下按键的哈希选择，其他策略忽略键。
+func (b *balancer) pickKey(n int, key string, load func(i int) int) int {
+	if b.strategy == KeyHash {
+		return hashIndex(key, n)
+	}
+	return b.pick(n, load)
+}
+
+// hashIndex 返回键在 n 个子池中对应的下标
+func hashIndex(key string, n int) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return int(h.Sum32() % uint32(n))
+}
+
+// pick 返回下一个任务应当提交到的子池下标
+// load 返回第 i 个子池的负载，由调用方根据策略决定统计哪些任务。
+func (b *balancer) pick(n int, load func(i int) int) int {
+	switch b.strategy {
+	case LeastBusy, LeastTasks:
+		best, min := 0, load(0)
+		for i := 1; i < n; i++ {
+			if l := load(i); l < min {
+				best, min = i, l
+			}
+		}
+		return best
+	case Random:
+		return rand.Intn(n)
+	default:
+		return int((b.next.Add(1) - 1) % uint64(n))
+	}
+}
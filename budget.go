@@ -0,0 +1,92 @@
+package laborer
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Budget 多个池共享的并发预算
+//
+// 挂载了同一个 Budget 的池，同时执行的任务总数不会超过预算容量，
+// 例如将多个池的总并发与数据库连接数绑定。每个池仍保留自己的容量、
+// 队列和配置：任务在提交时占用一个预算名额，执行结束（包括 panic）
+// 后归还。预算用尽时按池的配置处理：阻塞模式下提交者等待其他池归还名额
+// （SubmitContext、SubmitWithTimeout 等的取消和超时同样生效），
+// 非阻塞模式下提交返回 ErrPoolOverload。等待预算的提交不会占用 worker。
+type Budget struct {
+	slots chan struct{}
+
+	// mu 串行化一次占用多个名额的过程，避免多个批量提交各自占住一部分名额而互相等待
+	mu sync.Mutex
+}
+
+// NewBudget 创建一个容量为 size 的并发预算
+// size 必须为正数，否则返回 ErrInvalidBudgetSize
+func NewBudget(size int) (*Budget, error) {
+	if size <= 0 {
+		return nil, ErrInvalidBudgetSize
+	}
+	return &Budget{slots: make(chan struct{}, size)}, nil
+}
+
+// Cap 返回预算容量
+func (b *Budget) Cap() int {
+	return cap(b.slots)
+}
+
+// Running 返回当前占用预算的任务数量
+func (b *Budget) Running() int {
+	return len(b.slots)
+}
+
+// acquire 占用 n 个预算名额，失败时归还已占用的名额
+// 预算不足时，nonblocking 为 true 立即返回 ErrPoolOverload；否则等待，直到 ctx 被取消
+// （返回 ctx.Err()）或到达 deadline（返回 ErrTimeout），deadline 为零值时不超时。
+// n 超过预算容量时永远无法满足，返回 ErrPoolOverload。
+func (b *Budget) acquire(ctx context.Context, deadline time.Time, nonblocking bool, n int) error {
+	if n > cap(b.slots) {
+		return ErrPoolOverload
+	}
+	if n > 1 {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+	}
+
+	var timeout <-chan time.Time
+	for i := 0; i < n; i++ {
+		// 快速路径：有空闲名额时不创建定时器
+		select {
+		case b.slots <- struct{}{}:
+			continue
+		default:
+		}
+
+		err := ErrPoolOverload
+		if !nonblocking {
+			if timeout == nil && !deadline.IsZero() {
+				timer := time.NewTimer(time.Until(deadline))
+				defer timer.Stop()
+				timeout = timer.C
+			}
+			select {
+			case b.slots <- struct{}{}:
+				continue
+			case <-ctx.Done():
+				err = ctx.Err()
+			case <-timeout:
+				err = ErrTimeout
+			}
+		}
+		b.release(i)
+		return err
+	}
+	return nil
+}
+
+// release 归还 n 个预算名额
+func (b *Budget) release(n int) {
+	for i := 0; i < n; i++ {
+		<-b.slots
+	}
+}
@@ -0,0 +1,122 @@
+package laborer
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestBudget 测试多个池共享并发预算
+func TestBudget(t *testing.T) {
+	if _, err := NewBudget(0); err != ErrInvalidBudgetSize {
+		t.Errorf("期望返回 ErrInvalidBudgetSize，实际返回: %v", err)
+	}
+
+	budget, err := NewBudget(3)
+	if err != nil {
+		t.Fatalf("创建预算失败: %v", err)
+	}
+
+	var cur, peak int32
+	var wg sync.WaitGroup
+	work := func() {
+		n := atomic.AddInt32(&cur, 1)
+		for {
+			old := atomic.LoadInt32(&peak)
+			if n <= old || atomic.CompareAndSwapInt32(&peak, old, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		atomic.AddInt32(&cur, -1)
+		wg.Done()
+	}
+
+	pool, err := NewPool(5, WithBudget(budget))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	funcPool, err := NewPoolWithFunc(5, func(interface{}) { work() }, WithBudget(budget))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer funcPool.Release()
+
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		if err := pool.Submit(work); err != nil {
+			t.Fatalf("提交任务失败: %v", err)
+		}
+		if err := funcPool.Invoke(i); err != nil {
+			t.Fatalf("提交任务失败: %v", err)
+		}
+	}
+	wg.Wait()
+
+	if peak > 3 {
+		t.Errorf("期望同时执行的任务不超过 3 个，实际峰值 %d", peak)
+	}
+	if budget.Running() != 0 {
+		t.Errorf("期望任务结束后预算全部归还，实际占用 %d", budget.Running())
+	}
+}
+
+// TestBudgetPanic 测试任务 panic 后归还预算名额
+func TestBudgetPanic(t *testing.T) {
+	budget, _ := NewBudget(1)
+	pool, err := NewPool(2, WithBudget(budget), WithPanicHandler(func(interface{}) {}))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	f, _ := pool.SubmitWithResult(func() (interface{}, error) { panic("boom") })
+	f.Get()
+
+	done := make(chan struct{})
+	pool.Submit(func() { close(done) })
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("panic 后预算名额未归还")
+	}
+}
+
+// TestBudgetAdmission 测试预算在提交时占用，用尽时不占用 worker
+func TestBudgetAdmission(t *testing.T) {
+	budget, _ := NewBudget(1)
+	release := make(chan struct{})
+	defer close(release)
+
+	nonblocking, err := NewPool(2, WithBudget(budget), WithNonblocking(true))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer nonblocking.Release()
+
+	if err := nonblocking.Submit(func() { <-release }); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	if err := nonblocking.Submit(func() {}); err != ErrPoolOverload {
+		t.Errorf("期望预算用尽时返回 ErrPoolOverload，实际返回: %v", err)
+	}
+	if n := nonblocking.Running(); n != 1 {
+		t.Errorf("期望被拒绝的任务不占用 worker，实际运行 %d 个 worker", n)
+	}
+
+	blocking, err := NewPool(2, WithBudget(budget))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer blocking.Release()
+
+	if err := blocking.SubmitWithTimeout(func() {}, 20*time.Millisecond); err != ErrTimeout {
+		t.Errorf("期望等待预算超时返回 ErrTimeout，实际返回: %v", err)
+	}
+	if n := blocking.Running(); n != 0 {
+		t.Errorf("期望等待预算时不占用 worker，实际运行 %d 个 worker", n)
+	}
+}
@@ -0,0 +1,39 @@
+package laborer
+
+import "time"
+
+// Clock 池读取时间的时钟，通过 WithClock 设置
+//
+// 时钟只用于空闲 worker 的过期回收：worker 的创建和归还时间、
+// 清理 goroutine 的扫描周期和过期判断都从时钟读取，Workers() 的时长也按时钟计算。
+// 测试中替换为可控的时钟（例如 laborertest.FakeClock）后，
+// 推进时间即可触发过期回收，而不必真正等待。
+type Clock interface {
+	// Now 返回当前时间
+	Now() time.Time
+
+	// NewTicker 返回每隔 d 触发一次的 channel 和停止它的函数
+	NewTicker(d time.Duration) (<-chan time.Time, func())
+}
+
+// systemClock 使用系统时间的时钟
+type systemClock struct{}
+
+// Now 实现 Clock 接口
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// NewTicker 实现 Clock 接口
+func (systemClock) NewTicker(d time.Duration) (<-chan time.Time, func()) {
+	t := time.NewTicker(d)
+	return t.C, t.Stop
+}
+
+// clock 返回实际生效的时钟
+func (opts *Options) clock() Clock {
+	if opts.Clock != nil {
+		return opts.Clock
+	}
+	return systemClock{}
+}
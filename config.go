@@ -0,0 +1,251 @@
+package laborer
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config 可以从配置文件加载的池配置。
+//
+// 字段都是普通的值类型，带有 json 和 yaml 标签，服务可以直接把配置文件中的
+// 一段解码到 Config，而不必逐个映射到 With* 选项。零值字段使用默认配置。
+// 回调、Logger 等无法写在配置文件中的选项，通过 NewPoolFromConfig 的 options 追加。
+//
+// 示例:
+//
+//	// pool.json: {"size": 100, "expiry": "30s", "nonblocking": true, "queue_type": "loop"}
+//	var cfg laborer.Config
+//	if err := json.Unmarshal(data, &cfg); err != nil {
+//	    return err
+//	}
+//	pool, err := laborer.NewPoolFromConfig(cfg)
+type Config struct {
+	// Size 池的容量，-1 表示无限容量，0 表示按 CPU 数量换算
+	Size int `json:"size" yaml:"size"`
+
+	// SizeMultiplier 容量为 0 时 CPU 数量的倍数
+	SizeMultiplier int `json:"size_multiplier,omitempty" yaml:"size_multiplier,omitempty"`
+
+	// Name 池的名称
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+
+	// ExpiryDuration worker 的空闲超时时间，例如 "30s"
+	ExpiryDuration Duration `json:"expiry,omitempty" yaml:"expiry,omitempty"`
+
+	// CleanInterval 扫描过期 worker 的间隔，例如 "1s"
+	CleanInterval Duration `json:"clean_interval,omitempty" yaml:"clean_interval,omitempty"`
+
+	// Nonblocking 是否使用非阻塞模式
+	Nonblocking bool `json:"nonblocking,omitempty" yaml:"nonblocking,omitempty"`
+
+	// PreAlloc 是否预分配 worker 队列
+	PreAlloc bool `json:"prealloc,omitempty" yaml:"prealloc,omitempty"`
+
+	// DisablePurge 是否禁用过期 worker 的清理
+	DisablePurge bool `json:"disable_purge,omitempty" yaml:"disable_purge,omitempty"`
+
+	// MaxBlockingTasks 阻塞模式下同时等待 worker 的提交数上限，超出时提交返回 ErrPoolOverload，0 表示不限制
+	MaxBlockingTasks int `json:"max_blocking_tasks,omitempty" yaml:"max_blocking_tasks,omitempty"`
+
+	// QueueType 空闲 worker 队列的数据结构："auto"、"stack" 或 "loop"
+	QueueType QueueType `json:"queue_type,omitempty" yaml:"queue_type,omitempty"`
+
+	// QueueThreshold 自动选择队列类型时改用循环队列的容量阈值
+	QueueThreshold int `json:"queue_threshold,omitempty" yaml:"queue_threshold,omitempty"`
+
+	// SpilloverLimit 池饱和时最多允许在溢出 worker 上执行的任务数量
+	SpilloverLimit int `json:"spillover_limit,omitempty" yaml:"spillover_limit,omitempty"`
+}
+
+// Options 返回与配置对应的选项，零值字段不生成选项
+// 适合与 NewPoolWithFunc、NewMultiPool 等其他构造函数一起使用:
+//
+//	pool, err := laborer.NewPoolWithFunc(cfg.Size, handle, cfg.Options()...)
+func (cfg Config) Options() []Option {
+	var opts []Option
+	if cfg.Name != "" {
+		opts = append(opts, WithName(cfg.Name))
+	}
+	if cfg.SizeMultiplier != 0 {
+		opts = append(opts, WithSizeMultiplier(cfg.SizeMultiplier))
+	}
+	if cfg.ExpiryDuration != 0 {
+		opts = append(opts, WithExpiryDuration(time.Duration(cfg.ExpiryDuration)))
+	}
+	if cfg.CleanInterval != 0 {
+		opts = append(opts, WithCleanInterval(time.Duration(cfg.CleanInterval)))
+	}
+	if cfg.Nonblocking {
+		opts = append(opts, WithNonblocking(true))
+	}
+	if cfg.PreAlloc {
+		opts = append(opts, WithPreAlloc(true))
+	}
+	if cfg.DisablePurge {
+		opts = append(opts, WithDisablePurge(true))
+	}
+	if cfg.MaxBlockingTasks != 0 {
+		opts = append(opts, WithMaxBlockingTasks(cfg.MaxBlockingTasks))
+	}
+	if cfg.QueueType != AutoQueue {
+		opts = append(opts, WithQueueType(cfg.QueueType))
+	}
+	if cfg.QueueThreshold != 0 {
+		opts = append(opts, WithQueueThreshold(cfg.QueueThreshold))
+	}
+	if cfg.SpilloverLimit != 0 {
+		opts = append(opts, WithSpillover(cfg.SpilloverLimit))
+	}
+	return opts
+}
+
+// NewPoolFromConfig 按配置创建一个新的 goroutine 池
+// cfg: 池的配置，通常从配置文件解码得到
+// options: 追加的配置选项，在 cfg 之后应用，可以覆盖 cfg 中的值
+func NewPoolFromConfig(cfg Config, options ...Option) (*Pool, error) {
+	return NewPool(cfg.Size, append(cfg.Options(), options...)...)
+}
+
+// ConfigFromEnv 用环境变量覆盖 defaults 中的配置，未设置的变量保留 defaults 中的值
+//
+// 容器部署时不必重新编译即可调整池的配置。这是一个显式调用的辅助函数，
+// 构造函数本身从不读取环境变量。支持的变量:
+//
+//	LABORER_POOL_SIZE           容量，-1 表示无限容量，0 表示按 CPU 数量换算
+//	LABORER_SIZE_MULTIPLIER     容量为 0 时 CPU 数量的倍数
+//	LABORER_NAME                池的名称
+//	LABORER_EXPIRY              空闲超时时间，例如 30s
+//	LABORER_CLEAN_INTERVAL      扫描过期 worker 的间隔
+//	LABORER_NONBLOCKING         是否使用非阻塞模式，例如 true
+//	LABORER_PREALLOC            是否预分配 worker 队列
+//	LABORER_DISABLE_PURGE       是否禁用过期 worker 的清理
+//	LABORER_MAX_BLOCKING_TASKS  等待 worker 的提交数上限，0 表示不限制
+//	LABORER_QUEUE_TYPE          auto、stack 或 loop
+//	LABORER_QUEUE_THRESHOLD     自动选择队列类型的容量阈值
+//	LABORER_SPILLOVER_LIMIT     溢出 worker 的上限
+//
+// 变量的值无法解析时返回包装了 ErrInvalidOption 的错误，其中包含变量名。
+//
+// 示例:
+//
+//	cfg, err := laborer.ConfigFromEnv(laborer.Config{Size: 100})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	pool, err := laborer.NewPoolFromConfig(cfg)
+func ConfigFromEnv(defaults Config) (Config, error) {
+	cfg := defaults
+	vars := []struct {
+		name  string
+		parse func(v string) error
+	}{
+		{"LABORER_POOL_SIZE", intVar(&cfg.Size)},
+		{"LABORER_SIZE_MULTIPLIER", intVar(&cfg.SizeMultiplier)},
+		{"LABORER_NAME", func(v string) error { cfg.Name = v; return nil }},
+		{"LABORER_EXPIRY", cfg.ExpiryDuration.set},
+		{"LABORER_CLEAN_INTERVAL", cfg.CleanInterval.set},
+		{"LABORER_NONBLOCKING", boolVar(&cfg.Nonblocking)},
+		{"LABORER_PREALLOC", boolVar(&cfg.PreAlloc)},
+		{"LABORER_DISABLE_PURGE", boolVar(&cfg.DisablePurge)},
+		{"LABORER_MAX_BLOCKING_TASKS", intVar(&cfg.MaxBlockingTasks)},
+		{"LABORER_QUEUE_TYPE", func(v string) error { return cfg.QueueType.UnmarshalText([]byte(v)) }},
+		{"LABORER_QUEUE_THRESHOLD", intVar(&cfg.QueueThreshold)},
+		{"LABORER_SPILLOVER_LIMIT", intVar(&cfg.SpilloverLimit)},
+	}
+
+	for _, ev := range vars {
+		v, ok := os.LookupEnv(ev.name)
+		if !ok {
+			continue
+		}
+		if err := ev.parse(strings.TrimSpace(v)); err != nil {
+			return defaults, fmt.Errorf("%s: %w", ev.name, err)
+		}
+	}
+	return cfg, nil
+}
+
+// intVar 返回将环境变量的值解析为整数并写入 dst 的函数
+func intVar(dst *int) func(v string) error {
+	return func(v string) error {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidOption, err)
+		}
+		*dst = n
+		return nil
+	}
+}
+
+// boolVar 返回将环境变量的值解析为布尔值并写入 dst 的函数
+func boolVar(dst *bool) func(v string) error {
+	return func(v string) error {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidOption, err)
+		}
+		*dst = b
+		return nil
+	}
+}
+
+// Duration 可以从配置文件中以 "30s"、"1m30s" 等字符串读取的时间间隔
+type Duration time.Duration
+
+// MarshalText 实现 encoding.TextMarshaler，输出 time.Duration 的字符串形式
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// UnmarshalText 实现 encoding.TextUnmarshaler，按 time.ParseDuration 解析
+func (d *Duration) UnmarshalText(text []byte) error {
+	return d.set(string(text))
+}
+
+// set 按 time.ParseDuration 解析 s
+func (d *Duration) set(s string) error {
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidOption, err)
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// queueTypeNames 队列类型在配置文件中的名称
+var queueTypeNames = map[QueueType]string{
+	AutoQueue: "auto",
+	Stack:     "stack",
+	LoopQueue: "loop",
+}
+
+// String 返回队列类型的名称
+func (t QueueType) String() string {
+	if name, ok := queueTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("QueueType(%d)", int(t))
+}
+
+// MarshalText 实现 encoding.TextMarshaler
+func (t QueueType) MarshalText() ([]byte, error) {
+	if !t.valid() {
+		return nil, invalidOption("unknown queue type: %d", t)
+	}
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText 实现 encoding.TextUnmarshaler，接受 "auto"、"stack" 和 "loop"，不区分大小写
+func (t *QueueType) UnmarshalText(text []byte) error {
+	name := strings.ToLower(string(text))
+	for qt, n := range queueTypeNames {
+		if n == name {
+			*t = qt
+			return nil
+		}
+	}
+	return invalidOption("unknown queue type: %q", text)
+}
@@ -0,0 +1,138 @@
+package laborer
+
+import (
+	"encoding/json"
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestNewPoolFromConfig 测试从 JSON 配置创建池
+func TestNewPoolFromConfig(t *testing.T) {
+	data := []byte(`{
+		"size": 50,
+		"name": "from-config",
+		"expiry": "30s",
+		"nonblocking": true,
+		"prealloc": true,
+		"max_blocking_tasks": 64,
+		"queue_type": "loop"
+	}`)
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		t.Fatalf("解码配置失败: %v", err)
+	}
+
+	pool, err := NewPoolFromConfig(cfg, WithSpillover(3))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	opts := pool.Options()
+	if pool.Cap() != 50 || opts.Name != "from-config" || opts.ExpiryDuration != 30*time.Second {
+		t.Errorf("配置未生效: cap=%d %+v", pool.Cap(), opts)
+	}
+	if !opts.Nonblocking || !opts.PreAlloc || opts.MaxBlockingTasks != 64 || opts.QueueType != LoopQueue || opts.SpilloverLimit != 3 {
+		t.Errorf("配置未生效: %+v", opts)
+	}
+	if _, ok := pool.workers.(*loopQueue); !ok {
+		t.Errorf("期望使用循环队列，实际 %T", pool.workers)
+	}
+
+	// 零值配置使用默认值
+	def, err := NewPoolFromConfig(Config{Size: 4})
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer def.Release()
+	if def.Options().ExpiryDuration != DefaultExpiryDuration {
+		t.Errorf("期望默认过期时间，实际 %v", def.Options().ExpiryDuration)
+	}
+
+	// 容量为 0 时按 CPU 数量换算
+	auto, err := NewPoolFromConfig(Config{SizeMultiplier: 2})
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer auto.Release()
+	if want := runtime.GOMAXPROCS(0) * 2; auto.Cap() != want {
+		t.Errorf("期望容量为 %d，实际 %d", want, auto.Cap())
+	}
+
+	// 编码后可以再次解码
+	out, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("编码配置失败: %v", err)
+	}
+	var again Config
+	if err := json.Unmarshal(out, &again); err != nil || again != cfg {
+		t.Errorf("编码后解码不一致: %s %v", out, err)
+	}
+}
+
+// TestConfigInvalid 测试无效的配置值
+func TestConfigInvalid(t *testing.T) {
+	for _, data := range []string{
+		`{"size": 10, "expiry": "soon"}`,
+		`{"size": 10, "queue_type": "heap"}`,
+	} {
+		var cfg Config
+		if err := json.Unmarshal([]byte(data), &cfg); !errors.Is(err, ErrInvalidOption) {
+			t.Errorf("%s: 期望返回 ErrInvalidOption，实际返回: %v", data, err)
+		}
+	}
+
+	if _, err := NewPoolFromConfig(Config{Size: -2}); err != ErrInvalidPoolSize {
+		t.Errorf("期望返回 ErrInvalidPoolSize，实际返回: %v", err)
+	}
+}
+
+// TestConfigFromEnv 测试用环境变量覆盖默认配置
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("LABORER_POOL_SIZE", "32")
+	t.Setenv("LABORER_EXPIRY", "2m")
+	t.Setenv("LABORER_NONBLOCKING", "true")
+	t.Setenv("LABORER_MAX_BLOCKING_TASKS", "16")
+	t.Setenv("LABORER_QUEUE_TYPE", "stack")
+
+	cfg, err := ConfigFromEnv(Config{Size: 8, Name: "env", PreAlloc: true})
+	if err != nil {
+		t.Fatalf("读取环境变量失败: %v", err)
+	}
+	want := Config{
+		Size:             32,
+		Name:             "env",
+		ExpiryDuration:   Duration(2 * time.Minute),
+		Nonblocking:      true,
+		PreAlloc:         true,
+		MaxBlockingTasks: 16,
+		QueueType:        Stack,
+	}
+	if cfg != want {
+		t.Errorf("期望 %+v，实际 %+v", want, cfg)
+	}
+
+	pool, err := NewPoolFromConfig(cfg)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+	if pool.Cap() != 32 || !pool.Options().Nonblocking || pool.Options().MaxBlockingTasks != 16 {
+		t.Errorf("环境变量未生效: cap=%d %+v", pool.Cap(), pool.Options())
+	}
+
+	// 无法解析的值返回包含变量名的错误，并保留默认配置
+	t.Setenv("LABORER_POOL_SIZE", "many")
+	defaults := Config{Size: 8}
+	cfg, err = ConfigFromEnv(defaults)
+	if !errors.Is(err, ErrInvalidOption) || !strings.Contains(err.Error(), "LABORER_POOL_SIZE") {
+		t.Errorf("期望返回包含变量名的 ErrInvalidOption，实际返回: %v", err)
+	}
+	if cfg != defaults {
+		t.Errorf("出错时应该返回默认配置，实际 %+v", cfg)
+	}
+}
@@ -0,0 +1,54 @@
+package laborer
+
+import (
+	"context"
+	"sync"
+)
+
+// Consume 从 in 中持续读取元素，并在池中以有界并发调用 fn 处理。
+//
+// 并发度由池的容量决定：池满时（阻塞模式下）读取会暂停，直到有 worker 空闲，
+// 暂停期间 ctx 被取消也会立即返回。
+// 当 in 被关闭或 ctx 被取消时停止读取，并等待所有已提交的元素处理完成后返回。
+//
+// 参数:
+//   - ctx: 用于提前停止读取的上下文
+//   - pool: 执行 fn 的池
+//   - in: 输入 channel
+//   - fn: 处理单个元素的函数
+//
+// 返回:
+//   - error: in 被关闭时返回 nil；ctx 被取消时返回 ctx.Err()；
+//     提交失败（如 ErrPoolClosed、ErrPoolOverload）时返回该错误，
+//     此时导致失败的元素不会被处理
+//
+// 示例:
+//
+//	err := laborer.Consume(ctx, pool, jobs, func(job Job) {
+//	    process(job)
+//	})
+func Consume[T any](ctx context.Context, pool *Pool, in <-chan T, fn func(T)) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case item, ok := <-in:
+			if !ok {
+				return nil
+			}
+
+			wg.Add(1)
+			err := pool.SubmitContext(ctx, func() {
+				defer wg.Done()
+				fn(item)
+			})
+			if err != nil {
+				wg.Done()
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package laborer
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestConsume 测试从 channel 读取并处理所有元素
+func TestConsume(t *testing.T) {
+	pool, err := NewPool(3)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	in := make(chan int)
+	go func() {
+		for i := 1; i <= 100; i++ {
+			in <- i
+		}
+		close(in)
+	}()
+
+	var sum, active, maxActive int64
+	err = Consume(context.Background(), pool, in, func(n int) {
+		cur := atomic.AddInt64(&active, 1)
+		for {
+			old := atomic.LoadInt64(&maxActive)
+			if cur <= old || atomic.CompareAndSwapInt64(&maxActive, old, cur) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		atomic.AddInt64(&sum, int64(n))
+		atomic.AddInt64(&active, -1)
+	})
+	if err != nil {
+		t.Fatalf("Consume 返回错误: %v", err)
+	}
+
+	// Consume 返回时所有元素应该已处理完成
+	if got := atomic.LoadInt64(&sum); got != 5050 {
+		t.Errorf("期望总和为 5050，实际为 %d", got)
+	}
+	if m := atomic.LoadInt64(&maxActive); m > 3 {
+		t.Errorf("并发度 %d 超过了池容量 3", m)
+	}
+}
+
+// TestConsumeCancel 测试取消 ctx 后停止读取
+func TestConsumeCancel(t *testing.T) {
+	pool, err := NewPool(2)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	in := make(chan int)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var processed int32
+	done := make(chan error, 1)
+	go func() {
+		done <- Consume(ctx, pool, in, func(int) {
+			time.Sleep(20 * time.Millisecond)
+			atomic.AddInt32(&processed, 1)
+		})
+	}()
+
+	in <- 1
+	in <- 2
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Errorf("期望返回 context.Canceled，实际返回: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("取消后 Consume 没有返回")
+	}
+
+	if n := atomic.LoadInt32(&processed); n != 2 {
+		t.Errorf("返回前应该等待已提交的 2 个元素完成，实际完成 %d 个", n)
+	}
+}
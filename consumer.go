@@ -0,0 +1,199 @@
+package laborer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultConsumerMinBackoff Consumer 在池过载时第一次重试前的默认等待时间
+	DefaultConsumerMinBackoff = 10 * time.Millisecond
+
+	// DefaultConsumerMaxBackoff Consumer 在池过载时重试间隔的默认上限
+	DefaultConsumerMaxBackoff = time.Second
+)
+
+// Consumer 将 channel 中的元素持续提交到池中处理，是生产者与池之间的桥梁
+//
+// 与 Consume 相比，Consumer 在后台读取，非阻塞模式的池过载时按指数退避重试
+// 同一个元素而不是返回错误，并且可以通过 Stop 优雅地停止：不再读取新的元素，
+// 已读取的元素处理完成后才返回。
+//
+// 示例:
+//
+//	c := laborer.NewConsumer(pool, func(msg Message) {
+//	    handle(msg)
+//	})
+//	c.SetBackoff(50*time.Millisecond, 5*time.Second)
+//	if err := c.Start(ctx, messages); err != nil {
+//	    return err
+//	}
+//	...
+//	// 关闭时停止读取并等待已读取的消息处理完成
+//	if err := c.Stop(); err != nil {
+//	    log.Printf("consumer: %v", err)
+//	}
+type Consumer[T any] struct {
+	pool    *Pool
+	handler func(T)
+
+	mu         sync.Mutex
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	started    bool
+
+	// stop Stop 关闭此 channel 以停止读取
+	stop     chan struct{}
+	stopOnce sync.Once
+
+	// done 读取结束且已提交的元素全部处理完成后关闭，之后 err 不再改变
+	done chan struct{}
+	err  error
+
+	// inflight 已提交尚未处理完成的元素
+	inflight sync.WaitGroup
+}
+
+// NewConsumer 创建在 pool 中以 handler 处理元素的 Consumer
+// 退避时间默认为 DefaultConsumerMinBackoff 到 DefaultConsumerMaxBackoff。
+func NewConsumer[T any](pool *Pool, handler func(T)) *Consumer[T] {
+	return &Consumer[T]{
+		pool:       pool,
+		handler:    handler,
+		minBackoff: DefaultConsumerMinBackoff,
+		maxBackoff: DefaultConsumerMaxBackoff,
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+}
+
+// SetBackoff 设置池过载时重试的退避时间，必须在 Start 之前调用
+// 第一次重试前等待 min，之后每次加倍，最多等待 max。
+// min 小于等于 0 时不重试，过载时 Consumer 以 ErrPoolOverload 结束。
+func (c *Consumer[T]) SetBackoff(min, max time.Duration) {
+	if max < min {
+		max = min
+	}
+
+	c.mu.Lock()
+	c.minBackoff = min
+	c.maxBackoff = max
+	c.mu.Unlock()
+}
+
+// Start 在后台开始从 in 中读取元素并提交到池中，立即返回
+//
+// 读取在以下情况下结束，之后 Wait 返回:
+//   - in 被关闭或调用了 Stop: 结果为 nil
+//   - ctx 被取消: 结果为 ctx.Err()
+//   - 提交失败（例如 ErrPoolClosed，或不重试时的 ErrPoolOverload）: 结果为该错误，
+//     导致失败的元素不会被处理
+//
+// 每个 Consumer 只能启动一次，再次调用返回 ErrConsumerStarted。
+func (c *Consumer[T]) Start(ctx context.Context, in <-chan T) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.started {
+		return ErrConsumerStarted
+	}
+	c.started = true
+
+	go func() {
+		err := c.run(ctx, in)
+		c.inflight.Wait()
+		c.err = err
+		close(c.done)
+	}()
+	return nil
+}
+
+// Stop 停止读取新的元素，等待已读取的元素处理完成后返回 Consumer 的结果
+// 已从 in 读取的元素仍会被提交，即使池过载也会继续重试，直到 Start 的 ctx 被取消。
+// 尚未启动的 Consumer 调用 Stop 后，Start 启动的读取会立即结束。
+func (c *Consumer[T]) Stop() error {
+	c.stopOnce.Do(func() { close(c.stop) })
+
+	c.mu.Lock()
+	started := c.started
+	c.mu.Unlock()
+	if !started {
+		return nil
+	}
+	return c.Wait()
+}
+
+// Wait 等待读取结束且已提交的元素全部处理完成，返回 Consumer 的结果
+func (c *Consumer[T]) Wait() error {
+	<-c.done
+	return c.err
+}
+
+// Done 返回在 Consumer 结束后关闭的 channel
+func (c *Consumer[T]) Done() <-chan struct{} {
+	return c.done
+}
+
+// run 读取并提交元素直到 in 被关闭、Stop、ctx 被取消或提交失败
+func (c *Consumer[T]) run(ctx context.Context, in <-chan T) error {
+	for {
+		// 优先响应停止，避免 in 中一直有元素时无法停止
+		select {
+		case <-c.stop:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		select {
+		case <-c.stop:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		case item, ok := <-in:
+			if !ok {
+				return nil
+			}
+			if err := c.submit(ctx, item); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// submit 将元素提交到池中，池过载时按退避时间重试
+func (c *Consumer[T]) submit(ctx context.Context, item T) error {
+	c.mu.Lock()
+	backoff, max := c.minBackoff, c.maxBackoff
+	c.mu.Unlock()
+
+	for {
+		c.inflight.Add(1)
+		err := c.pool.SubmitContext(ctx, func() {
+			defer c.inflight.Done()
+			c.handler(item)
+		})
+		if err == nil {
+			return nil
+		}
+		c.inflight.Done()
+
+		if !errors.Is(err, ErrPoolOverload) || backoff <= 0 {
+			return err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		if backoff *= 2; backoff > max {
+			backoff = max
+		}
+	}
+}
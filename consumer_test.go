@@ -0,0 +1,120 @@
+package laborer
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestConsumerOverloadBackoff 测试非阻塞池过载时 Consumer 退避重试而不丢失元素
+func TestConsumerOverloadBackoff(t *testing.T) {
+	pool, err := NewPool(1, WithNonblocking(true))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	in := make(chan int, 20)
+	for i := 1; i <= 20; i++ {
+		in <- i
+	}
+	close(in)
+
+	var sum int64
+	c := NewConsumer(pool, func(n int) {
+		time.Sleep(time.Millisecond)
+		atomic.AddInt64(&sum, int64(n))
+	})
+	c.SetBackoff(time.Millisecond, 5*time.Millisecond)
+	if err := c.Start(context.Background(), in); err != nil {
+		t.Fatalf("启动失败: %v", err)
+	}
+	if err := c.Start(context.Background(), in); !errors.Is(err, ErrConsumerStarted) {
+		t.Errorf("再次启动应该返回 ErrConsumerStarted，实际 %v", err)
+	}
+
+	if err := c.Wait(); err != nil {
+		t.Fatalf("Consumer 返回错误: %v", err)
+	}
+	if got := atomic.LoadInt64(&sum); got != 210 {
+		t.Errorf("期望总和为 210，实际为 %d", got)
+	}
+	if pool.Stats().Rejected == 0 {
+		t.Error("容量为 1 的非阻塞池应该拒绝过部分提交")
+	}
+}
+
+// TestConsumerNoRetry 测试关闭重试后过载时以 ErrPoolOverload 结束
+func TestConsumerNoRetry(t *testing.T) {
+	pool, err := NewPool(1, WithNonblocking(true))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	block := make(chan struct{})
+	defer close(block)
+	in := make(chan int, 2)
+	in <- 1
+	in <- 2
+
+	c := NewConsumer(pool, func(int) { <-block })
+	c.SetBackoff(0, 0)
+	_ = c.Start(context.Background(), in)
+
+	select {
+	case <-c.Done():
+		t.Fatal("已提交的元素处理完成前 Consumer 不应该结束")
+	case <-time.After(20 * time.Millisecond):
+	}
+	block <- struct{}{}
+	if err := c.Wait(); !errors.Is(err, ErrPoolOverload) {
+		t.Errorf("期望返回 ErrPoolOverload，实际 %v", err)
+	}
+}
+
+// TestConsumerStop 测试 Stop 停止读取并等待已读取的元素处理完成
+func TestConsumerStop(t *testing.T) {
+	pool, err := NewPool(2)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	in := make(chan int)
+	started := make(chan struct{})
+	var processed int64
+	c := NewConsumer(pool, func(n int) {
+		close(started)
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt64(&processed, 1)
+	})
+	_ = c.Start(context.Background(), in)
+
+	in <- 1
+	<-started
+	if err := c.Stop(); err != nil {
+		t.Fatalf("Stop 返回错误: %v", err)
+	}
+	if atomic.LoadInt64(&processed) != 1 {
+		t.Error("Stop 返回前已读取的元素应该处理完成")
+	}
+
+	// 停止后不再读取
+	select {
+	case in <- 2:
+		t.Error("Stop 后不应该再读取元素")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	// ctx 被取消时返回 ctx.Err()
+	ctx, cancel := context.WithCancel(context.Background())
+	c2 := NewConsumer(pool, func(int) {})
+	_ = c2.Start(ctx, in)
+	cancel()
+	if err := c2.Wait(); !errors.Is(err, context.Canceled) {
+		t.Errorf("期望返回 context.Canceled，实际 %v", err)
+	}
+}
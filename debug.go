@@ -0,0 +1,229 @@
+package laborer
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// recentPanicsCap 每个池保留的最近 panic 记录数量
+const recentPanicsCap = 16
+
+// PanicRecord 表示一次任务 panic 的记录。
+type PanicRecord struct {
+	// Time panic 发生的时间
+	Time time.Time `json:"time"`
+
+	// WorkerID 发生 panic 的 worker 的编号
+	WorkerID int `json:"worker_id"`
+
+	// Task 发生 panic 的任务的名称，任务没有名称时为空
+	Task string `json:"task,omitempty"`
+
+	// Value panic 的值（格式化后的字符串）
+	Value string `json:"value"`
+
+	// Stack panic 发生时的 goroutine 栈
+	Stack string `json:"stack"`
+}
+
+// WorkerInfo 表示一个空闲 worker 的状态。
+type WorkerInfo struct {
+	// ID worker 在池内的编号，在 worker 的整个生命周期内不变
+	ID int `json:"id"`
+
+	// Age worker 自创建以来的时长
+	Age time.Duration `json:"age"`
+
+	// IdleFor worker 自上次执行任务以来的空闲时长
+	IdleFor time.Duration `json:"idle_for"`
+}
+
+// newWorkerInfo 创建一个 worker 在 now 时的状态
+// 启用分片锁时 worker 不持有池的锁就能归还到空闲缓存，最后使用时间可能晚于 now，此时空闲时长按 0 处理。
+func newWorkerInfo(id int, created, idleSince, now time.Time) WorkerInfo {
+	idle := now.Sub(idleSince)
+	if idle < 0 {
+		idle = 0
+	}
+	return WorkerInfo{ID: id, Age: now.Sub(created), IdleFor: idle}
+}
+
+// panicLog 是一个固定容量的环形缓冲区，保存最近的 panic 记录
+type panicLog struct {
+	mu      sync.Mutex
+	records [recentPanicsCap]PanicRecord
+	next    int
+	full    bool
+}
+
+// record 追加一条 panic 记录，超出容量时覆盖最旧的记录
+func (l *panicLog) record(workerID int, task string, value interface{}, stack []byte) {
+	l.mu.Lock()
+	l.records[l.next] = PanicRecord{
+		Time:     time.Now(),
+		WorkerID: workerID,
+		Task:     task,
+		Value:    fmt.Sprint(value),
+		Stack:    string(stack),
+	}
+	l.next++
+	if l.next == recentPanicsCap {
+		l.next = 0
+		l.full = true
+	}
+	l.mu.Unlock()
+}
+
+// snapshot 按时间从旧到新返回所有记录
+func (l *panicLog) snapshot() []PanicRecord {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.full {
+		out := make([]PanicRecord, l.next)
+		copy(out, l.records[:l.next])
+		return out
+	}
+
+	out := make([]PanicRecord, 0, recentPanicsCap)
+	out = append(out, l.records[l.next:]...)
+	out = append(out, l.records[:l.next]...)
+	return out
+}
+
+// Inspectable 定义可以被 DebugHandler 展示的池
+//
+// Pool 和 PoolWithFunc 都实现了此接口。
+type Inspectable interface {
+	// Name 返回池的名称
+	Name() string
+
+	// Stats 返回池的运行状态快照
+	Stats() Stats
+
+	// RecentPanics 返回最近发生的 panic 记录
+	RecentPanics() []PanicRecord
+
+	// Workers 返回空闲 worker 的状态
+	Workers() []WorkerInfo
+}
+
+// Observable 定义池的完整可观测性接口
+//
+// Pool 和 PoolWithFunc 都实现了此接口，拥有相同的计数器、延迟直方图、
+// 任务钩子和 worker 钩子，监控代码可以统一处理两种池。
+//
+// 示例:
+//
+//	func watch(pools ...laborer.Observable) {
+//	    for _, p := range pools {
+//	        s := p.Stats()
+//	        log.Printf("%s: running=%d p99=%v", p.Name(), s.Running, s.Execution.P99)
+//	    }
+//	}
+type Observable interface {
+	Inspectable
+
+	// Running 返回正在运行的 worker 数量
+	Running() int
+
+	// Free 返回空闲的 worker 数量
+	Free() int
+
+	// Cap 返回池容量
+	Cap() int
+
+	// Waiting 返回等待执行的任务数量
+	Waiting() int
+
+	// SubscribeStats 订阅池的状态快照
+	SubscribeStats(interval time.Duration) (<-chan Stats, func())
+
+	// DumpStacks 返回所有正在执行任务的 worker 的 goroutine 栈
+	DumpStacks() []WorkerStack
+}
+
+// poolDebugInfo 是 DebugHandler 输出的单个池的信息
+type poolDebugInfo struct {
+	Name         string        `json:"name"`
+	Stats        Stats         `json:"stats"`
+	RecentPanics []PanicRecord `json:"recent_panics"`
+	Workers      []WorkerInfo  `json:"workers"`
+}
+
+// DebugHandler 返回展示池状态的 http.Handler。
+//
+// 默认输出 JSON；当请求带有 ?format=html 或 Accept 头包含 text/html 时输出 HTML 页面。
+// 输出内容包括每个池的状态快照、最近的 panic 记录和空闲 worker 的存活时长，
+// 便于在生产环境快速排查问题。未指定池时展示全局注册表中的所有池（见 Pools）。
+//
+// 示例:
+//
+//	http.Handle("/debug/laborer", laborer.DebugHandler())
+func DebugHandler(pools ...Inspectable) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		targets := pools
+		if len(targets) == 0 {
+			targets = Pools()
+		}
+
+		infos := make([]poolDebugInfo, len(targets))
+		for i, p := range targets {
+			name := p.Name()
+			if name == "" {
+				name = fmt.Sprintf("pool-%d", i)
+			}
+			infos[i] = poolDebugInfo{
+				Name:         name,
+				Stats:        p.Stats(),
+				RecentPanics: p.RecentPanics(),
+				Workers:      p.Workers(),
+			}
+		}
+
+		if r.URL.Query().Get("format") == "html" || strings.Contains(r.Header.Get("Accept"), "text/html") {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			if err := debugTemplate.Execute(w, infos); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(infos); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// debugTemplate DebugHandler 的 HTML 模板
+var debugTemplate = template.Must(template.New("debug").Parse(`<!DOCTYPE html>
+<html>
+<head><title>laborer</title></head>
+<body>
+{{range .}}
+<h2>{{.Name}}</h2>
+<table border="1">
+<tr><th>Running</th><th>Free</th><th>Cap</th><th>Waiting</th><th>Submitted</th><th>Completed</th><th>Rejected</th><th>Failed</th><th>Panicked</th></tr>
+<tr><td>{{.Stats.Running}}</td><td>{{.Stats.Free}}</td><td>{{.Stats.Cap}}</td><td>{{.Stats.Waiting}}</td><td>{{.Stats.Submitted}}</td><td>{{.Stats.Completed}}</td><td>{{.Stats.Rejected}}</td><td>{{.Stats.Failed}}</td><td>{{.Stats.Panicked}}</td></tr>
+</table>
+<h3>Idle workers ({{len .Workers}})</h3>
+<table border="1">
+<tr><th>ID</th><th>Age</th><th>Idle for</th></tr>
+{{range .Workers}}<tr><td>{{.ID}}</td><td>{{.Age}}</td><td>{{.IdleFor}}</td></tr>
+{{end}}</table>
+<h3>Recent panics ({{len .RecentPanics}})</h3>
+{{range .RecentPanics}}<p>{{.Time}} worker {{.WorkerID}}: {{.Value}}</p>
+<pre>{{.Stack}}</pre>
+{{end}}
+{{end}}
+</body>
+</html>
+`))
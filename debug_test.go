@@ -0,0 +1,107 @@
+package laborer
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestDebugHandler 测试调试 HTTP 处理器
+func TestDebugHandler(t *testing.T) {
+	pool, err := NewPool(2, WithPanicHandler(func(interface{}) {}))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	_ = pool.Submit(func() { panic("boom") })
+	_ = pool.Submit(func() {})
+	time.Sleep(20 * time.Millisecond)
+
+	h := DebugHandler(pool)
+
+	// JSON 输出
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/debug/laborer", nil))
+
+	var infos []poolDebugInfo
+	if err := json.Unmarshal(rec.Body.Bytes(), &infos); err != nil {
+		t.Fatalf("解析 JSON 失败: %v", err)
+	}
+	if len(infos) != 1 {
+		t.Fatalf("期望 1 个池，实际 %d", len(infos))
+	}
+	if len(infos[0].RecentPanics) != 1 || infos[0].RecentPanics[0].Value != "boom" {
+		t.Errorf("panic 记录不正确: %+v", infos[0].RecentPanics)
+	}
+	if !strings.Contains(infos[0].RecentPanics[0].Stack, "TestDebugHandler") {
+		t.Error("panic 记录应该包含任务的栈")
+	}
+	if len(infos[0].Workers) != 1 {
+		t.Errorf("期望 1 个空闲 worker，实际 %d", len(infos[0].Workers))
+	}
+
+	// HTML 输出
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/debug/laborer?format=html", nil))
+	if !strings.Contains(rec.Header().Get("Content-Type"), "text/html") {
+		t.Errorf("期望 HTML 输出，实际 Content-Type: %s", rec.Header().Get("Content-Type"))
+	}
+}
+
+// TestPanicLogWrap 测试 panic 记录的环形缓冲
+func TestPanicLogWrap(t *testing.T) {
+	var l panicLog
+	for i := 0; i < recentPanicsCap+3; i++ {
+		l.record(i, "", i, nil)
+	}
+
+	records := l.snapshot()
+	if len(records) != recentPanicsCap {
+		t.Fatalf("期望 %d 条记录，实际 %d", recentPanicsCap, len(records))
+	}
+	if records[0].Value != "3" || records[0].WorkerID != 3 || records[recentPanicsCap-1].Value != "18" {
+		t.Errorf("记录顺序不正确: 首条 %s，末条 %s", records[0].Value, records[recentPanicsCap-1].Value)
+	}
+}
+
+// TestWorkerIDs 测试 worker 编号在钩子、任务元数据、panic 记录和 Workers 中保持一致
+func TestWorkerIDs(t *testing.T) {
+	created := make(chan int, 1)
+	completed := make(chan TaskInfo, 1)
+	pool, err := NewPool(1,
+		WithWorkerHooks(func(id int) { created <- id }, nil, nil),
+		WithTaskHooks(nil, func(info TaskInfo) { completed <- info }),
+		WithPanicHandler(func(interface{}) {}),
+	)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	if err := pool.Submit(func() {}); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	id := <-created
+	if info := <-completed; info.WorkerID != id {
+		t.Errorf("任务元数据中的 worker 编号 %d 与创建钩子中的 %d 不一致", info.WorkerID, id)
+	}
+	waitFor(t, func() bool { return len(pool.Workers()) == 1 })
+	if w := pool.Workers()[0]; w.ID != id {
+		t.Errorf("Workers 中的编号 %d 与创建钩子中的 %d 不一致", w.ID, id)
+	}
+
+	// 复用同一个 worker 时编号不变，panic 记录带有编号
+	if err := pool.Submit(func() { panic("boom") }); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	if info := <-completed; info.WorkerID != id {
+		t.Errorf("复用的 worker 编号应该不变，期望 %d，实际 %d", id, info.WorkerID)
+	}
+	waitFor(t, func() bool { return len(pool.RecentPanics()) == 1 })
+	if r := pool.RecentPanics()[0]; r.WorkerID != id {
+		t.Errorf("panic 记录中的编号 %d 与 worker 编号 %d 不一致", r.WorkerID, id)
+	}
+}
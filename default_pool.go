@@ -0,0 +1,57 @@
+package laborer
+
+import (
+	"math"
+	"sync"
+)
+
+const (
+	// DefaultPoolSize 默认池的初始容量
+	DefaultPoolSize = math.MaxInt32
+
+	// DefaultPoolName 默认池的名称，用于在监控和日志中识别
+	DefaultPoolName = "default"
+)
+
+var (
+	// defaultPool 进程内共享的默认池，第一次使用时创建
+	defaultPool     *Pool
+	defaultPoolOnce sync.Once
+)
+
+// DefaultPool 返回进程内共享的默认池，第一次调用时创建
+//
+// 默认池供不便自行管理池生命周期的库使用（例如 antscompat 的包级函数），
+// 宿主程序可以通过返回的池观察库的负载（Stats、Events、SubscribeStats），
+// 并通过 SetDefaultCapacity 调整容量。默认池不会被自动关闭。
+//
+// 初始容量为 DefaultPoolSize，名称为 DefaultPoolName，使用栈作为 worker 队列，
+// 大容量不会一次性分配内存。
+//
+// 示例:
+//
+//	laborer.SetDefaultCapacity(runtime.GOMAXPROCS(0) * 4)
+//	stats := laborer.DefaultPool().Stats()
+func DefaultPool() *Pool {
+	defaultPoolOnce.Do(func() {
+		defaultPool, _ = NewPool(DefaultPoolSize, WithName(DefaultPoolName), WithQueueType(Stack))
+	})
+	return defaultPool
+}
+
+// SetDefaultCapacity 调整默认池的容量
+// 与 Tune 的语义相同：缩容不打断正在执行的任务，扩容唤醒阻塞的提交者。
+// size 小于等于 0 或超过 math.MaxInt32 时返回 ErrInvalidPoolSize。
+//
+// 参数:
+//   - size: 新的容量
+//
+// 返回:
+//   - error: 无效的容量时返回 ErrInvalidPoolSize
+func SetDefaultCapacity(size int) error {
+	if size <= 0 || size > maxPoolSize {
+		return ErrInvalidPoolSize
+	}
+	DefaultPool().Tune(size)
+	return nil
+}
@@ -0,0 +1,44 @@
+package laborer
+
+import (
+	"sync"
+	"testing"
+)
+
+// TestDefaultPool 测试默认池是共享的，并且可以调整容量和观察状态
+func TestDefaultPool(t *testing.T) {
+	pool := DefaultPool()
+	if pool != DefaultPool() {
+		t.Fatal("DefaultPool 应该总是返回同一个池")
+	}
+	if pool.Name() != DefaultPoolName {
+		t.Errorf("期望名称为 %q，实际 %q", DefaultPoolName, pool.Name())
+	}
+
+	if err := SetDefaultCapacity(0); err != ErrInvalidPoolSize {
+		t.Errorf("容量为 0 时期望返回 ErrInvalidPoolSize，实际 %v", err)
+	}
+	if err := SetDefaultCapacity(4); err != nil {
+		t.Fatalf("调整容量失败: %v", err)
+	}
+	defer func() { _ = SetDefaultCapacity(DefaultPoolSize) }()
+	if pool.Cap() != 4 {
+		t.Errorf("期望容量为 4，实际 %d", pool.Cap())
+	}
+
+	before := pool.Stats().Submitted
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		if err := pool.Submit(wg.Done); err != nil {
+			t.Fatalf("提交任务失败: %v", err)
+		}
+	}
+	wg.Wait()
+	if got := pool.Stats().Submitted - before; got != 10 {
+		t.Errorf("期望默认池统计到 10 个提交，实际 %d", got)
+	}
+	if pool.Running() > 4 {
+		t.Errorf("运行的 worker 数 %d 超过了调整后的容量 4", pool.Running())
+	}
+}
@@ -0,0 +1,125 @@
+package laborer
+
+import (
+	"sync"
+	"time"
+)
+
+// dispatchTask 是 Dispatcher 投递给 worker 的参数
+type dispatchTask struct {
+	handler func(interface{})
+	args    interface{}
+}
+
+// Dispatcher 多函数分发池，多个具名处理函数共享同一组 worker
+//
+// 与为每个函数分别创建 PoolWithFunc 并手动划分容量相比，
+// Dispatcher 让不同类型的任务共享容量：先通过 Register 注册处理函数，
+// 再通过 Dispatch 按名称提交参数。
+//
+// 示例:
+//
+//	d, _ := laborer.NewDispatcher(100)
+//	defer d.Release()
+//
+//	d.Register("resize", resizeImage)
+//	d.Register("thumbnail", makeThumbnail)
+//
+//	d.Dispatch("resize", img)
+type Dispatcher struct {
+	pool *PoolWithFunc
+
+	mu       sync.RWMutex
+	handlers map[string]func(interface{})
+}
+
+// NewDispatcher 创建一个新的多函数分发池
+// size: 池的容量，-1 表示无限容量
+// options: 配置选项，与 NewPoolWithFunc 相同
+func NewDispatcher(size int, options ...Option) (*Dispatcher, error) {
+	d := &Dispatcher{handlers: make(map[string]func(interface{}))}
+
+	pool, err := NewPoolWithFunc(size, func(args interface{}) {
+		t := args.(*dispatchTask)
+		t.handler(t.args)
+	}, options...)
+	if err != nil {
+		return nil, err
+	}
+	d.pool = pool
+
+	return d, nil
+}
+
+// Register 注册一个具名处理函数，同名的处理函数会被替换
+// fn 为 nil 时返回 ErrInvalidPoolFunc。
+func (d *Dispatcher) Register(name string, fn func(interface{})) error {
+	if fn == nil {
+		return ErrInvalidPoolFunc
+	}
+
+	d.mu.Lock()
+	d.handlers[name] = fn
+	d.mu.Unlock()
+
+	return nil
+}
+
+// Dispatch 将参数交给名为 name 的处理函数执行
+// 处理函数未注册时返回 ErrHandlerNotFound，其余错误与 PoolWithFunc.Invoke 相同。
+func (d *Dispatcher) Dispatch(name string, args interface{}) error {
+	d.mu.RLock()
+	fn, ok := d.handlers[name]
+	d.mu.RUnlock()
+
+	if !ok {
+		return ErrHandlerNotFound
+	}
+
+	return d.pool.Invoke(&dispatchTask{handler: fn, args: args})
+}
+
+// Running 返回当前正在运行的 worker 数量
+func (d *Dispatcher) Running() int {
+	return d.pool.Running()
+}
+
+// Free 返回当前空闲的 worker 数量
+func (d *Dispatcher) Free() int {
+	return d.pool.Free()
+}
+
+// Cap 返回池的容量
+func (d *Dispatcher) Cap() int {
+	return d.pool.Cap()
+}
+
+// Waiting 返回等待执行的任务数量
+func (d *Dispatcher) Waiting() int {
+	return d.pool.Waiting()
+}
+
+// IsClosed 返回池是否已关闭
+func (d *Dispatcher) IsClosed() bool {
+	return d.pool.IsClosed()
+}
+
+// Stats 返回池的运行状态快照
+func (d *Dispatcher) Stats() Stats {
+	return d.pool.Stats()
+}
+
+// Release 优雅关闭池
+func (d *Dispatcher) Release() {
+	d.pool.Release()
+}
+
+// ReleaseTimeout 带超时的优雅关闭
+func (d *Dispatcher) ReleaseTimeout(timeout time.Duration) error {
+	return d.pool.ReleaseTimeout(timeout)
+}
+
+// Reboot 重启已关闭的池
+func (d *Dispatcher) Reboot() {
+	d.pool.Reboot()
+}
@@ -0,0 +1,54 @@
+package laborer
+
+import (
+	"sync"
+	"testing"
+)
+
+// TestDispatcher 测试多函数分发池
+func TestDispatcher(t *testing.T) {
+	d, err := NewDispatcher(2)
+	if err != nil {
+		t.Fatalf("创建分发池失败: %v", err)
+	}
+	defer d.Release()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	got := make(map[string][]interface{})
+	record := func(name string) func(interface{}) {
+		return func(args interface{}) {
+			mu.Lock()
+			got[name] = append(got[name], args)
+			mu.Unlock()
+			wg.Done()
+		}
+	}
+
+	if err := d.Register("resize", record("resize")); err != nil {
+		t.Fatalf("注册处理函数失败: %v", err)
+	}
+	if err := d.Register("thumbnail", record("thumbnail")); err != nil {
+		t.Fatalf("注册处理函数失败: %v", err)
+	}
+	if err := d.Register("nil", nil); err != ErrInvalidPoolFunc {
+		t.Errorf("期望返回 ErrInvalidPoolFunc，实际返回: %v", err)
+	}
+
+	wg.Add(3)
+	_ = d.Dispatch("resize", 1)
+	_ = d.Dispatch("resize", 2)
+	_ = d.Dispatch("thumbnail", 3)
+	wg.Wait()
+
+	if len(got["resize"]) != 2 || len(got["thumbnail"]) != 1 || got["thumbnail"][0] != 3 {
+		t.Errorf("处理函数收到的参数不正确: %v", got)
+	}
+
+	if err := d.Dispatch("unknown", 4); err != ErrHandlerNotFound {
+		t.Errorf("期望返回 ErrHandlerNotFound，实际返回: %v", err)
+	}
+	if s := d.Stats(); s.Submitted != 3 {
+		t.Errorf("Submitted 期望 3，实际 %d", s.Submitted)
+	}
+}
@@ -1,6 +1,9 @@
 package laborer
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 // 错误定义
 //
@@ -19,10 +22,10 @@ var (
 	//  }
 	ErrPoolClosed = errors.New("pool has been closed")
 
-	// ErrPoolOverload 表示池已过载（仅在非阻塞模式下返回）。
+	// ErrPoolOverload 表示池已过载。
 	//
 	// 当池的所有 worker 都在忙碌且达到容量上限时，
-	// 在非阻塞模式下提交任务会返回此错误。
+	// 在非阻塞模式下，或阻塞模式下等待的提交数已达 MaxBlockingTasks 时提交任务会返回此错误。
 	//
 	// 处理建议:
 	//  - 增加池容量
@@ -37,13 +40,27 @@ var (
 	//  }
 	ErrPoolOverload = errors.New("pool is overloaded")
 
+	// ErrDraining 表示池正在排空。
+	//
+	// 调用 Drain 后池不再接受新的任务，已接受的任务会继续执行完毕，
+	// 排空期间提交任务会返回此错误。排空完成后池被关闭，之后的提交返回 ErrPoolClosed。
+	//
+	// 示例:
+	//  if err := pool.Submit(task); errors.Is(err, laborer.ErrDraining) {
+	//      // 池即将关闭，改为同步执行
+	//      task()
+	//  }
+	ErrDraining = errors.New("pool is draining")
+
 	// ErrInvalidPoolSize 表示提供的池大小无效。
 	//
-	// 当创建池时提供的容量为 0 时返回此错误。
-	// 有效的容量值为正整数或 -1（表示无限容量）。
+	// 当创建池时提供的容量小于 -1 或超过 math.MaxInt32 时返回此错误。
+	// 有效的容量值为不超过 math.MaxInt32 的正整数、-1（表示无限容量）
+	// 或 0（按 CPU 数量换算，见 WithSizeMultiplier）。
 	//
 	// 示例:
-	//  pool, err := laborer.NewPool(0)  // 返回 ErrInvalidPoolSize
+	//  pool, err := laborer.NewPool(-5) // 返回 ErrInvalidPoolSize
+	//  pool, err := laborer.NewPool(0)  // OK，容量为 runtime.GOMAXPROCS(0)
 	//  pool, err := laborer.NewPool(-1) // OK，无限容量
 	//  pool, err := laborer.NewPool(10) // OK
 	ErrInvalidPoolSize = errors.New("invalid pool size")
@@ -58,6 +75,26 @@ var (
 	//      laborer.WithExpiryDuration(-1 * time.Second)) // 返回 ErrInvalidPoolExpiry
 	ErrInvalidPoolExpiry = errors.New("invalid pool expiry")
 
+	// ErrInvalidCleanInterval 表示提供的清理间隔无效。
+	//
+	// 当 CleanInterval 配置为负数时返回此错误。
+	//
+	// 示例:
+	//  pool, err := laborer.NewPool(10,
+	//      laborer.WithCleanInterval(-1 * time.Second)) // 返回 ErrInvalidCleanInterval
+	ErrInvalidCleanInterval = errors.New("invalid clean interval")
+
+	// ErrInvalidOption 表示配置选项的组合无效。
+	//
+	// 创建池时会检查组合后的选项，例如 Logger 为 nil、无限容量的池启用 PreAlloc、
+	// MaxBlockingTasks 为负数等，返回的错误包装了此错误并说明具体原因。
+	//
+	// 示例:
+	//  _, err := laborer.NewPool(-1, laborer.WithPreAlloc(true))
+	//  // errors.Is(err, laborer.ErrInvalidOption) == true
+	//  // err.Error() == "invalid option: PreAlloc requires a bounded pool size"
+	ErrInvalidOption = errors.New("invalid option")
+
 	// ErrInvalidPoolFunc 表示提供的池函数无效。
 	//
 	// 当创建 PoolWithFunc 时提供的函数为 nil 时返回此错误。
@@ -66,11 +103,153 @@ var (
 	//  pool, err := laborer.NewPoolWithFunc(10, nil) // 返回 ErrInvalidPoolFunc
 	ErrInvalidPoolFunc = errors.New("invalid pool function")
 
+	// ErrInvalidLoadBalancingStrategy 表示提供的负载均衡策略无效。
+	//
+	// 当创建分片池时提供了未定义的 LoadBalancingStrategy 时返回此错误。
+	//
+	// 示例:
+	//  mp, err := laborer.NewMultiPoolWithFunc(4, 10, fn, laborer.LoadBalancingStrategy(99)) // 返回 ErrInvalidLoadBalancingStrategy
+	ErrInvalidLoadBalancingStrategy = errors.New("invalid load balancing strategy")
+
+	// ErrInvalidBudgetSize 表示提供的并发预算容量无效。
+	//
+	// 当 NewBudget 的容量小于等于 0 时返回此错误。
+	//
+	// 示例:
+	//  budget, err := laborer.NewBudget(0) // 返回 ErrInvalidBudgetSize
+	ErrInvalidBudgetSize = errors.New("invalid budget size")
+
+	// ErrInvalidTaskWeight 表示提交的任务权重无效。
+	//
+	// 当 SubmitWeighted 的权重小于等于 0 或超过池容量时返回此错误。
+	//
+	// 示例:
+	//  pool, _ := laborer.NewPool(4)
+	//  err := pool.SubmitWeighted(task, 8) // 返回 ErrInvalidTaskWeight
+	ErrInvalidTaskWeight = errors.New("invalid task weight")
+
+	// ErrNilTask 表示提交的任务为 nil。
+	//
+	// 当 Submit 系列方法的任务函数、SubmitTask 系列方法的任务对象（或 SubmitMany、SubmitAll 中的任意一个任务）为 nil 时返回此错误，
+	// 任务不会被接受。
+	//
+	// 示例:
+	//  err := pool.Submit(nil) // 返回 ErrNilTask
+	ErrNilTask = errors.New("nil task")
+
+	// ErrWorkerInit 表示 worker 初始化失败。
+	//
+	// 当设置了 WithWorkerInit 且创建新 worker 时初始化函数返回错误，
+	// 提交任务会返回包装了此错误和原始错误的错误，任务不会被执行。
+	//
+	// 示例:
+	//  if err := pool.Submit(task); errors.Is(err, laborer.ErrWorkerInit) {
+	//      log.Printf("worker init failed: %v", err)
+	//  }
+	ErrWorkerInit = errors.New("worker init failed")
+
+	// ErrPoolQuarantined 表示函数池因连续 panic 处于隔离状态。
+	//
+	// 启用 WithPanicQuarantine 后，固定函数连续 panic 达到阈值时，
+	// 冷却时间内的提交会返回此错误。
+	//
+	// 示例:
+	//  if err := pool.Invoke(msg); errors.Is(err, laborer.ErrPoolQuarantined) {
+	//      deadLetter.Push(msg)
+	//  }
+	ErrPoolQuarantined = errors.New("pool is quarantined after repeated panics")
+
+	// ErrHandlerNotFound 表示 Dispatcher 中没有注册指定名称的处理函数。
+	//
+	// 示例:
+	//  if err := d.Dispatch("resize", img); errors.Is(err, laborer.ErrHandlerNotFound) {
+	//      d.Register("resize", resize)
+	//  }
+	ErrHandlerNotFound = errors.New("handler not found")
+
+	// ErrConsumerStarted 表示 Consumer 已经启动过。
+	//
+	// 每个 Consumer 只能调用一次 Start，需要重新读取时创建新的 Consumer。
+	//
+	// 示例:
+	//  if err := c.Start(ctx, in); errors.Is(err, laborer.ErrConsumerStarted) {
+	//      c = laborer.NewConsumer(pool, handle)
+	//  }
+	ErrConsumerStarted = errors.New("consumer already started")
+
+	// ErrWorkerQueue 表示空闲 worker 队列操作失败。
+	//
+	// 不会由提交返回，只通过 WithErrorHandler 上报，例如归还的 worker 无法放回队列、
+	// 自定义的 WorkerQueue 返回了不属于此池的 worker。
+	//
+	// 示例:
+	//  laborer.WithErrorHandler(func(err error) {
+	//      if errors.Is(err, laborer.ErrWorkerQueue) {
+	//          queueErrors.Inc()
+	//      }
+	//  })
+	ErrWorkerQueue = errors.New("worker queue error")
+
+	// ErrTaskQueue 表示 TaskQueue 取出或确认参数失败。
+	//
+	// 不会由提交返回，只通过 WithErrorHandler 上报。取出失败的参数留在队列中，
+	// 在下一次有 worker 空闲时重试。
+	//
+	// 示例:
+	//  laborer.WithErrorHandler(func(err error) {
+	//      if errors.Is(err, laborer.ErrTaskQueue) {
+	//          queueErrors.Inc()
+	//      }
+	//  })
+	ErrTaskQueue = errors.New("task queue error")
+
+	// ErrTaskQueueFull 表示 TaskQueue 已满。
+	//
+	// 由 TaskQueue 的实现在 Push 时返回，池收到后按未设置队列的规则处理提交：
+	// 阻塞模式下等待 worker，非阻塞模式下返回 ErrPoolOverload。
+	//
+	// 示例:
+	//  func (q *redisQueue) Push(args interface{}) error {
+	//      if q.length() >= q.limit {
+	//          return laborer.ErrTaskQueueFull
+	//      }
+	//      ...
+	//  }
+	ErrTaskQueueFull = errors.New("task queue is full")
+
+	// ErrTaskQueueEmpty 表示 TaskQueue 中没有参数。
+	//
+	// 由 TaskQueue 的实现在 Pop 时返回，不会上报为错误。
+	//
+	// 示例:
+	//  func (q *redisQueue) Pop() (interface{}, error) {
+	//      if q.length() == 0 {
+	//          return nil, laborer.ErrTaskQueueEmpty
+	//      }
+	//      ...
+	//  }
+	ErrTaskQueueEmpty = errors.New("task queue is empty")
+
+	// ErrInvariant 表示池检测到内部状态不一致，例如运行中的 worker 计数变为负数。
+	//
+	// 不会由提交返回，只通过 WithErrorHandler 上报。出现此错误说明池存在缺陷，
+	// 请附上错误信息提交 issue。
+	//
+	// 示例:
+	//  laborer.WithErrorHandler(func(err error) {
+	//      if errors.Is(err, laborer.ErrInvariant) {
+	//          alert(err)
+	//      }
+	//  })
+	ErrInvariant = errors.New("pool invariant violated")
+
 	// ErrTimeout 表示操作超时。
 	//
 	// 在以下情况下返回此错误:
-	//  - ReleaseTimeout: 池关闭超时
+	//  - ReleaseTimeout: 超时前仍有任务未完成（错误信息中包含未完成的任务数量）
+	//  - WaitWithTimeout: 超时前池仍未空闲（错误信息中包含未完成的任务数量）
 	//  - Future.GetWithTimeout: 等待任务结果超时
+	//  - PoolWithFunc.InvokeWithTimeout: 等待空闲 worker 超时
 	//
 	// 示例:
 	//  if err := pool.ReleaseTimeout(5 * time.Second); errors.Is(err, laborer.ErrTimeout) {
@@ -79,3 +258,56 @@ var (
 	//  }
 	ErrTimeout = errors.New("operation timeout")
 )
+
+// nameError 设置了池名称时用 pool "<name>": 前缀包装 err，err 为 nil 时返回 nil
+// 包装后 errors.Is 仍然可以识别原始错误。
+func (opts *Options) nameError(err error) error {
+	if err == nil || opts.Name == "" {
+		return err
+	}
+	return fmt.Errorf("pool %q: %w", opts.Name, err)
+}
+
+// reportError 上报池内部的运行错误
+// 优先调用 ErrorHandler，未设置时以 LevelError 的 pool_error 事件写入日志。
+func (opts *Options) reportError(err error) {
+	if opts.ErrorHandler != nil {
+		opts.ErrorHandler(opts.nameError(err))
+		return
+	}
+	opts.logEvent(LevelError, "pool_error", Field{"error", err})
+}
+
+// PanicError 表示带返回值的任务在执行过程中发生了 panic。
+//
+// 通过 SubmitWithResult 提交的任务 panic 时，对应的 Future 会以此错误完成，
+// 而不是永远阻塞 Get。panic 本身仍会按照 PanicHandler 的配置处理。
+//
+// 示例:
+//
+//	_, err := future.Get()
+//	var pe *laborer.PanicError
+//	if errors.As(err, &pe) {
+//	    log.Printf("task panicked: %v\n%s", pe.Value, pe.Stack)
+//	}
+type PanicError struct {
+	// Pool 任务所属池的名称，未设置名称时为空
+	Pool string
+
+	// WorkerID 执行任务的 worker 的编号
+	WorkerID int
+
+	// Value panic 恢复的值
+	Value interface{}
+
+	// Stack panic 发生时的 goroutine 栈
+	Stack []byte
+}
+
+// Error 实现 error 接口
+func (e *PanicError) Error() string {
+	if e.Pool != "" {
+		return fmt.Sprintf("pool %q: task panicked: %v", e.Pool, e.Value)
+	}
+	return fmt.Sprintf("task panicked: %v", e.Value)
+}
@@ -0,0 +1,143 @@
+package laborer
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultEventBuffer Events 返回的 channel 的默认缓冲大小
+const DefaultEventBuffer = 256
+
+// EventType 池事件的类型
+type EventType int
+
+const (
+	// WorkerCreated 创建了一个新的 worker，WorkerID 为它的编号
+	WorkerCreated EventType = iota + 1
+
+	// WorkerExpired 空闲超时的 worker 被回收，WorkerID 为它的编号
+	WorkerExpired
+
+	// WorkerExited worker 退出，WorkerID 为它的编号
+	WorkerExited
+
+	// TaskRejected 提交因池过载或处于隔离状态被拒绝，Err 为返回给提交方的错误
+	TaskRejected
+
+	// TaskPanicked 任务发生了 panic，WorkerID 为执行它的 worker，Value 为恢复的值
+	TaskPanicked
+
+	// PoolTuned Tune 调整了容量，OldCap 和 Cap 为调整前后的容量
+	PoolTuned
+
+	// PoolQuarantined 函数池因连续 panic 进入隔离状态
+	PoolQuarantined
+
+	// PoolClosed Release 或 ReleaseTimeout 关闭了池
+	PoolClosed
+
+	// PoolRebooted Reboot 重启了已关闭的池，Cap 为池的容量
+	PoolRebooted
+)
+
+// eventTypeNames 事件类型的名称
+var eventTypeNames = map[EventType]string{
+	WorkerCreated:   "WorkerCreated",
+	WorkerExpired:   "WorkerExpired",
+	WorkerExited:    "WorkerExited",
+	TaskRejected:    "TaskRejected",
+	TaskPanicked:    "TaskPanicked",
+	PoolTuned:       "PoolTuned",
+	PoolQuarantined: "PoolQuarantined",
+	PoolClosed:      "PoolClosed",
+	PoolRebooted:    "PoolRebooted",
+}
+
+// String 返回事件类型的名称
+func (t EventType) String() string {
+	if name, ok := eventTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("EventType(%d)", int(t))
+}
+
+// Event 池发出的一个类型化事件
+//
+// 与日志事件不同，Event 面向程序消费：外部系统可以据此扩缩容、告警或记录审计，
+// 而不必轮询 Stats。各字段只在对应的事件类型中有意义，其余为零值。
+type Event struct {
+	// Type 事件类型
+	Type EventType
+
+	// Pool 池的名称，未设置名称时为空
+	Pool string
+
+	// Time 事件发生的时间
+	Time time.Time
+
+	// WorkerID worker 事件和 TaskPanicked 中 worker 的编号
+	WorkerID int
+
+	// Cap PoolTuned 调整后的容量，PoolRebooted 时池的容量
+	Cap int
+
+	// OldCap PoolTuned 调整前的容量
+	OldCap int
+
+	// Err TaskRejected 中返回给提交方的错误
+	Err error
+
+	// Value TaskPanicked 中 panic 恢复的值
+	Value interface{}
+}
+
+// eventHub 池的事件 channel
+// 第一次调用 Events 时才创建 channel，之前发出事件只需要一次 atomic 读取。
+// 消费者处理不及时、缓冲已满时丢弃新的事件并计数，不会阻塞池。
+type eventHub struct {
+	// mu 保护 channel 的创建
+	mu sync.Mutex
+
+	// ch 事件 channel，未订阅时为 nil
+	ch atomic.Pointer[chan Event]
+
+	// dropped 因缓冲已满被丢弃的事件数量
+	dropped atomic.Uint64
+}
+
+// channel 返回事件 channel，第一次调用时按 size 创建
+func (h *eventHub) channel(size int) <-chan Event {
+	if ch := h.ch.Load(); ch != nil {
+		return *ch
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if ch := h.ch.Load(); ch != nil {
+		return *ch
+	}
+	if size <= 0 {
+		size = DefaultEventBuffer
+	}
+	ch := make(chan Event, size)
+	h.ch.Store(&ch)
+	return ch
+}
+
+// emit 发出一个事件，未订阅时不做任何事，缓冲已满时丢弃并计数
+func (h *eventHub) emit(opts *Options, e Event) {
+	ch := h.ch.Load()
+	if ch == nil {
+		return
+	}
+
+	e.Pool = opts.Name
+	e.Time = time.Now()
+	select {
+	case *ch <- e:
+	default:
+		h.dropped.Add(1)
+	}
+}
@@ -0,0 +1,139 @@
+package laborer
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// nextEvent 从事件 channel 读取下一个事件，超时则测试失败
+func nextEvent(t *testing.T, ch <-chan Event) Event {
+	t.Helper()
+	select {
+	case e := <-ch:
+		return e
+	case <-time.After(time.Second):
+		t.Fatal("等待事件超时")
+		return Event{}
+	}
+}
+
+// TestPoolEvents 测试池按发生顺序发出类型化事件
+func TestPoolEvents(t *testing.T) {
+	pool, err := NewPool(1, WithName("events"), WithNonblocking(true), WithPanicHandler(func(interface{}) {}))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	events := pool.Events()
+	if pool.Events() != events {
+		t.Fatal("多次调用 Events 应该返回同一个 channel")
+	}
+
+	block := make(chan struct{})
+	if err := pool.Submit(func() { <-block }); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	e := nextEvent(t, events)
+	if e.Type != WorkerCreated || e.WorkerID != 1 || e.Pool != "events" || e.Time.IsZero() {
+		t.Errorf("期望 worker 1 的 WorkerCreated 事件，实际 %+v", e)
+	}
+
+	if err := pool.Submit(func() {}); err != ErrPoolOverload {
+		t.Fatalf("期望返回 ErrPoolOverload，实际返回: %v", err)
+	}
+	if e := nextEvent(t, events); e.Type != TaskRejected || !errors.Is(e.Err, ErrPoolOverload) {
+		t.Errorf("期望 TaskRejected 事件，实际 %+v", e)
+	}
+	close(block)
+	pool.Wait()
+
+	if err := pool.Submit(func() { panic("boom") }); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	if e := nextEvent(t, events); e.Type != TaskPanicked || e.WorkerID != 1 || e.Value != "boom" {
+		t.Errorf("期望 TaskPanicked 事件，实际 %+v", e)
+	}
+	// panic 后 worker 退出
+	if e := nextEvent(t, events); e.Type != WorkerExited || e.WorkerID != 1 {
+		t.Errorf("期望 worker 1 的 WorkerExited 事件，实际 %+v", e)
+	}
+
+	pool.Tune(3)
+	if e := nextEvent(t, events); e.Type != PoolTuned || e.OldCap != 1 || e.Cap != 3 {
+		t.Errorf("期望 PoolTuned 事件，实际 %+v", e)
+	}
+
+	pool.Release()
+	if e := nextEvent(t, events); e.Type != PoolClosed {
+		t.Errorf("期望 PoolClosed 事件，实际 %+v", e)
+	}
+
+	pool.Reboot()
+	if e := nextEvent(t, events); e.Type != PoolRebooted || e.Cap != 3 {
+		t.Errorf("期望 PoolRebooted 事件，实际 %+v", e)
+	}
+	pool.Release()
+}
+
+// TestPoolEventsExpired 测试空闲超时的 worker 发出 WorkerExpired 事件
+func TestPoolEventsExpired(t *testing.T) {
+	pool, err := NewPoolWithFunc(1, func(interface{}) {},
+		WithExpiryDuration(20*time.Millisecond),
+		WithCleanInterval(10*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+	events := pool.Events()
+
+	if err := pool.Invoke(1); err != nil {
+		t.Fatalf("提交调用失败: %v", err)
+	}
+	want := []EventType{WorkerCreated, WorkerExpired, WorkerExited}
+	for _, typ := range want {
+		if e := nextEvent(t, events); e.Type != typ || e.WorkerID != 1 {
+			t.Errorf("期望 worker 1 的 %s 事件，实际 %+v", typ, e)
+		}
+	}
+}
+
+// TestPoolEventsDropped 测试缓冲已满时丢弃事件并计数，不阻塞池
+func TestPoolEventsDropped(t *testing.T) {
+	pool, err := NewPool(1, WithNonblocking(true), WithEventBuffer(2))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	// 订阅之前的事件不会被记录
+	pool.reject()
+	if pool.DroppedEvents() != 0 {
+		t.Fatal("未订阅时不应该计入丢弃的事件")
+	}
+
+	events := pool.Events()
+	if cap(events) != 2 {
+		t.Fatalf("期望缓冲大小为 2，实际 %d", cap(events))
+	}
+	for i := 0; i < 5; i++ {
+		pool.reject()
+	}
+	if len(events) != 2 || pool.DroppedEvents() != 3 {
+		t.Errorf("期望缓冲 2 个事件并丢弃 3 个，实际缓冲 %d 个，丢弃 %d 个", len(events), pool.DroppedEvents())
+	}
+
+	if _, err := NewPool(1, WithEventBuffer(-1)); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("负的缓冲大小应该返回 ErrInvalidOption，实际 %v", err)
+	}
+}
+
+// TestEventTypeString 测试事件类型的名称
+func TestEventTypeString(t *testing.T) {
+	if got := PoolQuarantined.String(); got != "PoolQuarantined" {
+		t.Errorf("期望 PoolQuarantined，实际 %s", got)
+	}
+	if got := EventType(100).String(); got != "EventType(100)" {
+		t.Errorf("期望 EventType(100)，实际 %s", got)
+	}
+}
@@ -56,15 +56,15 @@ func main() {
 	}
 
 	fmt.Println("4. 获取所有任务的结果")
-	for i, f := range futures {
-		if f == nil {
+	results, errs := laborer.Collect(futures)
+	for i := range futures {
+		if futures[i] == nil {
 			continue
 		}
-		result, err := f.Get()
-		if err != nil {
-			fmt.Printf("   任务 %d 失败: %v\n", i, err)
+		if errs[i] != nil {
+			fmt.Printf("   任务 %d 失败: %v\n", i, errs[i])
 		} else {
-			fmt.Printf("   任务 %d 结果: %v\n", i, result)
+			fmt.Printf("   任务 %d 结果: %v\n", i, results[i])
 		}
 	}
 	fmt.Println()
@@ -184,18 +184,18 @@ func main() {
 
 	// 收集所有结果
 	fmt.Println("9. 收集处理结果:")
-	for i, f := range resultFutures {
-		if f == nil {
+	results, errs = laborer.CollectWithTimeout(resultFutures, 5*time.Second)
+	for i := range resultFutures {
+		if resultFutures[i] == nil {
 			continue
 		}
 
-		result, err := f.Get()
-		if err != nil {
-			fmt.Printf("   任务 %d 失败: %v\n", i+1, err)
+		if errs[i] != nil {
+			fmt.Printf("   任务 %d 失败: %v\n", i+1, errs[i])
 			continue
 		}
 
-		r := result.(Result)
+		r := results[i].(Result)
 		fmt.Printf("   任务 %d: %s (成功: %v)\n", r.TaskID, r.Processed, r.Success)
 	}
 	fmt.Println()
@@ -241,15 +241,19 @@ func main() {
 	defer configuredPool.Release()
 
 	// 提交会 panic 的任务
-	_, err = configuredPool.SubmitWithResult(func() (interface{}, error) {
+	panicFuture, err := configuredPool.SubmitWithResult(func() (interface{}, error) {
 		panic("测试 panic")
 	})
 	if err != nil {
 		panic(err)
 	}
 
-	// 等待一下让 panic 被处理
-	time.Sleep(100 * time.Millisecond)
+	// panic 的任务的 future 会以 *laborer.PanicError 完成，不会永久阻塞
+	_, err = panicFuture.Get()
+	var pe *laborer.PanicError
+	if errors.As(err, &pe) {
+		fmt.Printf("   - 任务 panic: %v\n", pe.Value)
+	}
 
 	// 提交正常任务验证池仍然工作
 	normalFuture, err := configuredPool.SubmitWithResult(func() (interface{}, error) {
@@ -266,9 +270,5 @@ func main() {
 		fmt.Printf("   - %v\n", result)
 	}
 
-	// 注意：panic 的任务的 future 会一直阻塞，因为结果永远不会被设置
-	// 在实际应用中，应该使用 GetWithTimeout 来避免永久阻塞
-	fmt.Println("   - 注意: panic 的任务不会返回结果")
-
 	fmt.Println("\n=== 示例完成 ===")
 }
@@ -1,6 +1,7 @@
 package laborer
 
 import (
+	"runtime/debug"
 	"sync"
 	"time"
 )
@@ -85,6 +86,25 @@ type Future interface {
 	//      // 任务仍在执行，继续其他工作
 	//  }
 	IsDone() bool
+
+	// Map 返回一个新的 Future，其结果是对当前 Future 的结果应用 fn 后得到的值。
+	//
+	// fn 在当前 Future 完成时由完成它的 goroutine 调用（如果已经完成则立即调用），
+	// 因此应当是轻量的转换，不要在其中执行阻塞操作。
+	// 如果当前 Future 以错误完成，fn 不会被调用，错误会直接传递给新的 Future。
+	// fn 发生 panic 时，新的 Future 以 *PanicError 完成。
+	//
+	// 参数:
+	//  - fn: 结果转换函数
+	//
+	// 返回:
+	//  - Future: 转换后的 Future
+	//
+	// 示例:
+	//  lengths := future.Map(func(v interface{}) (interface{}, error) {
+	//      return len(v.(string)), nil
+	//  })
+	Map(fn func(interface{}) (interface{}, error)) Future
 }
 
 // future 是 Future 接口的内部实现。
@@ -104,6 +124,12 @@ type future struct {
 	// once 确保结果只被设置一次
 	// 防止多次设置结果导致的竞态条件
 	once sync.Once
+
+	// mu 保护 callbacks
+	mu sync.Mutex
+
+	// callbacks 在结果设置后依次调用，由 Map 注册
+	callbacks []func()
 }
 
 // newFuture 创建一个新的 future 实例。
@@ -184,5 +210,129 @@ func (f *future) setResult(result interface{}, err error) {
 		f.result = result
 		f.err = err
 		close(f.done)
+
+		f.mu.Lock()
+		callbacks := f.callbacks
+		f.callbacks = nil
+		f.mu.Unlock()
+
+		for _, cb := range callbacks {
+			cb()
+		}
 	})
 }
+
+// Map 实现 Future.Map 接口。
+//
+// 在当前 future 上注册一个回调，完成时对结果应用 fn 并设置到新的 future。
+// 如果当前 future 已经完成，回调会立即在调用方 goroutine 中执行。
+//
+// 参数:
+//   - fn: 结果转换函数
+//
+// 返回:
+//   - Future: 转换后的 Future
+func (f *future) Map(fn func(interface{}) (interface{}, error)) Future {
+	mapped := newFuture()
+	transform := func() {
+		if f.err != nil {
+			mapped.setResult(nil, f.err)
+			return
+		}
+		defer func() {
+			if p := recover(); p != nil {
+				mapped.setResult(nil, &PanicError{Value: p, Stack: debug.Stack()})
+			}
+		}()
+		mapped.setResult(fn(f.result))
+	}
+
+	f.mu.Lock()
+	if !f.IsDone() {
+		f.callbacks = append(f.callbacks, transform)
+		f.mu.Unlock()
+		return mapped
+	}
+	f.mu.Unlock()
+
+	transform()
+	return mapped
+}
+
+// Result 表示通过 channel 投递的任务结果，参见 Pool.SubmitToChan。
+type Result struct {
+	// Value 任务的返回值
+	Value interface{}
+
+	// Err 任务返回的错误，任务 panic 时为 *PanicError
+	Err error
+}
+
+// Collect 等待所有 future 完成，并按原顺序返回结果和错误。
+//
+// 返回的两个切片与 futures 按下标一一对应，便于和提交顺序对齐。
+// futures 中为 nil 的元素（例如提交失败时）会被跳过，对应位置保持 nil。
+//
+// 参数:
+//   - futures: 需要等待的 future 列表
+//
+// 返回:
+//   - []interface{}: 各任务的返回值
+//   - []error: 各任务的错误
+//
+// 示例:
+//
+//	results, errs := laborer.Collect(futures)
+//	for i := range results {
+//	    if errs[i] != nil {
+//	        log.Printf("task %d failed: %v", i, errs[i])
+//	    }
+//	}
+func Collect(futures []Future) ([]interface{}, []error) {
+	results := make([]interface{}, len(futures))
+	errs := make([]error, len(futures))
+	for i, f := range futures {
+		if f == nil {
+			continue
+		}
+		results[i], errs[i] = f.Get()
+	}
+	return results, errs
+}
+
+// CollectWithTimeout 与 Collect 相同，但所有 future 共享一个总的超时时间。
+//
+// 超时之后仍未完成的 future，对应位置的错误为 ErrTimeout。
+//
+// 参数:
+//   - futures: 需要等待的 future 列表
+//   - timeout: 等待所有 future 的总超时时间
+//
+// 返回:
+//   - []interface{}: 各任务的返回值
+//   - []error: 各任务的错误或 ErrTimeout
+//
+// 示例:
+//
+//	results, errs := laborer.CollectWithTimeout(futures, 5*time.Second)
+func CollectWithTimeout(futures []Future, timeout time.Duration) ([]interface{}, []error) {
+	results := make([]interface{}, len(futures))
+	errs := make([]error, len(futures))
+	deadline := time.Now().Add(timeout)
+	for i, f := range futures {
+		if f == nil {
+			continue
+		}
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			if f.IsDone() {
+				results[i], errs[i] = f.Get()
+			} else {
+				errs[i] = ErrTimeout
+			}
+			continue
+		}
+		results[i], errs[i] = f.GetWithTimeout(remaining)
+	}
+	return results, errs
+}
@@ -0,0 +1,193 @@
+package laborer
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestPromise 测试由外部完成的 Promise
+func TestPromise(t *testing.T) {
+	promise := NewPromise()
+	future := promise.Future()
+
+	if future.IsDone() {
+		t.Error("Promise 未完成时 IsDone 应该为 false")
+	}
+	if _, err := future.GetWithTimeout(10 * time.Millisecond); err != ErrTimeout {
+		t.Errorf("期望返回 ErrTimeout，实际返回: %v", err)
+	}
+
+	go promise.Complete("reply", nil)
+
+	result, err := future.GetWithTimeout(time.Second)
+	if err != nil || result != "reply" {
+		t.Errorf("期望结果为 reply，实际为 %v, %v", result, err)
+	}
+
+	// 重复完成不会改变结果
+	promise.Complete(nil, errors.New("late"))
+	if result, err := future.Get(); err != nil || result != "reply" {
+		t.Errorf("重复 Complete 改变了结果: %v, %v", result, err)
+	}
+}
+
+// TestFutureMap 测试 Future 的结果转换
+func TestFutureMap(t *testing.T) {
+	pool, err := NewPool(2)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	future, err := pool.SubmitWithResult(func() (interface{}, error) {
+		time.Sleep(10 * time.Millisecond)
+		return "hello", nil
+	})
+	if err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+
+	length := future.Map(func(v interface{}) (interface{}, error) {
+		return len(v.(string)), nil
+	})
+	doubled := length.Map(func(v interface{}) (interface{}, error) {
+		return v.(int) * 2, nil
+	})
+
+	if result, err := doubled.GetWithTimeout(time.Second); err != nil || result != 10 {
+		t.Errorf("期望结果为 10，实际为 %v, %v", result, err)
+	}
+
+	// 已完成的 Future 上调用 Map 会立即转换
+	if result, _ := future.Map(func(v interface{}) (interface{}, error) {
+		return v.(string) + "!", nil
+	}).Get(); result != "hello!" {
+		t.Errorf("期望结果为 hello!，实际为 %v", result)
+	}
+
+	// 上游错误直接传递，fn 不会被调用
+	boom := errors.New("boom")
+	promise := NewPromise()
+	called := false
+	mapped := promise.Future().Map(func(v interface{}) (interface{}, error) {
+		called = true
+		return v, nil
+	})
+	promise.Complete(nil, boom)
+	if _, err := mapped.Get(); err != boom || called {
+		t.Errorf("期望传递上游错误且不调用 fn，实际 err=%v called=%v", err, called)
+	}
+
+	// fn panic 时以 PanicError 完成
+	_, err = future.Map(func(interface{}) (interface{}, error) {
+		panic("bad transform")
+	}).Get()
+	var pe *PanicError
+	if !errors.As(err, &pe) {
+		t.Errorf("期望返回 PanicError，实际返回: %v", err)
+	}
+}
+
+// TestCollect 测试按提交顺序收集结果
+func TestCollect(t *testing.T) {
+	pool, err := NewPool(4)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	boom := errors.New("boom")
+	futures := make([]Future, 5)
+	for i := 0; i < 5; i++ {
+		n := i
+		if n == 3 {
+			continue // 模拟提交失败
+		}
+		futures[i], err = pool.SubmitWithResult(func() (interface{}, error) {
+			time.Sleep(time.Duration(5-n) * time.Millisecond)
+			if n == 1 {
+				return nil, boom
+			}
+			return n * n, nil
+		})
+		if err != nil {
+			t.Fatalf("提交任务失败: %v", err)
+		}
+	}
+
+	results, errs := Collect(futures)
+	want := []interface{}{0, nil, 4, nil, 16}
+	for i := range want {
+		if results[i] != want[i] {
+			t.Errorf("results[%d] 期望 %v，实际 %v", i, want[i], results[i])
+		}
+	}
+	if errs[1] != boom || errs[0] != nil || errs[3] != nil {
+		t.Errorf("错误未按下标对齐: %v", errs)
+	}
+}
+
+// TestCollectWithTimeout 测试带总超时的结果收集
+func TestCollectWithTimeout(t *testing.T) {
+	done := NewPromise()
+	done.Complete("ok", nil)
+	pending := NewPromise()
+
+	start := time.Now()
+	results, errs := CollectWithTimeout([]Future{pending.Future(), done.Future(), pending.Future()}, 30*time.Millisecond)
+	if elapsed := time.Since(start); elapsed > 200*time.Millisecond {
+		t.Errorf("超时应该是所有 future 共享的，实际耗时 %v", elapsed)
+	}
+
+	if errs[0] != ErrTimeout || errs[2] != ErrTimeout {
+		t.Errorf("未完成的 future 期望返回 ErrTimeout，实际 %v", errs)
+	}
+	if results[1] != "ok" || errs[1] != nil {
+		t.Errorf("已完成的 future 应该返回结果，实际 %v, %v", results[1], errs[1])
+	}
+}
+
+// TestSubmitToChan 测试通过 channel 接收任务结果
+func TestSubmitToChan(t *testing.T) {
+	pool, err := NewPool(3, WithPanicHandler(func(interface{}) {}))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	out := make(chan Result, 3)
+	boom := errors.New("boom")
+	tasks := []func() (interface{}, error){
+		func() (interface{}, error) { return 1, nil },
+		func() (interface{}, error) { return nil, boom },
+		func() (interface{}, error) { panic("oops") },
+	}
+	for _, task := range tasks {
+		if err := pool.SubmitToChan(task, out); err != nil {
+			t.Fatalf("提交任务失败: %v", err)
+		}
+	}
+
+	var values, errs, panics int
+	for i := 0; i < len(tasks); i++ {
+		select {
+		case r := <-out:
+			var pe *PanicError
+			switch {
+			case errors.As(r.Err, &pe):
+				panics++
+			case r.Err == boom:
+				errs++
+			case r.Value == 1:
+				values++
+			}
+		case <-time.After(time.Second):
+			t.Fatal("等待结果超时")
+		}
+	}
+
+	if values != 1 || errs != 1 || panics != 1 {
+		t.Errorf("结果不正确: values=%d errs=%d panics=%d", values, errs, panics)
+	}
+}
@@ -0,0 +1,163 @@
+package laborer
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// TaskGroup 将一组相关任务提交到同一个池中，并等待它们全部完成。
+//
+// TaskGroup 收集所有任务返回的错误（任务 panic 时为 *PanicError），
+// 省去手写 sync.WaitGroup 和错误切片的样板代码。
+// TaskGroup 可以从多个 goroutine 中并发调用 Submit。
+//
+// 示例:
+//
+//	group := laborer.NewTaskGroup(pool)
+//	for _, url := range urls {
+//	    url := url
+//	    group.Submit(func() error {
+//	        return fetch(url)
+//	    })
+//	}
+//	if err := group.Wait(); err != nil {
+//	    log.Printf("some fetches failed: %v", err)
+//	}
+type TaskGroup struct {
+	pool *Pool
+	wg   sync.WaitGroup
+
+	// ctx 和 cancel 仅在 NewTaskGroupWithContext 创建的组中设置
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu   sync.Mutex
+	errs []error
+}
+
+// NewTaskGroup 创建一个在指定池中执行任务的 TaskGroup。
+//
+// 参数:
+//   - pool: 执行任务的池
+//
+// 返回:
+//   - *TaskGroup: 新创建的任务组
+func NewTaskGroup(pool *Pool) *TaskGroup {
+	return &TaskGroup{pool: pool}
+}
+
+// NewTaskGroupWithContext 创建一个在第一个错误时取消的 TaskGroup，语义与 errgroup.WithContext 一致。
+//
+// 返回的 ctx 派生自 parent，会在组中第一个任务失败（或提交失败）时被取消，
+// 或者在 Wait 返回时被取消。ctx 取消后尚未开始执行的任务会被跳过。
+// 该模式下 Wait 只返回第一个错误。
+//
+// 参数:
+//   - parent: 父上下文
+//   - pool: 执行任务的池
+//
+// 返回:
+//   - *TaskGroup: 新创建的任务组
+//   - context.Context: 任务应当监听的上下文
+//
+// 示例:
+//
+//	group, ctx := laborer.NewTaskGroupWithContext(ctx, pool)
+//	for _, url := range urls {
+//	    url := url
+//	    group.Submit(func() error {
+//	        return fetch(ctx, url)
+//	    })
+//	}
+//	err := group.Wait() // 第一个失败任务的错误
+func NewTaskGroupWithContext(parent context.Context, pool *Pool) (*TaskGroup, context.Context) {
+	ctx, cancel := context.WithCancel(parent)
+	return &TaskGroup{pool: pool, ctx: ctx, cancel: cancel}, ctx
+}
+
+// Submit 向组中提交一个任务。
+//
+// 提交失败时（例如 ErrPoolClosed、ErrPoolOverload）返回该错误，
+// 同时该错误也会被记录，并在 Wait 中返回。
+//
+// 参数:
+//   - task: 要执行的任务
+//
+// 返回:
+//   - error: 提交错误
+func (g *TaskGroup) Submit(task func() error) error {
+	if task == nil {
+		g.record(ErrNilTask)
+		return ErrNilTask
+	}
+	g.wg.Add(1)
+	err := g.pool.submitCall(func() (interface{}, error) {
+		// 组已取消时跳过尚未开始的任务
+		if g.ctx != nil && g.ctx.Err() != nil {
+			return nil, nil
+		}
+		return nil, task()
+	}, func(_ interface{}, err error) {
+		g.record(err)
+		g.wg.Done()
+	})
+	if err != nil {
+		g.record(err)
+		g.wg.Done()
+	}
+	return err
+}
+
+// Wait 等待组中所有任务完成，并返回所有错误合并后的结果。
+//
+// 返回:
+//   - error: 没有任务失败时为 nil，否则为 errors.Join 合并后的错误，
+//     可以通过 errors.Is / errors.As 检查其中的单个错误；
+//     通过 NewTaskGroupWithContext 创建的组直接返回第一个错误
+func (g *TaskGroup) Wait() error {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel()
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.cancel != nil && len(g.errs) > 0 {
+		return g.errs[0]
+	}
+	return errors.Join(g.errs...)
+}
+
+// Errors 等待组中所有任务完成，并按完成顺序返回所有错误。
+//
+// 返回:
+//   - []error: 失败任务的错误列表，没有任务失败时为空
+func (g *TaskGroup) Errors() []error {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel()
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]error(nil), g.errs...)
+}
+
+// record 记录一个任务错误
+func (g *TaskGroup) record(err error) {
+	if err == nil {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	// 带 context 的组只保留第一个错误，并取消其余任务
+	if g.cancel != nil {
+		if len(g.errs) > 0 {
+			return
+		}
+		g.cancel()
+	}
+	g.errs = append(g.errs, err)
+}
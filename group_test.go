@@ -0,0 +1,109 @@
+package laborer
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+// TestTaskGroup 测试任务组等待并收集错误
+func TestTaskGroup(t *testing.T) {
+	pool, err := NewPool(4, WithPanicHandler(func(interface{}) {}))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	errA := errors.New("a")
+	errB := errors.New("b")
+	var ran int32
+
+	group := NewTaskGroup(pool)
+	for i := 0; i < 10; i++ {
+		i := i
+		_ = group.Submit(func() error {
+			atomic.AddInt32(&ran, 1)
+			switch i {
+			case 3:
+				return errA
+			case 7:
+				return errB
+			case 9:
+				panic("oops")
+			}
+			return nil
+		})
+	}
+
+	err = group.Wait()
+	if n := atomic.LoadInt32(&ran); n != 10 {
+		t.Errorf("Wait 返回前期望执行 10 个任务，实际 %d", n)
+	}
+	var pe *PanicError
+	if !errors.Is(err, errA) || !errors.Is(err, errB) || !errors.As(err, &pe) {
+		t.Errorf("合并后的错误缺少任务错误: %v", err)
+	}
+	if errs := group.Errors(); len(errs) != 3 {
+		t.Errorf("期望 3 个错误，实际 %d 个: %v", len(errs), errs)
+	}
+
+	// 没有失败的组返回 nil
+	ok := NewTaskGroup(pool)
+	_ = ok.Submit(func() error { return nil })
+	if err := ok.Wait(); err != nil {
+		t.Errorf("期望返回 nil，实际返回: %v", err)
+	}
+}
+
+// TestTaskGroupClosedPool 测试提交失败被记录到组错误中
+func TestTaskGroupClosedPool(t *testing.T) {
+	pool, err := NewPool(1)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	pool.Release()
+
+	group := NewTaskGroup(pool)
+	if err := group.Submit(func() error { return nil }); err != ErrPoolClosed {
+		t.Errorf("期望返回 ErrPoolClosed，实际返回: %v", err)
+	}
+	if err := group.Wait(); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Wait 期望包含 ErrPoolClosed，实际返回: %v", err)
+	}
+}
+
+// TestTaskGroupWithContext 测试第一个错误取消组内其余任务
+func TestTaskGroupWithContext(t *testing.T) {
+	pool, err := NewPool(1)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	boom := errors.New("boom")
+	var ran int32
+
+	group, ctx := NewTaskGroupWithContext(context.Background(), pool)
+	_ = group.Submit(func() error {
+		atomic.AddInt32(&ran, 1)
+		return boom
+	})
+	for i := 0; i < 5; i++ {
+		_ = group.Submit(func() error {
+			atomic.AddInt32(&ran, 1)
+			return errors.New("sibling")
+		})
+	}
+
+	if err := group.Wait(); err != boom {
+		t.Errorf("期望返回第一个错误 boom，实际返回: %v", err)
+	}
+	if ctx.Err() != context.Canceled {
+		t.Error("第一个错误后 ctx 应该被取消")
+	}
+	// 容量为 1 的池按顺序执行，后续任务开始时组已被取消
+	if n := atomic.LoadInt32(&ran); n != 1 {
+		t.Errorf("取消后尚未开始的任务应该被跳过，实际执行了 %d 个", n)
+	}
+}
@@ -0,0 +1,18 @@
+module github.com/kawaiirei0/laborer/grpc
+
+go 1.21
+
+replace github.com/kawaiirei0/laborer => ..
+
+require (
+	github.com/kawaiirei0/laborer v0.0.0
+	google.golang.org/grpc v1.66.3
+)
+
+require (
+	golang.org/x/net v0.26.0 // indirect
+	golang.org/x/sys v0.21.0 // indirect
+	golang.org/x/text v0.16.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240604185151-ef581f913117 // indirect
+	google.golang.org/protobuf v1.34.1 // indirect
+)
@@ -0,0 +1,100 @@
+// Package grpc 提供以 laborer 池限制 gRPC 服务并发的拦截器。
+//
+// 请求的处理函数在池的 worker 中执行，同时处理的请求数不超过池的容量。
+// 池无法接收请求时返回 RESOURCE_EXHAUSTED，客户端可以据此退避重试，
+// gRPC 服务因此获得基于池的准入控制。
+//
+// 示例:
+//
+//	pool, _ := laborer.NewPool(200, laborer.WithNonblocking(true))
+//	server := grpc.NewServer(
+//	    grpc.ChainUnaryInterceptor(laborergrpc.UnaryServerInterceptor(pool)),
+//	    grpc.ChainStreamInterceptor(laborergrpc.StreamServerInterceptor(pool)),
+//	)
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/kawaiirei0/laborer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Submitter 定义可以提交可取消任务的池
+//
+// laborer.Pool 实现了此接口。
+type Submitter interface {
+	SubmitContext(ctx context.Context, task func()) error
+}
+
+// UnaryServerInterceptor 返回在 pool 中执行一元 RPC 处理函数的拦截器
+//
+// 提交失败时处理函数不会被调用，错误按 Status 转换为 gRPC 状态返回给客户端。
+// 处理函数中的 panic 会在 gRPC 服务请求的 goroutine 中重新抛出，
+// 因此池的 panic 处理函数不会收到这些 panic，可以照常使用 recovery 拦截器。
+func UnaryServerInterceptor(pool Submitter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		var resp interface{}
+		err := run(ctx, pool, func() error {
+			var err error
+			resp, err = handler(ctx, req)
+			return err
+		})
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor 返回在 pool 中执行流式 RPC 处理函数的拦截器
+//
+// 流在整个生命周期内占用一个 worker，长连接的流会持续占用池的容量，
+// 如有需要可以为流式 RPC 使用单独的池。其余行为与 UnaryServerInterceptor 相同。
+func StreamServerInterceptor(pool Submitter) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return run(ss.Context(), pool, func() error {
+			return handler(srv, ss)
+		})
+	}
+}
+
+// run 在 pool 中执行 call 并等待其返回
+func run(ctx context.Context, pool Submitter, call func() error) error {
+	done := make(chan struct{})
+	var (
+		err       error
+		recovered interface{}
+	)
+	if serr := pool.SubmitContext(ctx, func() {
+		defer close(done)
+		defer func() {
+			recovered = recover()
+		}()
+		err = call()
+	}); serr != nil {
+		return Status(serr).Err()
+	}
+
+	<-done
+	if recovered != nil {
+		panic(recovered)
+	}
+	return err
+}
+
+// Status 将提交任务返回的错误转换为 gRPC 状态
+//
+//   - laborer.ErrPoolClosed、laborer.ErrDraining: UNAVAILABLE，服务正在关闭，客户端可以换一个后端
+//   - context.Canceled、context.DeadlineExceeded: CANCELED、DEADLINE_EXCEEDED
+//   - 其他错误（laborer.ErrPoolOverload 等）: RESOURCE_EXHAUSTED
+func Status(err error) *status.Status {
+	switch {
+	case errors.Is(err, laborer.ErrPoolClosed), errors.Is(err, laborer.ErrDraining):
+		return status.New(codes.Unavailable, err.Error())
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err)
+	default:
+		return status.New(codes.ResourceExhausted, err.Error())
+	}
+}
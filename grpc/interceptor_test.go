@@ -0,0 +1,132 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kawaiirei0/laborer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestUnaryServerInterceptor 测试一元处理函数在池中执行，池满时返回 RESOURCE_EXHAUSTED
+func TestUnaryServerInterceptor(t *testing.T) {
+	pool, err := laborer.NewPool(1, laborer.WithNonblocking(true))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	interceptor := UnaryServerInterceptor(pool)
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Call"}
+
+	started := make(chan struct{})
+	block := make(chan struct{})
+	slow := make(chan error)
+	go func() {
+		resp, err := interceptor(context.Background(), "req", info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			close(started)
+			<-block
+			return req.(string) + "-resp", nil
+		})
+		if resp != "req-resp" {
+			err = errors.New("响应不正确")
+		}
+		slow <- err
+	}()
+	<-started
+	if pool.Running() != 1 {
+		t.Errorf("处理函数应该在池的 worker 中执行，Running = %d", pool.Running())
+	}
+
+	_, err = interceptor(context.Background(), "req", info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		t.Error("提交失败时不应该调用处理函数")
+		return nil, nil
+	})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("期望 RESOURCE_EXHAUSTED，实际 %v", err)
+	}
+
+	close(block)
+	if err := <-slow; err != nil {
+		t.Errorf("处理函数执行失败: %v", err)
+	}
+
+	// 处理函数的错误原样返回
+	want := status.Error(codes.NotFound, "missing")
+	if _, err := interceptor(context.Background(), "req", info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, want
+	}); err != want {
+		t.Errorf("期望返回处理函数的错误，实际 %v", err)
+	}
+}
+
+// fakeStream 只提供 Context 的 grpc.ServerStream
+type fakeStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s fakeStream) Context() context.Context {
+	return s.ctx
+}
+
+// TestStreamServerInterceptor 测试流式处理函数在池中执行以及 panic 的重新抛出
+func TestStreamServerInterceptor(t *testing.T) {
+	pool, err := laborer.NewPool(1)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+
+	interceptor := StreamServerInterceptor(pool)
+	info := &grpc.StreamServerInfo{FullMethod: "/test.Service/Stream"}
+	ss := fakeStream{ctx: context.Background()}
+
+	ran := false
+	if err := interceptor(nil, ss, info, func(srv interface{}, stream grpc.ServerStream) error {
+		ran = pool.Running() == 1
+		return nil
+	}); err != nil || !ran {
+		t.Errorf("流式处理函数应该在池中执行，err = %v", err)
+	}
+
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Errorf("期望重新抛出 panic，实际 %v", r)
+			}
+		}()
+		_ = interceptor(nil, ss, info, func(srv interface{}, stream grpc.ServerStream) error {
+			panic("boom")
+		})
+	}()
+
+	// 池关闭后返回 UNAVAILABLE
+	pool.Release()
+	err = interceptor(nil, ss, info, func(srv interface{}, stream grpc.ServerStream) error { return nil })
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("期望 UNAVAILABLE，实际 %v", err)
+	}
+}
+
+// TestStatus 测试提交错误到 gRPC 状态码的转换
+func TestStatus(t *testing.T) {
+	tests := []struct {
+		err  error
+		code codes.Code
+	}{
+		{laborer.ErrPoolOverload, codes.ResourceExhausted},
+		{laborer.ErrPoolQuarantined, codes.ResourceExhausted},
+		{laborer.ErrPoolClosed, codes.Unavailable},
+		{laborer.ErrDraining, codes.Unavailable},
+		{context.Canceled, codes.Canceled},
+		{context.DeadlineExceeded, codes.DeadlineExceeded},
+	}
+	for _, tt := range tests {
+		if got := Status(tt.err).Code(); got != tt.code {
+			t.Errorf("%v: 期望 %v，实际 %v", tt.err, tt.code, got)
+		}
+	}
+}
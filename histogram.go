@@ -0,0 +1,206 @@
+package laborer
+
+import (
+	"math"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultLatencyBuckets 默认的延迟直方图桶上界
+//
+// 覆盖从 100 微秒到 10 秒的常见任务耗时范围，
+// 超过最后一个上界的样本计入 +Inf 桶。
+var DefaultLatencyBuckets = []time.Duration{
+	100 * time.Microsecond,
+	500 * time.Microsecond,
+	1 * time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	500 * time.Millisecond,
+	1 * time.Second,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// HistogramBucket 表示直方图中的一个桶。
+type HistogramBucket struct {
+	// UpperBound 桶的上界（包含），最后一个桶为 math.MaxInt64 表示 +Inf
+	UpperBound time.Duration
+
+	// Count 落入此桶的样本数量（非累积）
+	Count int64
+}
+
+// LatencyStats 表示一组延迟样本的统计摘要。
+//
+// 百分位数基于直方图桶估算，返回样本所在桶的上界（不超过 Max），
+// 精度取决于桶的划分。
+type LatencyStats struct {
+	// Count 样本数量
+	Count int64
+
+	// Sum 所有样本的延迟总和
+	Sum time.Duration
+
+	// Min 最小延迟
+	Min time.Duration
+
+	// Max 最大延迟
+	Max time.Duration
+
+	// Avg 平均延迟
+	Avg time.Duration
+
+	// P50 第 50 百分位延迟（估算）
+	P50 time.Duration
+
+	// P95 第 95 百分位延迟（估算）
+	P95 time.Duration
+
+	// P99 第 99 百分位延迟（估算）
+	P99 time.Duration
+
+	// Buckets 直方图各桶的样本数量
+	Buckets []HistogramBucket
+}
+
+// histogram 是一个无锁的定长桶直方图
+// 所有字段使用 atomic 操作更新，可以在 worker 中并发调用 observe
+type histogram struct {
+	bounds []time.Duration
+	counts []atomic.Int64
+	count  atomic.Int64
+	sum    atomic.Int64
+	min    atomic.Int64
+	max    atomic.Int64
+}
+
+// newHistogram 根据桶上界创建直方图
+// bounds 会被复制并排序，额外追加一个 +Inf 桶
+func newHistogram(bounds []time.Duration) *histogram {
+	b := make([]time.Duration, len(bounds))
+	copy(b, bounds)
+	sort.Slice(b, func(i, j int) bool { return b[i] < b[j] })
+
+	h := &histogram{
+		bounds: b,
+		counts: make([]atomic.Int64, len(b)+1),
+	}
+	h.min.Store(math.MaxInt64)
+	return h
+}
+
+// observe 记录一个延迟样本
+func (h *histogram) observe(d time.Duration) {
+	i := sort.Search(len(h.bounds), func(i int) bool { return h.bounds[i] >= d })
+	h.counts[i].Add(1)
+	h.count.Add(1)
+	h.sum.Add(int64(d))
+
+	for {
+		old := h.min.Load()
+		if int64(d) >= old || h.min.CompareAndSwap(old, int64(d)) {
+			break
+		}
+	}
+	for {
+		old := h.max.Load()
+		if int64(d) <= old || h.max.CompareAndSwap(old, int64(d)) {
+			break
+		}
+	}
+}
+
+// snapshot 生成当前直方图的统计摘要
+// 并发 observe 时各字段之间可能存在细微的不一致，对监控用途可以接受
+func (h *histogram) snapshot() LatencyStats {
+	s := LatencyStats{
+		Count:   h.count.Load(),
+		Buckets: make([]HistogramBucket, len(h.counts)),
+	}
+
+	for i := range h.counts {
+		upper := time.Duration(math.MaxInt64)
+		if i < len(h.bounds) {
+			upper = h.bounds[i]
+		}
+		s.Buckets[i] = HistogramBucket{UpperBound: upper, Count: h.counts[i].Load()}
+	}
+
+	if s.Count == 0 {
+		return s
+	}
+
+	s.Sum = time.Duration(h.sum.Load())
+	s.Min = time.Duration(h.min.Load())
+	s.Max = time.Duration(h.max.Load())
+	s.Avg = s.Sum / time.Duration(s.Count)
+	s.P50 = s.percentile(0.50)
+	s.P95 = s.percentile(0.95)
+	s.P99 = s.percentile(0.99)
+
+	return s
+}
+
+// merge 将另一个直方图快照合并到 s 中，并重新计算平均值和分位数
+// 两者的桶边界不同时只合并计数、总和和最值，不合并桶。
+func (s *LatencyStats) merge(o LatencyStats) {
+	if o.Count == 0 && len(o.Buckets) == 0 {
+		return
+	}
+	if s.Count == 0 && len(s.Buckets) == 0 {
+		*s = o
+		s.Buckets = append([]HistogramBucket(nil), o.Buckets...)
+		return
+	}
+
+	if len(s.Buckets) == len(o.Buckets) {
+		for i := range s.Buckets {
+			s.Buckets[i].Count += o.Buckets[i].Count
+		}
+	}
+
+	if o.Count == 0 {
+		return
+	}
+	if s.Count == 0 || o.Min < s.Min {
+		s.Min = o.Min
+	}
+	if o.Max > s.Max {
+		s.Max = o.Max
+	}
+	s.Count += o.Count
+	s.Sum += o.Sum
+	s.Avg = s.Sum / time.Duration(s.Count)
+	s.P50 = s.percentile(0.50)
+	s.P95 = s.percentile(0.95)
+	s.P99 = s.percentile(0.99)
+}
+
+// percentile 基于桶分布估算百分位数
+func (s *LatencyStats) percentile(q float64) time.Duration {
+	var total int64
+	for _, b := range s.Buckets {
+		total += b.Count
+	}
+	if total == 0 {
+		return 0
+	}
+
+	rank := int64(math.Ceil(q * float64(total)))
+	var cumulative int64
+	for _, b := range s.Buckets {
+		cumulative += b.Count
+		if cumulative >= rank {
+			if b.UpperBound > s.Max {
+				return s.Max
+			}
+			return b.UpperBound
+		}
+	}
+
+	return s.Max
+}
@@ -0,0 +1,135 @@
+// Package httpmw 提供在 laborer 池中执行 HTTP 处理函数的中间件，
+// 为 HTTP 服务提供内置的并发限制。
+//
+// 请求的处理函数在池的 worker 中执行，同时处理的请求数不超过池的容量；
+// 池过载时返回 503 Service Unavailable 和 Retry-After 头，而不是无限制地创建 goroutine。
+//
+// 示例:
+//
+//	pool, _ := laborer.NewPool(200, laborer.WithNonblocking(true))
+//	mux := http.NewServeMux()
+//	mux.Handle("/render", httpmw.Handler(pool, renderHandler))
+//
+//	// 或者作为中间件包装整个 mux
+//	http.ListenAndServe(":8080", httpmw.Middleware(pool)(mux))
+package httpmw
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// DefaultRetryAfter 过载响应中 Retry-After 头的默认值
+const DefaultRetryAfter = time.Second
+
+// Submitter 定义可以提交可取消任务的池
+//
+// laborer.Pool 实现了此接口。
+type Submitter interface {
+	SubmitContext(ctx context.Context, task func()) error
+}
+
+// Option 中间件的配置选项
+type Option func(*config)
+
+// config 中间件的配置
+type config struct {
+	// retryAfter 过载响应中 Retry-After 头的值，为 0 时不设置
+	retryAfter time.Duration
+
+	// overload 自定义的过载响应，为 nil 时返回 503
+	overload http.Handler
+}
+
+// WithRetryAfter 设置过载响应中 Retry-After 头的值，按秒向上取整
+// d 为 0 时不设置 Retry-After 头。默认值为 DefaultRetryAfter。
+func WithRetryAfter(d time.Duration) Option {
+	return func(c *config) {
+		c.retryAfter = d
+	}
+}
+
+// WithOverloadHandler 设置提交失败时的响应，替代默认的 503
+// 设置后 WithRetryAfter 不再生效，由 h 自行决定响应头。
+//
+// 示例:
+//
+//	httpmw.Handler(pool, api, httpmw.WithOverloadHandler(http.HandlerFunc(
+//	    func(w http.ResponseWriter, r *http.Request) {
+//	        w.Header().Set("Retry-After", "5")
+//	        http.Error(w, `{"error":"busy"}`, http.StatusTooManyRequests)
+//	    })))
+func WithOverloadHandler(h http.Handler) Option {
+	return func(c *config) {
+		c.overload = h
+	}
+}
+
+// handler 在池中执行 next 的 http.Handler
+type handler struct {
+	pool Submitter
+	next http.Handler
+	cfg  config
+}
+
+// Handler 返回在 pool 中执行 next 的 http.Handler
+//
+// 提交失败时（池过载、已关闭，或请求在等待 worker 时被取消）返回过载响应，next 不会被调用。
+// 阻塞模式的池会让请求排队等待 worker；非阻塞模式下池已满时，或等待 worker 的请求数
+// 已达 laborer.WithMaxBlockingTasks 设置的上限时，超出的请求立即得到过载响应。
+//
+// next 中的 panic 会在服务请求的 goroutine 中重新抛出，由 net/http 按通常的方式处理，
+// 因此池的 panic 处理函数不会收到这些 panic，worker 也不会因此退出。
+func Handler(pool Submitter, next http.Handler, opts ...Option) http.Handler {
+	cfg := config{retryAfter: DefaultRetryAfter}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return &handler{pool: pool, next: next, cfg: cfg}
+}
+
+// Middleware 返回在 pool 中执行被包装的 http.Handler 的中间件，行为与 Handler 相同
+func Middleware(pool Submitter, opts ...Option) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return Handler(pool, next, opts...)
+	}
+}
+
+// ServeHTTP 实现 http.Handler 接口
+// 等待 next 在 worker 中执行完毕后才返回，ResponseWriter 不会在请求结束后被使用。
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	done := make(chan struct{})
+	var recovered interface{}
+	err := h.pool.SubmitContext(r.Context(), func() {
+		defer close(done)
+		defer func() {
+			recovered = recover()
+		}()
+		h.next.ServeHTTP(w, r)
+	})
+	if err != nil {
+		h.overloaded(w, r)
+		return
+	}
+
+	<-done
+	if recovered != nil {
+		panic(recovered)
+	}
+}
+
+// overloaded 写入过载响应
+func (h *handler) overloaded(w http.ResponseWriter, r *http.Request) {
+	if h.cfg.overload != nil {
+		h.cfg.overload.ServeHTTP(w, r)
+		return
+	}
+
+	if h.cfg.retryAfter > 0 {
+		seconds := (h.cfg.retryAfter + time.Second - 1) / time.Second
+		w.Header().Set("Retry-After", strconv.FormatInt(int64(seconds), 10))
+	}
+	http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+}
@@ -0,0 +1,144 @@
+package httpmw
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kawaiirei0/laborer"
+)
+
+// TestHandler 测试处理函数在池中执行，池满时返回 503 和 Retry-After
+func TestHandler(t *testing.T) {
+	pool, err := laborer.NewPool(1, laborer.WithNonblocking(true))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	started := make(chan struct{})
+	block := make(chan struct{})
+	h := Handler(pool, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			close(started)
+			<-block
+		}
+		io.WriteString(w, "ok")
+	}), WithRetryAfter(1500*time.Millisecond))
+
+	slow := make(chan *httptest.ResponseRecorder)
+	go func() {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", "/slow", nil))
+		slow <- rec
+	}()
+	<-started
+	if pool.Running() != 1 {
+		t.Errorf("处理函数应该在池的 worker 中执行，Running = %d", pool.Running())
+	}
+
+	// 池已满，请求立即得到过载响应
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	if rec.Code != http.StatusServiceUnavailable || rec.Header().Get("Retry-After") != "2" {
+		t.Errorf("期望 503 和 Retry-After: 2，实际 %d %q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+
+	close(block)
+	if rec := <-slow; rec.Code != http.StatusOK || rec.Body.String() != "ok" {
+		t.Errorf("期望 200 ok，实际 %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+// TestHandlerMaxBlockingTasks 测试等待 worker 的请求数达到上限后超出的请求立即得到过载响应
+func TestHandlerMaxBlockingTasks(t *testing.T) {
+	pool, err := laborer.NewPool(1, laborer.WithMaxBlockingTasks(1))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	started := make(chan struct{}, 1)
+	block := make(chan struct{})
+	h := Handler(pool, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-block
+	}))
+
+	// 一个请求占住 worker，一个请求排队等待 worker
+	done := make(chan int, 2)
+	serve := func() {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+		done <- rec.Code
+	}
+	go serve()
+	<-started
+	go serve()
+	for pool.Waiting() != 1 {
+		time.Sleep(time.Millisecond)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("期望 503，实际 %d", rec.Code)
+	}
+
+	close(block)
+	for i := 0; i < 2; i++ {
+		if code := <-done; code != http.StatusOK {
+			t.Errorf("期望 200，实际 %d", code)
+		}
+	}
+}
+
+// TestMiddlewareOverloadHandler 测试自定义过载响应
+func TestMiddlewareOverloadHandler(t *testing.T) {
+	pool, err := laborer.NewPool(1)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	pool.Release()
+
+	mw := Middleware(pool, WithOverloadHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "busy", http.StatusTooManyRequests)
+	})))
+	h := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("提交失败时不应该调用处理函数")
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "" {
+		t.Errorf("期望自定义的 429 响应，实际 %d %q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+}
+
+// TestHandlerPanic 测试处理函数的 panic 在服务请求的 goroutine 中重新抛出
+func TestHandlerPanic(t *testing.T) {
+	pool, err := laborer.NewPool(1)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	h := Handler(pool, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	func() {
+		defer func() {
+			if r := recover(); r != http.ErrAbortHandler {
+				t.Errorf("期望重新抛出 http.ErrAbortHandler，实际 %v", r)
+			}
+		}()
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	}()
+
+	if stats := pool.Stats(); stats.Panicked != 0 {
+		t.Errorf("池不应该记录处理函数的 panic，实际 %d", stats.Panicked)
+	}
+}
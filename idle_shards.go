@@ -0,0 +1,177 @@
+package laborer
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// idleWorker 可以放入分片空闲缓存或自定义队列的 worker
+type idleWorker interface {
+	comparable
+	Worker
+
+	// idleSince 返回 worker 最后一次执行完任务的时间
+	idleSince() time.Time
+
+	// workerID 返回 worker 在池内的编号
+	workerID() int
+
+	// expire 标记 worker 为过期并结束 worker
+	expire()
+
+	// finish 结束 worker
+	finish()
+}
+
+// idleBucket 一个分片桶，拥有独立的锁
+type idleBucket[W idleWorker] struct {
+	lock  sync.Mutex
+	items []W
+
+	// 填充到缓存行大小，避免相邻的桶伪共享
+	_ [32]byte
+}
+
+// idleShards 按桶分片的空闲 worker 缓存
+//
+// 启用 WithShardedLocking 后，归还的 worker 放入当前 P 对应的桶，
+// 获取 worker 时从当前 P 的桶开始查找，并发的提交和归还分散在 GOMAXPROCS 个锁上，
+// 而不是都在池的锁上串行。阻塞等待者的队列不分片，仍由池的锁保护。
+// 未启用时 buckets 为空，所有方法都不做任何事。
+type idleShards[W idleWorker] struct {
+	buckets []idleBucket[W]
+
+	// count 所有桶中的 worker 总数，为 0 时获取 worker 不必逐个检查桶
+	count atomic.Int32
+
+	// homes 缓存桶编号，近似当前 P 对应的桶
+	// sync.Pool 为每个 P 维护本地缓存，Get 通常取回同一个 P 上次 Put 的编号，
+	// 因此同一个 P 上的提交和归还落在同一个桶，不需要共享的随机数源。
+	homes sync.Pool
+
+	// next 下一个分配给新 P 的桶编号
+	next atomic.Uint32
+}
+
+// init 创建 GOMAXPROCS 个桶
+func (s *idleShards[W]) init() {
+	s.buckets = make([]idleBucket[W], runtime.GOMAXPROCS(0))
+	s.homes.New = func() interface{} {
+		home := int(s.next.Add(1)-1) % len(s.buckets)
+		return &home
+	}
+}
+
+// home 返回当前 P 对应的桶编号
+func (s *idleShards[W]) home() int {
+	h := s.homes.Get().(*int)
+	home := *h
+	s.homes.Put(h)
+	return home
+}
+
+// enabled 返回是否启用了分片
+func (s *idleShards[W]) enabled() bool {
+	return len(s.buckets) > 0
+}
+
+// len 返回所有桶中的 worker 总数
+func (s *idleShards[W]) len() int {
+	return int(s.count.Load())
+}
+
+// push 将 worker 放入当前 P 对应的桶
+func (s *idleShards[W]) push(w W) {
+	b := &s.buckets[s.home()]
+	b.lock.Lock()
+	b.items = append(b.items, w)
+	s.count.Add(1)
+	b.lock.Unlock()
+}
+
+// pop 从当前 P 对应的桶开始查找并取出一个 worker，没有空闲 worker 时返回零值
+func (s *idleShards[W]) pop() W {
+	var zero W
+	if s.count.Load() <= 0 {
+		return zero
+	}
+
+	n := len(s.buckets)
+	start := s.home()
+	for k := 0; k < n; k++ {
+		b := &s.buckets[(start+k)%n]
+		b.lock.Lock()
+		if last := len(b.items) - 1; last >= 0 {
+			w := b.items[last]
+			b.items[last] = zero
+			b.items = b.items[:last]
+			s.count.Add(-1)
+			b.lock.Unlock()
+			return w
+		}
+		b.lock.Unlock()
+	}
+	return zero
+}
+
+// each 遍历所有桶中的 worker
+func (s *idleShards[W]) each(fn func(w W)) {
+	for i := range s.buckets {
+		b := &s.buckets[i]
+		b.lock.Lock()
+		for _, w := range b.items {
+			fn(w)
+		}
+		b.lock.Unlock()
+	}
+}
+
+// refresh 结束空闲超过 duration 的 worker，返回它们的编号和空闲时长
+func (s *idleShards[W]) refresh(now time.Time, duration time.Duration) []expiredWorker {
+	if s.count.Load() <= 0 {
+		return nil
+	}
+
+	var zero W
+	var expired []expiredWorker
+	expiryTime := now.Add(-duration)
+	for i := range s.buckets {
+		b := &s.buckets[i]
+		b.lock.Lock()
+		kept := b.items[:0]
+		for _, w := range b.items {
+			if w.idleSince().Before(expiryTime) {
+				expired = append(expired, newExpiredWorker(w, now))
+				w.expire()
+				continue
+			}
+			kept = append(kept, w)
+		}
+		for j := len(kept); j < len(b.items); j++ {
+			b.items[j] = zero
+		}
+		s.count.Add(-int32(len(b.items) - len(kept)))
+		b.items = kept
+		b.lock.Unlock()
+	}
+
+	return expired
+}
+
+// reset 结束所有桶中的 worker
+func (s *idleShards[W]) reset() {
+	var zero W
+	for i := range s.buckets {
+		b := &s.buckets[i]
+		b.lock.Lock()
+		for j, w := range b.items {
+			w.finish()
+			b.items[j] = zero
+		}
+		s.count.Add(-int32(len(b.items)))
+		b.items = b.items[:0]
+		b.lock.Unlock()
+	}
+}
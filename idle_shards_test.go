@@ -0,0 +1,97 @@
+package laborer
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestShardedLocking 测试启用分片锁后的并发提交和关闭
+func TestShardedLocking(t *testing.T) {
+	pool, err := NewPool(-1, WithShardedLocking(true))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+
+	var counter int32
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				if err := pool.Submit(func() { atomic.AddInt32(&counter, 1) }); err != nil {
+					t.Errorf("提交任务失败: %v", err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&counter) != 1600 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if n := atomic.LoadInt32(&counter); n != 1600 {
+		t.Errorf("期望执行 1600 个任务，实际 %d 个", n)
+	}
+
+	// 空闲 worker 在分片缓存中，同样计入 IdleWorkers 并在关闭时结束
+	deadline = time.Now().Add(time.Second)
+	for pool.IdleWorkers() != pool.Running() && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if len(pool.Workers()) != pool.IdleWorkers() {
+		t.Errorf("Workers 与 IdleWorkers 不一致: %d != %d", len(pool.Workers()), pool.IdleWorkers())
+	}
+	pool.Release()
+	deadline = time.Now().Add(time.Second)
+	for pool.Running() != 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if pool.Running() != 0 || pool.IdleWorkers() != 0 {
+		t.Errorf("期望关闭后没有 worker，实际 Running=%d IdleWorkers=%d", pool.Running(), pool.IdleWorkers())
+	}
+}
+
+// TestShardedLockingWithFunc 测试函数池启用分片锁后的阻塞等待和过期回收
+func TestShardedLockingWithFunc(t *testing.T) {
+	var wg sync.WaitGroup
+	pool, err := NewPoolWithFunc(2, func(interface{}) {
+		time.Sleep(100 * time.Microsecond)
+		wg.Done()
+	}, WithShardedLocking(true), WithExpiryDuration(20*time.Millisecond), WithCleanInterval(5*time.Millisecond))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	// 并发的调用方多于容量，会进入阻塞等待
+	var callers sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		callers.Add(1)
+		go func() {
+			defer callers.Done()
+			for i := 0; i < 25; i++ {
+				wg.Add(1)
+				if err := pool.Invoke(i); err != nil {
+					wg.Done()
+					t.Errorf("提交任务失败: %v", err)
+					return
+				}
+			}
+		}()
+	}
+	callers.Wait()
+	wg.Wait()
+
+	deadline := time.Now().Add(time.Second)
+	for pool.Free() != 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if pool.Free() != 0 {
+		t.Errorf("期望空闲 worker 过期后被回收，实际 Free=%d", pool.Free())
+	}
+}
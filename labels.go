@@ -0,0 +1,38 @@
+package laborer
+
+import (
+	"context"
+	"runtime/pprof"
+)
+
+// pprof 标签的键
+const (
+	// PprofLabelPool 标识任务所属池的 pprof 标签键
+	PprofLabelPool = "laborer_pool"
+
+	// PprofLabelTask 标识任务名称的 pprof 标签键，只在执行通过 SubmitNamed 提交的任务时设置
+	PprofLabelTask = "laborer_task"
+)
+
+// setWorkerLabels 为当前 worker goroutine 设置 pprof 标签
+// 池名称在 worker 的整个生命周期内不变，因此只需在 worker 启动时设置一次，
+// 不会给每个任务带来额外开销。
+func setWorkerLabels(opts *Options) {
+	if !opts.PprofLabels {
+		return
+	}
+
+	ctx := pprof.WithLabels(context.Background(), pprof.Labels(PprofLabelPool, opts.Name))
+	pprof.SetGoroutineLabels(ctx)
+}
+
+// setTaskLabels 在执行有名称的任务期间为 worker goroutine 加上任务名称标签
+// 只有有名称的任务才会设置，任务结束后由 setWorkerLabels 恢复为 worker 的标签。
+func setTaskLabels(opts *Options, name string) {
+	if !opts.PprofLabels {
+		return
+	}
+
+	ctx := pprof.WithLabels(context.Background(), pprof.Labels(PprofLabelPool, opts.Name, PprofLabelTask, name))
+	pprof.SetGoroutineLabels(ctx)
+}
@@ -0,0 +1,68 @@
+package laborer
+
+import (
+	"bytes"
+	"runtime/pprof"
+	"testing"
+)
+
+// TestPprofLabels 测试 worker goroutine 的 pprof 标签
+func TestPprofLabels(t *testing.T) {
+	pool, err := NewPool(1, WithName("labeled"), WithPprofLabels(true))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	if err := pool.Submit(func() {
+		close(started)
+		<-release
+	}); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	<-started
+
+	var buf bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 1); err != nil {
+		t.Fatalf("获取 goroutine profile 失败: %v", err)
+	}
+	close(release)
+
+	if !bytes.Contains(buf.Bytes(), []byte(`"laborer_pool":"labeled"`)) {
+		t.Error("goroutine profile 中应该包含池名称标签")
+	}
+}
+
+// TestPprofTaskLabel 测试执行有名称的任务期间 worker goroutine 带有任务名称标签
+func TestPprofTaskLabel(t *testing.T) {
+	pool, err := NewPool(1, WithName("labeled"), WithPprofLabels(true))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	if err := pool.SubmitNamed("resize", func() {
+		close(started)
+		<-release
+	}); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	<-started
+
+	var buf bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 1); err != nil {
+		t.Fatalf("获取 goroutine profile 失败: %v", err)
+	}
+	close(release)
+
+	if !bytes.Contains(buf.Bytes(), []byte(`"laborer_task":"resize"`)) {
+		t.Error("goroutine profile 中应该包含任务名称标签")
+	}
+	if !bytes.Contains(buf.Bytes(), []byte(`"laborer_pool":"labeled"`)) {
+		t.Error("任务名称标签不应该覆盖池名称标签")
+	}
+}
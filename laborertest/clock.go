@@ -0,0 +1,82 @@
+package laborertest
+
+import (
+	"sync"
+	"time"
+)
+
+// FakeClock 由测试控制的时钟，实现 laborer.Clock
+//
+// 时间只在调用 Advance 时前进，到期的 ticker 在 Advance 中触发。
+// 与 laborer.WithClock 配合使用，推进时间即可触发空闲 worker 的过期回收。
+//
+// 示例:
+//
+//	clock := laborertest.NewFakeClock(time.Now())
+//	pool, _ := laborer.NewPool(10,
+//	    laborer.WithExpiryDuration(time.Minute),
+//	    laborer.WithClock(clock))
+//
+//	clock.Advance(2 * time.Minute)
+//	laborertest.ExpectRunning(t, pool, 0)
+type FakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	tickers map[*fakeTicker]struct{}
+}
+
+// fakeTicker FakeClock 创建的 ticker
+type fakeTicker struct {
+	c      chan time.Time
+	period time.Duration
+	next   time.Time
+}
+
+// NewFakeClock 创建当前时间为 now 的时钟
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now, tickers: make(map[*fakeTicker]struct{})}
+}
+
+// Now 返回时钟的当前时间
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// NewTicker 创建每隔 d 触发一次的 ticker，d 必须为正数
+// 与 time.Ticker 一样，channel 只缓冲一次触发，接收方来不及读取时多余的触发被丢弃。
+func (c *FakeClock) NewTicker(d time.Duration) (<-chan time.Time, func()) {
+	if d <= 0 {
+		panic("laborertest: non-positive interval for NewTicker")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTicker{c: make(chan time.Time, 1), period: d, next: c.now.Add(d)}
+	c.tickers[t] = struct{}{}
+	return t.c, func() {
+		c.mu.Lock()
+		delete(c.tickers, t)
+		c.mu.Unlock()
+	}
+}
+
+// Advance 将时间推进 d，并触发在此期间到期的 ticker
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	for t := range c.tickers {
+		if t.next.After(c.now) {
+			continue
+		}
+		select {
+		case t.c <- c.now:
+		default:
+		}
+		for !t.next.After(c.now) {
+			t.next = t.next.Add(t.period)
+		}
+	}
+}
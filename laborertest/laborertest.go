@@ -0,0 +1,69 @@
+// Package laborertest 提供测试使用 laborer 的代码的辅助工具。
+//
+//   - SyncPool 在提交方的 goroutine 中同步执行任务，实现 laborer.PoolInterface，
+//     被测代码提交的任务在 Submit 返回时已经执行完毕。
+//   - FakeClock 由测试控制的时钟，通过 laborer.WithClock 设置后推进时间即可触发过期回收。
+//   - WaitForIdle 和 ExpectRunning 等待池到达预期的状态，超时时使测试失败。
+//
+// 示例:
+//
+//	func TestExpiry(t *testing.T) {
+//	    clock := laborertest.NewFakeClock(time.Now())
+//	    pool, _ := laborer.NewPool(10,
+//	        laborer.WithExpiryDuration(time.Minute),
+//	        laborer.WithClock(clock))
+//	    defer pool.Release()
+//
+//	    pool.Submit(task)
+//	    laborertest.WaitForIdle(t, pool)
+//	    laborertest.ExpectRunning(t, pool, 1)
+//
+//	    clock.Advance(2 * time.Minute)
+//	    laborertest.ExpectRunning(t, pool, 0)
+//	}
+package laborertest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kawaiirei0/laborer"
+)
+
+// DefaultTimeout WaitForIdle 和 ExpectRunning 等待的最长时间
+const DefaultTimeout = 5 * time.Second
+
+// Pool 定义辅助函数检查的池
+//
+// laborer.Pool、laborer.PoolWithFunc 和 SyncPool 实现了此接口。
+// 辅助函数在池的计数变化时被唤醒，而不是反复检查状态。
+type Pool interface {
+	Running() int
+	Stats() laborer.Stats
+	WaitWithTimeout(timeout time.Duration) error
+	WaitRunning(ctx context.Context, n int) error
+}
+
+// WaitForIdle 等待池中已提交的任务全部执行结束
+// 没有等待中的任务且已提交的任务都已完成或 panic 时返回，
+// 超过 DefaultTimeout 时以 t.Fatalf 结束测试。
+func WaitForIdle(t testing.TB, pool Pool) {
+	t.Helper()
+	err := pool.WaitWithTimeout(DefaultTimeout)
+	if s := pool.Stats(); err != nil || s.Waiting != 0 || s.Submitted != s.Completed+s.Panicked {
+		t.Fatalf("laborertest: pool not idle after %v: submitted=%d completed=%d panicked=%d waiting=%d",
+			DefaultTimeout, s.Submitted, s.Completed, s.Panicked, s.Waiting)
+	}
+}
+
+// ExpectRunning 等待池中运行的 worker 数量变为 n
+// worker 的创建和回收是异步的，因此最多等待 DefaultTimeout，超时时以 t.Errorf 报告。
+func ExpectRunning(t testing.TB, pool Pool, n int) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+	defer cancel()
+	if err := pool.WaitRunning(ctx, n); err != nil {
+		t.Errorf("laborertest: expected %d running workers, got %d", n, pool.Running())
+	}
+}
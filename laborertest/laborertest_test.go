@@ -0,0 +1,127 @@
+package laborertest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kawaiirei0/laborer"
+)
+
+// TestSyncPool 测试任务在 Submit 返回前执行完毕
+func TestSyncPool(t *testing.T) {
+	var recovered interface{}
+	pool := NewSyncPool(1, laborer.WithPanicHandler(func(r interface{}) { recovered = r }))
+
+	n := 0
+	for i := 0; i < 10; i++ {
+		if err := pool.Submit(func() { n++ }); err != nil {
+			t.Fatalf("提交任务失败: %v", err)
+		}
+		if n != i+1 {
+			t.Fatalf("Submit 返回时任务应该已经执行，期望 %d，实际 %d", i+1, n)
+		}
+	}
+
+	// 任务中再次提交时池已满
+	var nested error
+	_ = pool.Submit(func() { nested = pool.Submit(func() {}) })
+	if nested != laborer.ErrPoolOverload {
+		t.Errorf("池已满时期望返回 ErrPoolOverload，实际 %v", nested)
+	}
+
+	f, err := pool.SubmitWithResult(func() (interface{}, error) { panic("boom") })
+	if err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	var pe *laborer.PanicError
+	if _, err := f.Get(); !errors.As(err, &pe) || pe.Value != "boom" {
+		t.Errorf("期望 Future 返回 PanicError，实际 %v", err)
+	}
+	if recovered != "boom" {
+		t.Errorf("期望 PanicHandler 收到 boom，实际 %v", recovered)
+	}
+
+	s := pool.Stats()
+	if s.Submitted != 12 || s.Completed != 11 || s.Panicked != 1 || s.Rejected != 1 {
+		t.Errorf("统计不正确: %+v", s)
+	}
+	if err := s.Check(); err != nil {
+		t.Errorf("统计违反不变量: %v", err)
+	}
+
+	// 其他 goroutine 中的提交返回前运行计数不为 0
+	release := make(chan struct{})
+	go pool.Submit(func() { <-release })
+	ExpectRunning(t, pool, 1)
+	close(release)
+	if err := pool.ReleaseTimeout(time.Second); err != nil {
+		t.Errorf("等待提交返回失败: %v", err)
+	}
+	ExpectRunning(t, pool, 0)
+
+	if err := pool.Submit(func() {}); err != laborer.ErrPoolClosed {
+		t.Errorf("关闭后期望返回 ErrPoolClosed，实际 %v", err)
+	}
+	pool.Reboot()
+	if err := pool.Submit(func() {}); err != nil {
+		t.Errorf("重启后提交失败: %v", err)
+	}
+}
+
+// TestFakeClockExpiry 测试推进时钟触发空闲 worker 的过期回收
+func TestFakeClockExpiry(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	pool, err := laborer.NewPool(10, laborer.WithExpiryDuration(time.Minute), laborer.WithClock(clock))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	for i := 0; i < 3; i++ {
+		_ = pool.Submit(func() { time.Sleep(time.Millisecond) })
+	}
+	WaitForIdle(t, pool)
+	ExpectRunning(t, pool, 3)
+
+	// 未到过期时间时扫描不回收
+	clock.Advance(30 * time.Second)
+	time.Sleep(10 * time.Millisecond)
+	if pool.Running() != 3 {
+		t.Errorf("未过期的 worker 不应该被回收，运行 %d", pool.Running())
+	}
+
+	clock.Advance(time.Minute)
+	ExpectRunning(t, pool, 0)
+}
+
+// TestFakeClockTicker 测试 ticker 在到期时只触发一次并可以停止
+func TestFakeClockTicker(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	tick, stop := clock.NewTicker(time.Second)
+
+	clock.Advance(500 * time.Millisecond)
+	select {
+	case <-tick:
+		t.Fatal("未到期的 ticker 不应该触发")
+	default:
+	}
+
+	clock.Advance(5 * time.Second)
+	if got := <-tick; !got.Equal(time.Unix(5, 5e8)) {
+		t.Errorf("期望触发时间为推进后的时间，实际 %v", got)
+	}
+	select {
+	case <-tick:
+		t.Fatal("一次推进只应该缓冲一次触发")
+	default:
+	}
+
+	stop()
+	clock.Advance(time.Hour)
+	select {
+	case <-tick:
+		t.Fatal("停止后的 ticker 不应该触发")
+	default:
+	}
+}
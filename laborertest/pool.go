@@ -0,0 +1,267 @@
+package laborertest
+
+import (
+	"context"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kawaiirei0/laborer"
+)
+
+// SyncPool 在提交方的 goroutine 中同步执行任务的池，实现 laborer.PoolInterface
+//
+// Submit 返回时任务已经执行完毕，测试不必等待或猜测任务何时完成。
+// 任务不会排队：池已满（例如任务中再次提交）时返回 laborer.ErrPoolOverload。
+// 任务的 panic 被恢复后交给 PanicHandlerV2 或 PanicHandler，都未设置时写入日志，
+// 带返回值的任务通过 Future 得到 *laborer.PanicError。
+//
+// 示例:
+//
+//	pool := laborertest.NewSyncPool(10)
+//	svc := NewService(pool) // 接收 laborer.PoolInterface 的代码
+//	svc.Handle(req)
+//	// 此时 svc 提交的任务都已执行完毕
+type SyncPool struct {
+	size    int
+	options *laborer.Options
+
+	closed  atomic.Bool
+	running atomic.Int64
+
+	// changed 运行计数变化时关闭并替换的 channel，ReleaseTimeout 和 WaitRunning 在其上等待
+	changedMu sync.Mutex
+	changed   chan struct{}
+
+	submitted atomic.Int64
+	completed atomic.Int64
+	rejected  atomic.Int64
+	failed    atomic.Int64
+	panicked  atomic.Int64
+
+	// panicMu 串行化 panic 处理函数的调用
+	panicMu sync.Mutex
+}
+
+var _ laborer.PoolInterface = (*SyncPool)(nil)
+
+// NewSyncPool 创建容量为 size 的同步池，size 为 -1 表示无限容量
+// options 中只有 Name、PanicHandler、PanicHandlerV2 和 Logger 生效。
+func NewSyncPool(size int, options ...laborer.Option) *SyncPool {
+	if size <= 0 {
+		size = -1
+	}
+	return &SyncPool{size: size, options: laborer.NewOptions(options...)}
+}
+
+// Submit 在当前 goroutine 中执行任务
+func (p *SyncPool) Submit(task func()) error {
+	if task == nil {
+		return laborer.ErrNilTask
+	}
+	if err := p.acquire(); err != nil {
+		return err
+	}
+	defer p.leave()
+
+	if !p.run(task, nil) {
+		return nil
+	}
+	p.completed.Add(1)
+	return nil
+}
+
+// SubmitWithResult 在当前 goroutine 中执行任务，返回已完成的 Future
+func (p *SyncPool) SubmitWithResult(task func() (interface{}, error)) (laborer.Future, error) {
+	if task == nil {
+		return nil, laborer.ErrNilTask
+	}
+	if err := p.acquire(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	promise := laborer.NewPromise()
+	var result interface{}
+	var err error
+	if !p.run(func() { result, err = task() }, promise) {
+		return promise.Future(), nil
+	}
+	if err != nil {
+		p.failed.Add(1)
+	}
+	p.completed.Add(1)
+	promise.Complete(result, err)
+	return promise.Future(), nil
+}
+
+// acquire 占用一个运行名额
+func (p *SyncPool) acquire() error {
+	if p.closed.Load() {
+		return laborer.ErrPoolClosed
+	}
+	if running := p.running.Add(1); p.size != -1 && running > int64(p.size) {
+		p.running.Add(-1)
+		p.rejected.Add(1)
+		return laborer.ErrPoolOverload
+	}
+	p.submitted.Add(1)
+	p.notify()
+	return nil
+}
+
+// leave 归还 acquire 占用的运行名额
+func (p *SyncPool) leave() {
+	p.running.Add(-1)
+	p.notify()
+}
+
+// notify 唤醒等待运行计数变化的 goroutine
+func (p *SyncPool) notify() {
+	p.changedMu.Lock()
+	if p.changed != nil {
+		close(p.changed)
+		p.changed = nil
+	}
+	p.changedMu.Unlock()
+}
+
+// awaitRunning 等待运行计数变为 n
+// 先取得 channel 再检查计数，计数在两者之间变化时 channel 已被关闭，通知不会丢失。
+func (p *SyncPool) awaitRunning(ctx context.Context, n int) error {
+	for {
+		p.changedMu.Lock()
+		if p.changed == nil {
+			p.changed = make(chan struct{})
+		}
+		ch := p.changed
+		p.changedMu.Unlock()
+
+		if p.Running() == n {
+			return nil
+		}
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			if p.Running() == n {
+				return nil
+			}
+			return ctx.Err()
+		}
+	}
+}
+
+// run 执行任务并恢复 panic，任务正常结束时返回 true
+// promise 不为 nil 时将 panic 作为 *laborer.PanicError 传给它。
+func (p *SyncPool) run(task func(), promise *laborer.Promise) (ok bool) {
+	defer func() {
+		if ok {
+			return
+		}
+		r := recover()
+		stack := debug.Stack()
+		p.panicked.Add(1)
+		if promise != nil {
+			promise.Complete(nil, &laborer.PanicError{Pool: p.options.Name, Value: r, Stack: stack})
+		}
+
+		p.panicMu.Lock()
+		defer p.panicMu.Unlock()
+		switch {
+		case p.options.PanicHandlerV2 != nil:
+			p.options.PanicHandlerV2(r, stack, laborer.TaskInfo{Pool: p.options.Name, Panic: r})
+		case p.options.PanicHandler != nil:
+			p.options.PanicHandler(r)
+		default:
+			p.options.Logger.Printf("laborertest: task panic: %v\n%s", r, stack)
+		}
+	}()
+
+	task()
+	return true
+}
+
+// Release 关闭池，之后的提交返回 laborer.ErrPoolClosed
+func (p *SyncPool) Release() {
+	p.closed.Store(true)
+}
+
+// ReleaseTimeout 关闭池
+// 任务在提交时同步执行，只有其他 goroutine 中尚未返回的提交需要等待，超时返回包装了 laborer.ErrTimeout 的错误。
+func (p *SyncPool) ReleaseTimeout(timeout time.Duration) error {
+	p.Release()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := p.awaitRunning(ctx, 0); err != nil {
+		return laborer.ErrTimeout
+	}
+	return nil
+}
+
+// WaitWithTimeout 等待其他 goroutine 中尚未返回的提交执行结束，超时返回 laborer.ErrTimeout
+func (p *SyncPool) WaitWithTimeout(timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := p.awaitRunning(ctx, 0); err != nil {
+		return laborer.ErrTimeout
+	}
+	return nil
+}
+
+// WaitRunning 阻塞直到正在执行的任务数量变为 n，ctx 被取消时返回 ctx.Err()
+func (p *SyncPool) WaitRunning(ctx context.Context, n int) error {
+	return p.awaitRunning(ctx, n)
+}
+
+// Reboot 重新打开已关闭的池
+func (p *SyncPool) Reboot() {
+	p.closed.Store(false)
+}
+
+// PurgeNow 同步池没有空闲的 worker，总是返回 0
+func (p *SyncPool) PurgeNow() int {
+	return 0
+}
+
+// Running 返回正在执行的任务数量
+func (p *SyncPool) Running() int {
+	return int(p.running.Load())
+}
+
+// Free 返回剩余的容量，无限容量的池返回 -1
+func (p *SyncPool) Free() int {
+	if p.size == -1 {
+		return -1
+	}
+	return p.size - p.Running()
+}
+
+// Cap 返回池的容量
+func (p *SyncPool) Cap() int {
+	return p.size
+}
+
+// Waiting 同步池的任务不排队，总是返回 0
+func (p *SyncPool) Waiting() int {
+	return 0
+}
+
+// IsClosed 返回池是否已关闭
+func (p *SyncPool) IsClosed() bool {
+	return p.closed.Load()
+}
+
+// Stats 返回池的运行状态快照
+func (p *SyncPool) Stats() laborer.Stats {
+	return laborer.Stats{
+		Running:   p.Running(),
+		Free:      p.Free(),
+		Cap:       p.size,
+		Submitted: p.submitted.Load(),
+		Completed: p.completed.Load(),
+		Rejected:  p.rejected.Load(),
+		Failed:    p.failed.Load(),
+		Panicked:  p.panicked.Load(),
+	}
+}
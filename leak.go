@@ -0,0 +1,94 @@
+package laborer
+
+import (
+	"context"
+	"time"
+)
+
+// LeakReport 表示池关闭后超过 LeakCheckGrace 仍未退出的 worker。
+type LeakReport struct {
+	// Pool 池的名称
+	Pool string `json:"pool"`
+
+	// Running 运行计数中仍未退出的 worker 数量，包括溢出 worker
+	Running int `json:"running"`
+
+	// Workers 仍未退出的 worker 及其栈，空闲的 worker BusyFor 为 0
+	Workers []WorkerStack `json:"workers"`
+}
+
+// all 返回所有存活的 worker
+func (s *workerSet) all() []*workerState {
+	var out []*workerState
+	s.m.Range(func(key, _ interface{}) bool {
+		out = append(out, key.(*workerState))
+		return true
+	})
+	return out
+}
+
+// checkLeaks 在池关闭后启动泄漏检查，未配置 LeakCheckGrace 时不做任何事
+// 在后台的 quiet 上等待最多 LeakCheckGrace，直到 running 降为 0 且所有 worker 都已退出；
+// 期间池被重启（reopened 返回 true）时放弃检查。
+func checkLeaks(opts *Options, quiet *zeroSignal, workers *workerSet, running func() int, reopened func() bool) {
+	if opts.LeakCheckGrace <= 0 {
+		return
+	}
+
+	go func() {
+		// worker 退出时扣减运行计数并移出存活集合之后才通知，每次通知后重新检查两者
+		remaining := func() int {
+			if reopened() {
+				return 0
+			}
+			return running() + len(workers.all())
+		}
+		if _, err := quiet.await(context.Background(), time.Now().Add(opts.LeakCheckGrace), remaining); err == nil {
+			return
+		}
+		if reopened() {
+			return
+		}
+
+		reportLeak(opts, LeakReport{
+			Pool:    opts.Name,
+			Running: running(),
+			Workers: leakedStacks(workers.all(), time.Now().UnixNano()),
+		})
+	}()
+}
+
+// leakedStacks 获取未退出的 worker 的栈信息，包括空闲的 worker
+func leakedStacks(workers []*workerState, now int64) []WorkerStack {
+	if len(workers) == 0 {
+		return nil
+	}
+
+	stacks := allStacks()
+	out := make([]WorkerStack, 0, len(workers))
+	for _, w := range workers {
+		s := WorkerStack{
+			WorkerID:  w.id,
+			Goroutine: w.gid,
+			Stack:     stacks[w.gid],
+		}
+		if since := w.busySince.Load(); since != 0 {
+			s.BusyFor = time.Duration(now - since)
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// reportLeak 上报泄漏，优先调用 OnLeak，未设置时写入日志
+func reportLeak(opts *Options, r LeakReport) {
+	if opts.OnLeak != nil {
+		opts.OnLeak(r)
+		return
+	}
+	opts.logEvent(LevelWarn, "workers_leaked", Field{"running", r.Running}, Field{"grace", opts.LeakCheckGrace})
+	for _, s := range r.Workers {
+		opts.logEvent(LevelWarn, "worker_leaked", Field{"worker_id", s.WorkerID}, Field{"goroutine", s.Goroutine},
+			Field{"busy_for", s.BusyFor}, Field{"stack", s.Stack})
+	}
+}
@@ -0,0 +1,64 @@
+package laborer
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestLeakCheck 测试关闭后仍未退出的 worker 被上报
+func TestLeakCheck(t *testing.T) {
+	leaks := make(chan LeakReport, 1)
+	pool, err := NewPool(2, WithName("leaky"), WithLeakCheck(20*time.Millisecond, func(r LeakReport) {
+		leaks <- r
+	}))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+
+	release := make(chan struct{})
+	defer close(release)
+	if err := pool.Submit(func() { stuckTask(release) }); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	pool.Release()
+
+	select {
+	case r := <-leaks:
+		if r.Pool != "leaky" || r.Running != 1 || len(r.Workers) != 1 {
+			t.Fatalf("泄漏报告不正确: %+v", r)
+		}
+		if !strings.Contains(r.Workers[0].Stack, "stuckTask") {
+			t.Errorf("栈中应该包含未结束的任务函数:\n%s", r.Workers[0].Stack)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("应该上报未退出的 worker")
+	}
+}
+
+// TestLeakCheckClean 测试所有 worker 正常退出时不上报
+func TestLeakCheckClean(t *testing.T) {
+	leaks := make(chan LeakReport, 1)
+	pool, err := NewPoolWithFunc(4, func(interface{}) {
+		time.Sleep(5 * time.Millisecond)
+	}, WithLeakCheck(50*time.Millisecond, func(r LeakReport) { leaks <- r }))
+	if err != nil {
+		t.Fatalf("创建函数池失败: %v", err)
+	}
+
+	for i := 0; i < 8; i++ {
+		if err := pool.Invoke(i); err != nil {
+			t.Fatalf("Invoke失败: %v", err)
+		}
+	}
+	if err := pool.ReleaseTimeout(time.Second); err != nil {
+		t.Fatalf("关闭池失败: %v", err)
+	}
+
+	select {
+	case r := <-leaks:
+		t.Errorf("不应该上报泄漏: %+v", r)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
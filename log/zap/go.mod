@@ -0,0 +1,12 @@
+module github.com/kawaiirei0/laborer/log/zap
+
+go 1.21
+
+replace github.com/kawaiirei0/laborer => ../..
+
+require (
+	github.com/kawaiirei0/laborer v0.0.0
+	go.uber.org/zap v1.27.0
+)
+
+require go.uber.org/multierr v1.10.0 // indirect
@@ -0,0 +1,89 @@
+// Package zap 提供将 laborer 池的日志写入 *zap.Logger 的适配器。
+//
+// 示例:
+//
+//	logger, _ := zap.NewProduction()
+//	pool, err := laborer.NewPool(100, laborerzap.WithLogger(logger))
+package zap
+
+import (
+	"fmt"
+
+	"github.com/kawaiirei0/laborer"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Logger 将池的日志写入 *zap.Logger。
+//
+// 同时实现 laborer.Logger 和 laborer.EventLogger：结构化的日志事件按事件级别写入，
+// 事件名称作为消息，池名称和事件字段作为 zap 字段；Printf 以 Info 级别写入格式化后的文本。
+type Logger struct {
+	logger *zap.Logger
+}
+
+// New 创建写入 logger 的适配器
+func New(logger *zap.Logger) *Logger {
+	return &Logger{logger: logger.WithOptions(zap.AddCallerSkip(1))}
+}
+
+// WithLogger 返回将池的日志写入 logger 的选项，同时设置 Logger 和 EventLogger
+func WithLogger(logger *zap.Logger) laborer.Option {
+	l := New(logger)
+	return func(opts *laborer.Options) {
+		opts.Logger = l
+		opts.EventLogger = l
+	}
+}
+
+// Printf 实现 laborer.Logger 接口，以 Info 级别写入
+func (l *Logger) Printf(format string, args ...interface{}) {
+	if ce := l.logger.Check(zapcore.InfoLevel, ""); ce != nil {
+		ce.Message = fmt.Sprintf(format, args...)
+		ce.Write()
+	}
+}
+
+// LogEvent 实现 laborer.EventLogger 接口
+// 级别未启用时不构造字段。
+func (l *Logger) LogEvent(e laborer.LogEvent) {
+	ce := l.logger.Check(level(e.Level), e.Event)
+	if ce == nil {
+		return
+	}
+
+	fields := make([]zap.Field, 0, len(e.Fields)+1)
+	if e.Pool != "" {
+		fields = append(fields, zap.String("pool", e.Pool))
+	}
+	for _, f := range e.Fields {
+		fields = append(fields, field(f))
+	}
+	ce.Write(fields...)
+}
+
+// level 将池的日志级别转换为 zap 的级别
+func level(l laborer.LogLevel) zapcore.Level {
+	switch {
+	case l >= laborer.LevelError:
+		return zapcore.ErrorLevel
+	case l >= laborer.LevelWarn:
+		return zapcore.WarnLevel
+	case l >= laborer.LevelInfo:
+		return zapcore.InfoLevel
+	default:
+		return zapcore.DebugLevel
+	}
+}
+
+// field 将事件字段转换为 zap 字段，调用栈以字符串写入而不是字节数组
+func field(f laborer.Field) zap.Field {
+	switch v := f.Value.(type) {
+	case []byte:
+		return zap.ByteString(f.Key, v)
+	case error:
+		return zap.NamedError(f.Key, v)
+	default:
+		return zap.Any(f.Key, v)
+	}
+}
@@ -0,0 +1,64 @@
+package zap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kawaiirei0/laborer"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// TestLogEvent 测试日志事件按级别和字段写入 zap
+func TestLogEvent(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	l := New(zap.New(core))
+
+	l.LogEvent(laborer.LogEvent{
+		Level:  laborer.LevelWarn,
+		Event:  "worker_stuck",
+		Pool:   "orders",
+		Fields: []laborer.Field{{Key: "worker_id", Value: 3}, {Key: "busy_for", Value: time.Second}, {Key: "stack", Value: "main.main()"}},
+	})
+	// 未启用的级别不写入
+	l.LogEvent(laborer.LogEvent{Level: laborer.LevelDebug, Event: "worker_expired"})
+	l.Printf("hello %s", "zap")
+
+	entries := logs.AllUntimed()
+	if len(entries) != 2 {
+		t.Fatalf("期望 2 条日志，实际 %d 条", len(entries))
+	}
+
+	e := entries[0]
+	if e.Level != zapcore.WarnLevel || e.Message != "worker_stuck" {
+		t.Errorf("事件的级别或消息不正确: %v %q", e.Level, e.Message)
+	}
+	fields := e.ContextMap()
+	if fields["pool"] != "orders" || fields["worker_id"] != int64(3) || fields["busy_for"] != time.Second {
+		t.Errorf("事件的字段不正确: %v", fields)
+	}
+
+	if entries[1].Level != zapcore.InfoLevel || entries[1].Message != "hello zap" {
+		t.Errorf("Printf 写入的日志不正确: %v %q", entries[1].Level, entries[1].Message)
+	}
+}
+
+// TestWithLogger 测试池通过选项使用 zap 记录生命周期事件
+func TestWithLogger(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	pool, err := laborer.NewPool(2, laborer.WithName("zap"), WithLogger(zap.New(core)))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	pool.Tune(4)
+	pool.Release()
+
+	tuned := logs.FilterMessage("pool_tuned").AllUntimed()
+	if len(tuned) != 1 {
+		t.Fatalf("期望 1 条 pool_tuned 日志，实际 %d 条", len(tuned))
+	}
+	if fields := tuned[0].ContextMap(); fields["pool"] != "zap" || fields["old_cap"] != int64(2) || fields["new_cap"] != int64(4) {
+		t.Errorf("pool_tuned 的字段不正确: %v", fields)
+	}
+}
@@ -1,5 +1,13 @@
 package laborer
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
 // Logger 定义日志记录接口。
 //
 // 实现此接口可以自定义池的日志输出行为。
@@ -14,7 +22,7 @@ package laborer
 //	pool, _ := laborer.NewPool(10,
 //	    laborer.WithLogger(log.Default()))
 //
-// 自定义实现示例:
+// 使用 zap 时可以直接使用 log/zap 子包中的适配器。自定义实现示例:
 //
 //	type MyLogger struct {
 //	    logger *zap.Logger
@@ -64,3 +72,300 @@ func (l *defaultLogger) Printf(format string, args ...interface{}) {
 func newDefaultLogger() Logger {
 	return &defaultLogger{}
 }
+
+// LogEvent 池写入的一条结构化日志
+//
+// 池不再写入预先格式化的字符串，而是把每条日志表示为带有字段的事件，
+// 日志管道可以按 Event 和字段解析池的活动，不必匹配文本。
+//
+// 池会写入的事件、级别及其字段:
+//
+//	worker_panic          error  worker_id, panic, [task]  任务 panic 且未设置 panic 处理函数
+//	worker_panic_stack    debug  worker_id, stack        紧随 worker_panic，记录 panic 的完整堆栈
+//	worker_expired        debug  worker_id, idle_for     空闲超时的 worker 被回收
+//	workers_purged        info   count                   PurgeNow 结束了空闲 worker
+//	worker_stuck          warn   worker_id, busy_for, stack, [task]  看门狗发现执行时间过长的任务
+//	pool_quarantined      warn   panics, until           连续 panic 达到阈值，池进入隔离期
+//	workers_leaked        warn   running, grace          释放后仍有 worker 在运行
+//	worker_leaked         warn   worker_id, goroutine, busy_for, stack
+//	pool_created          info   cap, nonblocking        池创建完成
+//	pool_released         info   running, timeout        Release 或 ReleaseTimeout 完成，Release 的 timeout 为 0
+//	pool_release_timeout  warn   running, timeout, error  ReleaseTimeout 超时，仍有任务未完成
+//	pool_rebooted         info   cap                     Reboot 重启了已关闭的池
+//	pool_tuned            info   old_cap, new_cap        Tune 调整了容量
+//	pool_error            error  error                   内部运行错误，设置了 WithErrorHandler 时不记录
+//	log_suppressed        warn   sampled_event, suppressed  采样窗口结束，汇总被 WithLogSampling 丢弃的事件
+//	task_submitted        debug  task_id                 开启追踪时任务被提交（见 WithTrace）
+//	task_dispatched       debug  task_id, worker_id, wait  被追踪的任务交给 worker 开始执行
+//	task_completed        debug  task_id, worker_id, duration, panicked
+//	task_rejected         debug  task_id, error          被追踪的任务没有被执行
+//	task_queued           debug  task_id                 被追踪的调用放入了任务队列
+//
+// [task] 表示只有任务设置了名称（SubmitNamed）时才有的字段。
+type LogEvent struct {
+	// Level 事件的级别
+	Level LogLevel
+
+	// Event 事件名称，例如 "worker_expired"
+	Event string
+
+	// Pool 池的名称，未设置 WithName 时为空
+	Pool string
+
+	// Fields 事件的字段，按固定顺序排列
+	Fields []Field
+}
+
+// LogLevel 日志事件的级别
+// 取值与 log/slog 的级别相同，可以直接转换为 slog.Level。
+type LogLevel int
+
+const (
+	// LevelDebug 频繁发生的常规事件，例如 worker 过期
+	LevelDebug LogLevel = -4
+
+	// LevelInfo 池的生命周期和配置变化
+	LevelInfo LogLevel = 0
+
+	// LevelWarn 需要关注但池仍然正常工作的情况
+	LevelWarn LogLevel = 4
+
+	// LevelError 任务 panic 等错误
+	LevelError LogLevel = 8
+)
+
+// String 返回级别的小写名称
+func (l LogLevel) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	}
+	return fmt.Sprintf("LogLevel(%d)", int(l))
+}
+
+// Field 日志事件的一个字段
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// String 以 logfmt 格式返回事件，例如:
+//
+//	level=debug event=worker_expired pool=orders worker_id=3 idle_for=1m0s
+//
+// 包含空格、引号、等号或换行的值会加上引号并转义，每个事件始终占一行。
+func (e LogEvent) String() string {
+	var b strings.Builder
+	b.WriteString("level=")
+	b.WriteString(e.Level.String())
+	b.WriteString(" event=")
+	b.WriteString(logfmtValue(e.Event))
+	if e.Pool != "" {
+		b.WriteString(" pool=")
+		b.WriteString(logfmtValue(e.Pool))
+	}
+	for _, f := range e.Fields {
+		b.WriteByte(' ')
+		b.WriteString(f.Key)
+		b.WriteByte('=')
+		b.WriteString(logfmtValue(f.Value))
+	}
+	return b.String()
+}
+
+// logfmtValue 将字段值格式化为 logfmt 的值
+func logfmtValue(v interface{}) string {
+	var s string
+	switch x := v.(type) {
+	case string:
+		s = x
+	case []byte:
+		s = string(x)
+	case time.Time:
+		s = x.Format(time.RFC3339Nano)
+	case error:
+		s = x.Error()
+	default:
+		s = fmt.Sprint(x)
+	}
+	if s == "" || strings.ContainsAny(s, " \t\r\n\"=") {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// EventLogger 接收结构化日志事件的日志记录器
+//
+// 通过 WithEventLogger 设置后，池把日志事件交给它而不是 Logger，
+// 实现可以把字段原样写入 zap、slog 等结构化日志库。
+//
+// 示例:
+//
+//	type slogEvents struct{ l *slog.Logger }
+//
+//	func (s slogEvents) LogEvent(e laborer.LogEvent) {
+//	    attrs := []any{"pool", e.Pool}
+//	    for _, f := range e.Fields {
+//	        attrs = append(attrs, f.Key, f.Value)
+//	    }
+//	    s.l.Log(context.Background(), slog.Level(e.Level), e.Event, attrs...)
+//	}
+type EventLogger interface {
+	// LogEvent 记录一个事件，可能被多个 goroutine 并发调用
+	LogEvent(e LogEvent)
+}
+
+// TextEventLogger 返回把事件格式化为一行 logfmt 文本后写入 logger 的 EventLogger
+// 未设置 EventLogger 时池使用它把事件写入 Logger。
+//
+// 示例:
+//
+//	events := laborer.TextEventLogger(log.Default())
+//	// 输出: level=debug event=worker_expired pool=orders worker_id=3 idle_for=1m0s
+func TextEventLogger(logger Logger) EventLogger {
+	return textEventLogger{logger}
+}
+
+// textEventLogger 把事件格式化后写入普通的 Logger
+type textEventLogger struct {
+	logger Logger
+}
+
+// LogEvent 实现 EventLogger 接口
+func (l textEventLogger) LogEvent(e LogEvent) {
+	l.logger.Printf("%s", e)
+}
+
+// logEvent 记录一个事件，Pool 字段取池的名称
+// 优先交给 EventLogger，未设置时格式化后写入 Logger；默认的空 Logger 不做格式化。
+// 启用了日志采样时，被采样丢弃的事件数量在采样窗口结束时由 log_suppressed 事件汇总记录。
+func (opts *Options) logEvent(level LogLevel, event string, fields ...Field) {
+	l := opts.eventLogger()
+	if l == nil {
+		return
+	}
+	if opts.sampler != nil && !opts.sampler.allow(event, time.Now()) {
+		return
+	}
+	l.LogEvent(LogEvent{Level: level, Event: event, Pool: opts.Name, Fields: fields})
+}
+
+// eventLogger 返回记录事件的 EventLogger，使用默认的空 Logger 时返回 nil
+func (opts *Options) eventLogger() EventLogger {
+	if opts.EventLogger != nil {
+		return opts.EventLogger
+	}
+	if _, ok := opts.Logger.(*defaultLogger); ok || opts.Logger == nil {
+		return nil
+	}
+	return textEventLogger{opts.Logger}
+}
+
+// logSuppressed 记录一个采样窗口内被丢弃的 event 事件数量，不经过采样
+func (opts *Options) logSuppressed(event string, suppressed int) {
+	if l := opts.eventLogger(); l != nil {
+		l.LogEvent(LogEvent{Level: LevelWarn, Event: "log_suppressed", Pool: opts.Name,
+			Fields: []Field{{"sampled_event", event}, {"suppressed", suppressed}}})
+	}
+}
+
+// logSampler 按事件名称限制日志的速率
+// 每个事件名称在每个 period 内最多记录 burst 条，其余的只计数，
+// 窗口结束时把计数交给 report，使风暴中丢弃了多少事件仍然可见。
+type logSampler struct {
+	burst  int
+	period time.Duration
+
+	// report 汇总一个窗口内被丢弃的事件数量
+	report func(event string, suppressed int)
+
+	mu      sync.Mutex
+	windows map[string]*sampleWindow
+}
+
+// sampleWindow 一个事件名称的当前采样窗口
+type sampleWindow struct {
+	// start 窗口开始的时间
+	start time.Time
+
+	// count 窗口内发生的事件数量
+	count int
+
+	// suppressed 窗口内被丢弃的事件数量
+	suppressed int
+
+	// timer 窗口内第一次丢弃事件时启动，窗口结束时汇总丢弃的数量，
+	// 风暴过后不再有同名事件时计数也不会丢失
+	timer *time.Timer
+}
+
+// newLogSampler 创建每 period 内每个事件最多记录 burst 条的采样器
+// 每个窗口内被丢弃的事件数量在窗口结束时交给 report。
+func newLogSampler(burst int, period time.Duration, report func(event string, suppressed int)) *logSampler {
+	return &logSampler{
+		burst:   burst,
+		period:  period,
+		report:  report,
+		windows: make(map[string]*sampleWindow),
+	}
+}
+
+// allow 返回 now 发生的 event 是否应该记录
+// now 已经超出当前窗口时先汇总上一个窗口丢弃的事件，再开始新的窗口。
+func (s *logSampler) allow(event string, now time.Time) bool {
+	s.mu.Lock()
+	w := s.windows[event]
+	if w == nil {
+		w = &sampleWindow{start: now}
+		s.windows[event] = w
+	}
+	var flushed int
+	if now.Sub(w.start) >= s.period {
+		flushed = w.take()
+		w.start = now
+		w.count = 0
+	}
+
+	w.count++
+	ok := w.count <= s.burst
+	if !ok {
+		w.suppressed++
+		if w.timer == nil {
+			w.timer = time.AfterFunc(w.start.Add(s.period).Sub(now), func() { s.flush(event) })
+		}
+	}
+	s.mu.Unlock()
+
+	if flushed > 0 {
+		s.report(event, flushed)
+	}
+	return ok
+}
+
+// flush 在窗口结束时汇总 event 被丢弃的事件数量
+func (s *logSampler) flush(event string) {
+	s.mu.Lock()
+	n := s.windows[event].take()
+	s.mu.Unlock()
+
+	if n > 0 {
+		s.report(event, n)
+	}
+}
+
+// take 取出并清零被丢弃的事件数量，调用方必须持有 logSampler.mu
+func (w *sampleWindow) take() int {
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+	n := w.suppressed
+	w.suppressed = 0
+	return n
+}
@@ -0,0 +1,280 @@
+package laborer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordLogger 记录所有日志行的 Logger
+type recordLogger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *recordLogger) Printf(format string, args ...interface{}) {
+	l.mu.Lock()
+	l.lines = append(l.lines, fmt.Sprintf(format, args...))
+	l.mu.Unlock()
+}
+
+// contains 返回是否有日志行包含 s
+func (l *recordLogger) contains(s string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, line := range l.lines {
+		if strings.Contains(line, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// TestPoolNameInLogsAndErrors 测试池名称出现在日志、panic 报告和错误中
+func TestPoolNameInLogsAndErrors(t *testing.T) {
+	logger := &recordLogger{}
+	pool, err := NewPool(2, WithName("orders"), WithLogger(logger))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+
+	// 未设置 panic 处理函数时写入日志
+	if err := pool.Submit(func() { panic("boom") }); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	waitFor(t, func() bool {
+		return logger.contains(`level=debug event=worker_panic_stack pool=orders worker_id=1 stack=`)
+	})
+	if !logger.contains(`level=error event=worker_panic pool=orders worker_id=1 panic=boom`) || logger.contains(`event=worker_panic pool=orders worker_id=1 panic=boom stack=`) {
+		t.Error("worker_panic 应该以 error 级别记录且不带有堆栈")
+	}
+
+	// 带返回值的任务 panic 时 PanicError 带有池名称
+	future, err := pool.SubmitWithResult(func() (interface{}, error) { panic("oops") })
+	if err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	_, err = future.GetWithTimeout(time.Second)
+	var pe *PanicError
+	if !errors.As(err, &pe) || pe.Pool != "orders" || !strings.Contains(err.Error(), `pool "orders"`) {
+		t.Errorf("PanicError 应该包含池名称，实际: %v", err)
+	}
+
+	// 关闭超时的错误带有池名称，仍然可以识别 ErrTimeout
+	block := make(chan struct{})
+	defer close(block)
+	if err := pool.Submit(func() { <-block }); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	err = pool.ReleaseTimeout(10 * time.Millisecond)
+	if !errors.Is(err, ErrTimeout) || !strings.HasPrefix(err.Error(), `pool "orders": `) {
+		t.Errorf("超时错误应该包含池名称，实际: %v", err)
+	}
+
+	// 未设置名称时不加前缀
+	unnamed, _ := NewPool(1, WithLogger(logger))
+	defer unnamed.Release()
+	unnamed.options.logEvent(LevelInfo, "hello")
+	if !logger.contains("event=hello") || logger.contains("event=hello pool=") {
+		t.Error("未设置名称时日志不应该带有 pool 字段")
+	}
+}
+
+// eventRecorder 记录所有日志事件的 EventLogger
+type eventRecorder struct {
+	mu     sync.Mutex
+	events []LogEvent
+}
+
+func (r *eventRecorder) LogEvent(e LogEvent) {
+	r.mu.Lock()
+	r.events = append(r.events, e)
+	r.mu.Unlock()
+}
+
+// find 返回第一个名称为 name 的事件
+func (r *eventRecorder) find(name string) (LogEvent, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, e := range r.events {
+		if e.Event == name {
+			return e, true
+		}
+	}
+	return LogEvent{}, false
+}
+
+// TestStructuredLogEvents 测试池以带字段的事件记录日志
+func TestStructuredLogEvents(t *testing.T) {
+	events := &eventRecorder{}
+	logger := &recordLogger{}
+	pool, err := NewPool(2,
+		WithName("events"),
+		WithLogger(logger),
+		WithEventLogger(events),
+		WithExpiryDuration(20*time.Millisecond),
+		WithCleanInterval(10*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	if err := pool.Submit(func() {}); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	waitFor(t, func() bool {
+		_, ok := events.find("worker_expired")
+		return ok
+	})
+
+	e, _ := events.find("worker_expired")
+	if e.Pool != "events" || len(e.Fields) != 2 || e.Fields[0].Key != "worker_id" || e.Fields[1].Key != "idle_for" {
+		t.Fatalf("worker_expired 事件的字段不正确: %+v", e)
+	}
+	if idle, ok := e.Fields[1].Value.(time.Duration); !ok || idle < 20*time.Millisecond {
+		t.Errorf("idle_for 应该是不小于过期时间的 time.Duration，实际 %#v", e.Fields[1].Value)
+	}
+	if len(logger.lines) != 0 {
+		t.Errorf("设置 EventLogger 后不应该写入 Logger，实际 %q", logger.lines)
+	}
+}
+
+// TestLogEventString 测试事件的 logfmt 格式
+func TestLogEventString(t *testing.T) {
+	e := LogEvent{
+		Level: LevelWarn,
+		Event: "worker_stuck",
+		Pool:  "web",
+		Fields: []Field{
+			{"worker_id", 7},
+			{"busy_for", 1500 * time.Millisecond},
+			{"stack", "goroutine 1 [running]:\nmain.main()"},
+			{"reason", ""},
+		},
+	}
+	want := `level=warn event=worker_stuck pool=web worker_id=7 busy_for=1.5s stack="goroutine 1 [running]:\nmain.main()" reason=""`
+	if got := e.String(); got != want {
+		t.Errorf("期望 %s，实际 %s", want, got)
+	}
+
+	logger := &recordLogger{}
+	TextEventLogger(logger).LogEvent(e)
+	if !logger.contains(want) {
+		t.Errorf("TextEventLogger 应该写入格式化后的事件，实际 %q", logger.lines)
+	}
+}
+
+// TestLogSampling 测试重复的日志事件被采样，窗口结束时汇总丢弃的数量
+func TestLogSampling(t *testing.T) {
+	events := &eventRecorder{}
+	pool, err := NewPool(1, WithEventLogger(events), WithLogSampling(2, time.Hour))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+	events.events = nil
+
+	for i := 0; i < 5; i++ {
+		pool.options.logEvent(LevelError, "worker_panic", Field{"panic", i})
+	}
+	pool.options.logEvent(LevelInfo, "workers_purged", Field{"count", 1})
+	if len(events.events) != 3 {
+		t.Fatalf("期望记录 3 条事件，实际 %d 条: %+v", len(events.events), events.events)
+	}
+
+	// 下一个窗口的第一条事件之前先汇总上一个窗口丢弃的数量
+	var reported []int
+	s := newLogSampler(2, time.Hour, func(event string, n int) { reported = append(reported, n) })
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		s.allow("worker_expired", now)
+	}
+	if ok := s.allow("worker_expired", now.Add(time.Hour)); !ok || len(reported) != 1 || reported[0] != 3 {
+		t.Errorf("期望记录并汇总 3 条被丢弃的事件，实际 %v %v", ok, reported)
+	}
+	if ok := s.allow("worker_expired", now.Add(time.Hour)); !ok || len(reported) != 1 {
+		t.Errorf("计数应该已经清零，实际 %v %v", ok, reported)
+	}
+}
+
+// TestLogSamplingSummary 测试风暴过后没有同名事件时，窗口结束仍然记录 log_suppressed
+func TestLogSamplingSummary(t *testing.T) {
+	events := &eventRecorder{}
+	pool, err := NewPool(1, WithEventLogger(events), WithLogSampling(1, 20*time.Millisecond))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	for i := 0; i < 4; i++ {
+		pool.options.logEvent(LevelError, "worker_panic", Field{"panic", i})
+	}
+	waitFor(t, func() bool {
+		_, ok := events.find("log_suppressed")
+		return ok
+	})
+
+	e, _ := events.find("log_suppressed")
+	if e.Level != LevelWarn || len(e.Fields) != 2 || e.Fields[0].Value != "worker_panic" || e.Fields[1].Value != 3 {
+		t.Errorf("期望汇总 3 条被丢弃的 worker_panic，实际 %+v", e)
+	}
+}
+
+// TestLifecycleEvents 测试创建、调整容量、关闭和重启时记录的事件
+func TestLifecycleEvents(t *testing.T) {
+	events := &eventRecorder{}
+	pool, err := NewPool(2, WithName("lifecycle"), WithEventLogger(events))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+
+	pool.Tune(5)
+	pool.Release()
+	pool.Reboot()
+
+	block := make(chan struct{})
+	defer close(block)
+	if err := pool.Submit(func() { <-block }); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	if err := pool.ReleaseTimeout(10 * time.Millisecond); !errors.Is(err, ErrTimeout) {
+		t.Fatalf("期望返回 ErrTimeout，实际返回: %v", err)
+	}
+
+	tests := []struct {
+		event string
+		level LogLevel
+		want  string
+	}{
+		{"pool_created", LevelInfo, "level=info event=pool_created pool=lifecycle cap=2 nonblocking=false"},
+		{"pool_tuned", LevelInfo, "level=info event=pool_tuned pool=lifecycle old_cap=2 new_cap=5"},
+		{"pool_released", LevelInfo, "level=info event=pool_released pool=lifecycle running=0 timeout=0s"},
+		{"pool_rebooted", LevelInfo, "level=info event=pool_rebooted pool=lifecycle cap=5"},
+		{"pool_release_timeout", LevelWarn, `level=warn event=pool_release_timeout pool=lifecycle running=1 timeout=10ms error="operation timeout: 1 tasks still running"`},
+	}
+	for _, tt := range tests {
+		e, ok := events.find(tt.event)
+		if !ok {
+			t.Errorf("缺少 %s 事件", tt.event)
+			continue
+		}
+		if e.Level != tt.level || e.String() != tt.want {
+			t.Errorf("期望 %s，实际 %s", tt.want, e)
+		}
+	}
+
+	// PoolWithFunc 记录相同的字段
+	fpEvents := &eventRecorder{}
+	fp, err := NewPoolWithFunc(1, func(interface{}) {}, WithName("lifecycle"), WithEventLogger(fpEvents))
+	if err != nil {
+		t.Fatalf("创建函数池失败: %v", err)
+	}
+	fp.Release()
+	if e, _ := fpEvents.find("pool_released"); e.String() != tests[2].want {
+		t.Errorf("期望 %s，实际 %s", tests[2].want, e)
+	}
+}
@@ -0,0 +1,184 @@
+// Package prometheus 提供将 laborer 池状态导出为 Prometheus 指标的 Collector。
+//
+// 示例:
+//
+//	collector := prometheus.NewCollector()
+//	collector.Add("image-resize", resizePool)
+//	collector.Add("webhook", webhookPool)
+//	promclient.MustRegister(collector)
+package prometheus
+
+import (
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/kawaiirei0/laborer"
+	prom "github.com/prometheus/client_golang/prometheus"
+)
+
+// namespace 所有指标的名称前缀
+const namespace = "laborer"
+
+// StatsSource 定义可以提供状态快照的池
+//
+// laborer.Pool 和 laborer.PoolWithFunc 都实现了此接口。
+type StatsSource interface {
+	Stats() laborer.Stats
+}
+
+// NamedStatsSource 定义可以提供按任务名称统计的池
+//
+// laborer.Pool 实现了此接口，添加的池实现了它时额外导出带 "task" 标签的指标。
+type NamedStatsSource interface {
+	NamedStats() map[string]laborer.NamedStats
+}
+
+// Collector 实现 prometheus.Collector 接口，导出已添加池的状态。
+//
+// 每个池通过 "pool" 标签区分，采集时调用池的 Stats() 获取快照，
+// 因此不需要额外的后台 goroutine。Collector 是线程安全的。
+type Collector struct {
+	mu    sync.RWMutex
+	pools map[string]StatsSource
+
+	running   *prom.Desc
+	free      *prom.Desc
+	capacity  *prom.Desc
+	waiting   *prom.Desc
+	submitted *prom.Desc
+	completed *prom.Desc
+	rejected  *prom.Desc
+	failed    *prom.Desc
+	panicked  *prom.Desc
+	queueWait *prom.Desc
+	execution *prom.Desc
+
+	namedRunning   *prom.Desc
+	namedCompleted *prom.Desc
+	namedPanicked  *prom.Desc
+}
+
+// NewCollector 创建一个新的 Collector
+func NewCollector() *Collector {
+	labels := []string{"pool"}
+	desc := func(name, help string) *prom.Desc {
+		return prom.NewDesc(prom.BuildFQName(namespace, "pool", name), help, labels, nil)
+	}
+	namedLabels := []string{"pool", "task"}
+	namedDesc := func(name, help string) *prom.Desc {
+		return prom.NewDesc(prom.BuildFQName(namespace, "pool", name), help, namedLabels, nil)
+	}
+
+	return &Collector{
+		pools:     make(map[string]StatsSource),
+		running:   desc("running_workers", "Number of running workers."),
+		free:      desc("free_workers", "Number of idle workers."),
+		capacity:  desc("capacity", "Pool capacity, -1 means unlimited."),
+		waiting:   desc("waiting_tasks", "Number of tasks waiting for a worker."),
+		submitted: desc("tasks_submitted_total", "Total number of accepted task submissions."),
+		completed: desc("tasks_completed_total", "Total number of tasks that completed without panicking."),
+		rejected:  desc("tasks_rejected_total", "Total number of submissions rejected because the pool was overloaded."),
+		failed:    desc("tasks_failed_total", "Total number of tasks that returned an error."),
+		panicked:  desc("tasks_panicked_total", "Total number of tasks that panicked."),
+		queueWait: desc("task_queue_wait_seconds", "Time tasks spent waiting for a worker."),
+		execution: desc("task_execution_seconds", "Time tasks spent executing."),
+
+		namedRunning:   namedDesc("named_tasks_running", "Number of tasks with this name currently executing."),
+		namedCompleted: namedDesc("named_tasks_completed_total", "Total number of tasks with this name that completed without panicking."),
+		namedPanicked:  namedDesc("named_tasks_panicked_total", "Total number of tasks with this name that panicked."),
+	}
+}
+
+// Add 添加一个需要导出指标的池，name 作为 "pool" 标签的值
+// 重复添加同名池会覆盖之前的池
+func (c *Collector) Add(name string, pool StatsSource) {
+	c.mu.Lock()
+	c.pools[name] = pool
+	c.mu.Unlock()
+}
+
+// Remove 移除指定名称的池
+func (c *Collector) Remove(name string) {
+	c.mu.Lock()
+	delete(c.pools, name)
+	c.mu.Unlock()
+}
+
+// Describe 实现 prometheus.Collector 接口
+func (c *Collector) Describe(ch chan<- *prom.Desc) {
+	ch <- c.running
+	ch <- c.free
+	ch <- c.capacity
+	ch <- c.waiting
+	ch <- c.submitted
+	ch <- c.completed
+	ch <- c.rejected
+	ch <- c.failed
+	ch <- c.panicked
+	ch <- c.queueWait
+	ch <- c.execution
+	ch <- c.namedRunning
+	ch <- c.namedCompleted
+	ch <- c.namedPanicked
+}
+
+// Collect 实现 prometheus.Collector 接口
+func (c *Collector) Collect(ch chan<- prom.Metric) {
+	c.mu.RLock()
+	names := make([]string, 0, len(c.pools))
+	for name := range c.pools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pools := make([]StatsSource, len(names))
+	for i, name := range names {
+		pools[i] = c.pools[name]
+	}
+	c.mu.RUnlock()
+
+	for i, name := range names {
+		s := pools[i].Stats()
+
+		ch <- prom.MustNewConstMetric(c.running, prom.GaugeValue, float64(s.Running), name)
+		ch <- prom.MustNewConstMetric(c.free, prom.GaugeValue, float64(s.Idle), name)
+		ch <- prom.MustNewConstMetric(c.capacity, prom.GaugeValue, float64(s.Cap), name)
+		ch <- prom.MustNewConstMetric(c.waiting, prom.GaugeValue, float64(s.Waiting), name)
+		ch <- prom.MustNewConstMetric(c.submitted, prom.CounterValue, float64(s.Submitted), name)
+		ch <- prom.MustNewConstMetric(c.completed, prom.CounterValue, float64(s.Completed), name)
+		ch <- prom.MustNewConstMetric(c.rejected, prom.CounterValue, float64(s.Rejected), name)
+		ch <- prom.MustNewConstMetric(c.failed, prom.CounterValue, float64(s.Failed), name)
+		ch <- prom.MustNewConstMetric(c.panicked, prom.CounterValue, float64(s.Panicked), name)
+
+		// 延迟直方图仅在池启用 WithLatencyHistogram 时导出
+		if len(s.QueueWait.Buckets) > 0 {
+			ch <- histogram(c.queueWait, s.QueueWait, name)
+			ch <- histogram(c.execution, s.Execution, name)
+		}
+
+		// 按任务名称的指标仅对提供 NamedStats 的池（laborer.Pool）导出
+		if src, ok := pools[i].(NamedStatsSource); ok {
+			for task, ns := range src.NamedStats() {
+				ch <- prom.MustNewConstMetric(c.namedRunning, prom.GaugeValue, float64(ns.Running), name, task)
+				ch <- prom.MustNewConstMetric(c.namedCompleted, prom.CounterValue, float64(ns.Completed), name, task)
+				ch <- prom.MustNewConstMetric(c.namedPanicked, prom.CounterValue, float64(ns.Panicked), name, task)
+			}
+		}
+	}
+}
+
+// histogram 将 laborer.LatencyStats 转换为 Prometheus 常量直方图
+// Prometheus 要求桶计数为累积值，+Inf 桶由 count 隐式表示
+func histogram(desc *prom.Desc, s laborer.LatencyStats, name string) prom.Metric {
+	buckets := make(map[float64]uint64, len(s.Buckets))
+	var cumulative uint64
+	for _, b := range s.Buckets {
+		cumulative += uint64(b.Count)
+		if b.UpperBound == math.MaxInt64 {
+			continue
+		}
+		buckets[b.UpperBound.Seconds()] = cumulative
+	}
+
+	return prom.MustNewConstHistogram(desc, cumulative, s.Sum.Seconds(), buckets, name)
+}
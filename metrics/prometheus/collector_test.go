@@ -0,0 +1,92 @@
+package prometheus
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kawaiirei0/laborer"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// TestCollector 测试导出池的状态指标
+func TestCollector(t *testing.T) {
+	pool, err := laborer.NewPool(4, laborer.WithLatencyHistogram())
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	f, err := pool.SubmitWithResult(func() (interface{}, error) { return nil, nil })
+	if err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	_, _ = f.Get()
+
+	c := NewCollector()
+	c.Add("test", pool)
+
+	if n := testutil.CollectAndCount(c); n != 11 {
+		t.Errorf("期望 11 个指标，实际 %d", n)
+	}
+
+	expected := `
+# HELP laborer_pool_capacity Pool capacity, -1 means unlimited.
+# TYPE laborer_pool_capacity gauge
+laborer_pool_capacity{pool="test"} 4
+`
+	if err := testutil.CollectAndCompare(c, strings.NewReader(expected), "laborer_pool_capacity"); err != nil {
+		t.Error(err)
+	}
+
+	c.Remove("test")
+	if n := testutil.CollectAndCount(c); n != 0 {
+		t.Errorf("移除后期望 0 个指标，实际 %d", n)
+	}
+}
+
+// TestCollectorUnbounded 测试无限容量的池导出实际空闲的 worker 数量，而不是 Free 的 -1
+func TestCollectorUnbounded(t *testing.T) {
+	pool, err := laborer.NewPool(-1)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	c := NewCollector()
+	c.Add("unbounded", pool)
+
+	expected := `
+# HELP laborer_pool_free_workers Number of idle workers.
+# TYPE laborer_pool_free_workers gauge
+laborer_pool_free_workers{pool="unbounded"} 0
+`
+	if err := testutil.CollectAndCompare(c, strings.NewReader(expected), "laborer_pool_free_workers"); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestCollectorNamedTasks 测试通过 SubmitNamed 提交的任务按 "task" 标签导出
+func TestCollectorNamedTasks(t *testing.T) {
+	pool, err := laborer.NewPool(2)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	if err := pool.SubmitNamed("resize", func() {}); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	pool.Wait()
+
+	c := NewCollector()
+	c.Add("images", pool)
+
+	expected := `
+# HELP laborer_pool_named_tasks_completed_total Total number of tasks with this name that completed without panicking.
+# TYPE laborer_pool_named_tasks_completed_total counter
+laborer_pool_named_tasks_completed_total{pool="images",task="resize"} 1
+`
+	if err := testutil.CollectAndCompare(c, strings.NewReader(expected), "laborer_pool_named_tasks_completed_total"); err != nil {
+		t.Error(err)
+	}
+}
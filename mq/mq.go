@@ -0,0 +1,223 @@
+// Package mq 提供以 laborer 池处理消息队列消息的通用适配器。
+//
+// 适配器运行若干个拉取循环，从 Source 拉取消息并提交到池中处理，
+// 只有处理成功的消息才会被确认，处理失败或未处理的消息由消息队列重新投递。
+// 为 Kafka、NSQ、SQS 等客户端实现 Source 即可由池承载消费。
+//
+// 示例:
+//
+//	source := mq.SourceFunc[*sqs.Message](func(ctx context.Context) (*sqs.Message, mq.Ack, error) {
+//	    msg, err := receive(ctx)
+//	    if err != nil {
+//	        return nil, nil, err
+//	    }
+//	    return msg, func() error { return deleteMessage(msg) }, nil
+//	})
+//
+//	w := mq.New(pool, source, func(ctx context.Context, msg *sqs.Message) error {
+//	    return handle(ctx, msg)
+//	}, mq.WithFetchers(4))
+//	err := w.Run(ctx)
+package mq
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/kawaiirei0/laborer"
+)
+
+const (
+	// DefaultFetchers 默认的拉取循环数量
+	DefaultFetchers = 1
+
+	// DefaultBackoff 拉取失败或池过载后重试前的默认等待时间
+	DefaultBackoff = time.Second
+)
+
+// Ack 确认一条消息已处理完成，返回确认失败的错误
+type Ack func() error
+
+// Source 定义可以拉取消息的消息队列
+//
+// Fetch 阻塞直到取到一条消息或 ctx 被取消，返回消息和确认它的函数。
+// 不需要确认的消息队列可以返回 nil 的 Ack。Fetch 会被多个拉取循环并发调用。
+type Source[M any] interface {
+	Fetch(ctx context.Context) (M, Ack, error)
+}
+
+// SourceFunc 将函数适配为 Source
+type SourceFunc[M any] func(ctx context.Context) (M, Ack, error)
+
+// Fetch 实现 Source 接口
+func (f SourceFunc[M]) Fetch(ctx context.Context) (M, Ack, error) {
+	return f(ctx)
+}
+
+// Handler 处理一条消息，返回 nil 时消息被确认
+type Handler[M any] func(ctx context.Context, msg M) error
+
+// Option 适配器的配置选项
+type Option func(*config)
+
+// config 适配器的配置
+type config struct {
+	// fetchers 并发的拉取循环数量
+	fetchers int
+
+	// backoff 拉取失败或池过载后重试前的等待时间
+	backoff time.Duration
+
+	// onError 接收拉取、处理和确认失败的错误
+	onError func(err error)
+}
+
+// WithFetchers 设置并发的拉取循环数量，小于 1 时按 1 处理
+// 单次拉取延迟较高的消息队列（例如长轮询）需要更多的拉取循环才能让池保持忙碌。
+func WithFetchers(n int) Option {
+	return func(c *config) {
+		c.fetchers = n
+	}
+}
+
+// WithBackoff 设置拉取失败或非阻塞池过载后重试前的等待时间
+func WithBackoff(d time.Duration) Option {
+	return func(c *config) {
+		c.backoff = d
+	}
+}
+
+// WithErrorHandler 设置接收错误的函数
+// 拉取、处理和确认失败的错误都会传给 fn，处理失败的消息不会被确认。
+// fn 会被并发调用。
+func WithErrorHandler(fn func(err error)) Option {
+	return func(c *config) {
+		c.onError = fn
+	}
+}
+
+// Worker 从 Source 拉取消息并在池中处理
+type Worker[M any] struct {
+	pool    *laborer.Pool
+	source  Source[M]
+	handler Handler[M]
+	cfg     config
+}
+
+// New 创建在 pool 中以 handler 处理 source 的消息的 Worker
+func New[M any](pool *laborer.Pool, source Source[M], handler Handler[M], opts ...Option) *Worker[M] {
+	cfg := config{fetchers: DefaultFetchers, backoff: DefaultBackoff}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.fetchers < 1 {
+		cfg.fetchers = 1
+	}
+	return &Worker[M]{pool: pool, source: source, handler: handler, cfg: cfg}
+}
+
+// Run 运行拉取循环直到 ctx 被取消或池被关闭，阻塞到所有已提交的消息处理完成
+//
+// ctx 被取消后不再拉取新的消息，已提交的消息仍会处理完成并确认，
+// 传给 handler 的 ctx 不会因此被取消。
+// ctx 被取消时返回 ctx.Err()；提交失败（例如 laborer.ErrPoolClosed）时返回该错误，
+// 此时已拉取的消息不会被确认。
+func (w *Worker[M]) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	handlerCtx := context.WithoutCancel(ctx)
+
+	var fetchers, inflight sync.WaitGroup
+	for i := 0; i < w.cfg.fetchers; i++ {
+		fetchers.Add(1)
+		go func() {
+			defer fetchers.Done()
+			if err := w.fetch(ctx, handlerCtx, &inflight); err != nil {
+				cancel(err)
+			}
+		}()
+	}
+
+	fetchers.Wait()
+	inflight.Wait()
+	return context.Cause(ctx)
+}
+
+// fetch 一个拉取循环，返回导致停止的提交错误，ctx 被取消时返回 nil
+func (w *Worker[M]) fetch(ctx, handlerCtx context.Context, inflight *sync.WaitGroup) error {
+	for ctx.Err() == nil {
+		msg, ack, err := w.source.Fetch(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			w.report(err)
+			w.sleep(ctx)
+			continue
+		}
+
+		if err := w.submit(ctx, handlerCtx, inflight, msg, ack); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// submit 将消息提交到池中，非阻塞池过载时等待后重试
+func (w *Worker[M]) submit(ctx, handlerCtx context.Context, inflight *sync.WaitGroup, msg M, ack Ack) error {
+	for {
+		inflight.Add(1)
+		err := w.pool.SubmitContext(ctx, func() {
+			defer inflight.Done()
+			w.handle(handlerCtx, msg, ack)
+		})
+		if err == nil {
+			return nil
+		}
+		inflight.Done()
+
+		if !errors.Is(err, laborer.ErrPoolOverload) {
+			return err
+		}
+		w.sleep(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
+
+// handle 处理一条消息，成功时确认
+func (w *Worker[M]) handle(ctx context.Context, msg M, ack Ack) {
+	if err := w.handler(ctx, msg); err != nil {
+		w.report(err)
+		return
+	}
+	if ack == nil {
+		return
+	}
+	if err := ack(); err != nil {
+		w.report(err)
+	}
+}
+
+// report 将错误交给错误处理函数
+func (w *Worker[M]) report(err error) {
+	if w.cfg.onError != nil {
+		w.cfg.onError(err)
+	}
+}
+
+// sleep 等待退避时间或 ctx 被取消
+func (w *Worker[M]) sleep(ctx context.Context) {
+	timer := time.NewTimer(w.cfg.backoff)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
@@ -0,0 +1,141 @@
+package mq
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kawaiirei0/laborer"
+)
+
+// memSource 内存中的消息队列，记录被确认的消息
+type memSource struct {
+	msgs chan int
+
+	mu    sync.Mutex
+	acked map[int]bool
+}
+
+func newMemSource(n int) *memSource {
+	s := &memSource{msgs: make(chan int, n), acked: make(map[int]bool)}
+	for i := 1; i <= n; i++ {
+		s.msgs <- i
+	}
+	return s
+}
+
+func (s *memSource) Fetch(ctx context.Context) (int, Ack, error) {
+	select {
+	case <-ctx.Done():
+		return 0, nil, ctx.Err()
+	case msg := <-s.msgs:
+		return msg, func() error {
+			s.mu.Lock()
+			s.acked[msg] = true
+			s.mu.Unlock()
+			return nil
+		}, nil
+	}
+}
+
+func (s *memSource) ackedCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.acked)
+}
+
+// TestWorker 测试消息在池中处理，只有处理成功的消息被确认
+func TestWorker(t *testing.T) {
+	pool, err := laborer.NewPool(2, laborer.WithNonblocking(true))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	source := newMemSource(20)
+	var mu sync.Mutex
+	var errs []error
+	w := New[int](pool, source, func(ctx context.Context, msg int) error {
+		time.Sleep(time.Millisecond)
+		if msg%5 == 0 {
+			return errors.New("bad message")
+		}
+		return nil
+	}, WithFetchers(3), WithBackoff(time.Millisecond), WithErrorHandler(func(err error) {
+		mu.Lock()
+		errs = append(errs, err)
+		mu.Unlock()
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- w.Run(ctx) }()
+
+	deadline := time.Now().Add(time.Second)
+	for source.ackedCount() < 16 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("期望返回 context.Canceled，实际 %v", err)
+	}
+
+	if got := source.ackedCount(); got != 16 {
+		t.Errorf("期望确认 16 条消息，实际 %d", got)
+	}
+	for _, msg := range []int{5, 10, 15, 20} {
+		if source.acked[msg] {
+			t.Errorf("处理失败的消息 %d 不应该被确认", msg)
+		}
+	}
+	if len(errs) != 4 {
+		t.Errorf("期望上报 4 个处理错误，实际 %d", len(errs))
+	}
+}
+
+// TestWorkerPoolClosed 测试池关闭后 Run 返回 ErrPoolClosed
+func TestWorkerPoolClosed(t *testing.T) {
+	pool, err := laborer.NewPool(1)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	pool.Release()
+
+	source := newMemSource(1)
+	w := New[int](pool, source, func(context.Context, int) error { return nil })
+	if err := w.Run(context.Background()); !errors.Is(err, laborer.ErrPoolClosed) {
+		t.Errorf("期望返回 ErrPoolClosed，实际 %v", err)
+	}
+	if source.ackedCount() != 0 {
+		t.Error("未处理的消息不应该被确认")
+	}
+}
+
+// TestWorkerGracefulStop 测试 ctx 取消后已提交的消息仍处理完成并确认
+func TestWorkerGracefulStop(t *testing.T) {
+	pool, err := laborer.NewPool(1)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	source := newMemSource(1)
+	started := make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	w := New[int](pool, source, func(hctx context.Context, msg int) error {
+		close(started)
+		time.Sleep(20 * time.Millisecond)
+		return hctx.Err()
+	})
+
+	done := make(chan error)
+	go func() { done <- w.Run(ctx) }()
+	<-started
+	cancel()
+	<-done
+	if source.ackedCount() != 1 {
+		t.Error("ctx 取消前已提交的消息应该处理完成并确认")
+	}
+}
@@ -0,0 +1,113 @@
+package laborer
+
+// MultiPool 由多个独立的 Pool 组成的分片池
+//
+// 每个子池拥有自己的锁和条件变量，Submit 根据负载均衡策略分配到某个子池，
+// 用于缓解高 QPS 服务中单个池的锁竞争。状态查询返回所有子池的汇总值，
+// Release 关闭所有子池。启用 WithWorkStealing 后，选中的子池已满时
+// 会先使用其他子池的空闲容量；阻塞模式下所有子池都已满时，提交由最先
+// 空出 worker 的子池接手。
+//
+// 示例:
+//
+//	mp, _ := laborer.NewMultiPool(8, 100, laborer.LeastBusy)
+//	defer mp.Release()
+//
+//	mp.Submit(func() {
+//	    handle(req)
+//	})
+type MultiPool struct {
+	poolShards
+	lb balancer
+
+	// thieves 启用工作窃取时阻塞等待任意子池空出 worker 的提交者
+	thieves *thieves
+}
+
+// NewMultiPool 创建一个新的分片池
+// size: 子池的数量，必须为正数
+// sizePerPool: 每个子池的容量，-1 表示无限容量
+// lbs: 负载均衡策略
+// options: 配置选项，应用到每个子池
+func NewMultiPool(size, sizePerPool int, lbs LoadBalancingStrategy, options ...Option) (*MultiPool, error) {
+	if size <= 0 {
+		return nil, ErrInvalidPoolSize
+	}
+	if !lbs.valid() {
+		return nil, ErrInvalidLoadBalancingStrategy
+	}
+
+	shards, err := newPoolShards(size, sizePerPool, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	mp := &MultiPool{poolShards: shards}
+	mp.lb.strategy = lbs
+	if shards[0].options.WorkStealing {
+		mp.thieves = &thieves{}
+		for _, p := range shards {
+			p.thieves = mp.thieves
+		}
+	}
+
+	return mp, nil
+}
+
+// Submit 根据负载均衡策略选择一个子池并提交任务
+func (mp *MultiPool) Submit(task func()) error {
+	return mp.dispatch(mp.pickIndex(&mp.lb), taskItem{run: task})
+}
+
+// SubmitWithKey 提交一个带键的任务
+// KeyHash 策略下同一个键的任务总是提交到同一个子池，其他策略忽略键。
+func (mp *MultiPool) SubmitWithKey(key string, task func()) error {
+	return mp.dispatch(mp.pickIndexByKey(&mp.lb, key), taskItem{run: task})
+}
+
+// SubmitWithResult 根据负载均衡策略选择一个子池并提交带返回值的任务
+func (mp *MultiPool) SubmitWithResult(task func() (interface{}, error)) (Future, error) {
+	f := newFuture()
+	if err := mp.dispatch(mp.pickIndex(&mp.lb), taskItem{call: task, future: f}); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Tune 将每个子池的容量调整为 sizePerPool
+// 规则与 Pool.Tune 相同，分片池的总容量变为子池数量乘以 sizePerPool。
+func (mp *MultiPool) Tune(sizePerPool int) {
+	mp.tune(sizePerPool)
+}
+
+// dispatch 将任务投递到第 i 个子池
+// 启用工作窃取时，该子池已满会先尝试其他子池的空闲容量；
+// 阻塞模式下所有子池都已满时等待任意子池空出 worker
+func (mp *MultiPool) dispatch(i int, t taskItem) error {
+	if mp.IsClosed() {
+		return ErrPoolClosed
+	}
+	if t.isNil() {
+		return ErrNilTask
+	}
+
+	p := mp.poolShards[i]
+	t = p.newTask(t)
+
+	if p.options.WorkStealing {
+		try := func() (bool, error) {
+			return mp.steal(i, func(p *Pool) (bool, error) {
+				return p.tryDispatch(t)
+			})
+		}
+		// 共享预算用尽时由选中的子池等待预算，预算的归还不会唤醒窃取者
+		if !p.options.Nonblocking && p.options.Budget == nil {
+			return mp.thieves.wait(&p.thieving, try)
+		}
+		if ok, err := try(); err != nil || ok {
+			return err
+		}
+	}
+
+	return p.dispatch(t)
+}
@@ -0,0 +1,99 @@
+package laborer
+
+// MultiPoolWithFunc 由多个独立的 PoolWithFunc 组成的分片函数池
+//
+// 每个子池拥有自己的锁和条件变量，Invoke 根据负载均衡策略分配到某个子池，
+// 用于降低 Invoke 频率极高时单个池的锁竞争。
+//
+// 示例:
+//
+//	mp, _ := laborer.NewMultiPoolWithFunc(8, 100, func(args interface{}) {
+//	    handle(args)
+//	}, laborer.RoundRobin)
+//	defer mp.Release()
+//
+//	mp.Invoke(msg)
+type MultiPoolWithFunc struct {
+	funcShards
+	lb balancer
+
+	// thieves 启用工作窃取时阻塞等待任意子池空出 worker 的调用方
+	thieves *thieves
+}
+
+// NewMultiPoolWithFunc 创建一个新的分片函数池
+// size: 子池的数量，必须为正数
+// sizePerPool: 每个子池的容量，-1 表示无限容量
+// pf: 所有 worker 执行的固定函数
+// lbs: 负载均衡策略
+// options: 配置选项，应用到每个子池
+func NewMultiPoolWithFunc(size, sizePerPool int, pf func(interface{}), lbs LoadBalancingStrategy, options ...Option) (*MultiPoolWithFunc, error) {
+	if size <= 0 {
+		return nil, ErrInvalidPoolSize
+	}
+	if !lbs.valid() {
+		return nil, ErrInvalidLoadBalancingStrategy
+	}
+
+	shards, err := newFuncShards(size, sizePerPool, pf, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	mp := &MultiPoolWithFunc{funcShards: shards}
+	mp.lb.strategy = lbs
+	if shards[0].options.WorkStealing {
+		mp.thieves = &thieves{}
+		for _, p := range shards {
+			p.thieves = mp.thieves
+		}
+	}
+
+	return mp, nil
+}
+
+// Invoke 根据负载均衡策略选择一个子池，提交参数到固定函数执行
+func (mp *MultiPoolWithFunc) Invoke(args interface{}) error {
+	return mp.invoke(mp.pickIndex(&mp.lb), args)
+}
+
+// InvokeWithKey 提交一个带键的参数
+// KeyHash 策略下同一个键的参数总是提交到同一个子池，其他策略忽略键。
+func (mp *MultiPoolWithFunc) InvokeWithKey(key string, args interface{}) error {
+	return mp.invoke(mp.pickIndexByKey(&mp.lb, key), args)
+}
+
+// Tune 将每个子池的容量调整为 sizePerPool
+// 规则与 PoolWithFunc.Tune 相同，分片池的总容量变为子池数量乘以 sizePerPool。
+func (mp *MultiPoolWithFunc) Tune(sizePerPool int) {
+	mp.tune(sizePerPool)
+}
+
+// invoke 将参数提交到第 i 个子池
+func (mp *MultiPoolWithFunc) invoke(i int, args interface{}) error {
+	if mp.IsClosed() {
+		return ErrPoolClosed
+	}
+
+	p := mp.funcShards[i]
+
+	// 启用工作窃取时，选中的子池已满会先尝试其他子池的空闲容量；
+	// 阻塞模式下所有子池都已满时等待任意子池空出 worker。
+	// 设置了任务队列时参数放入共享的队列，由空闲的子池取出，不需要等待；
+	// 设置了共享预算时由选中的子池等待预算，预算的归还不会唤醒窃取者。
+	if p.options.WorkStealing {
+		try := func() (bool, error) {
+			return mp.steal(i, func(p *PoolWithFunc) (bool, error) {
+				return p.tryInvoke(args)
+			})
+		}
+		if !p.options.Nonblocking && p.options.TaskQueue == nil && p.options.Budget == nil {
+			return mp.thieves.wait(&p.thieving, try)
+		}
+		if ok, err := try(); err != nil || ok {
+			return err
+		}
+	}
+
+	return p.Invoke(args)
+}
@@ -0,0 +1,71 @@
+package laborer
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// TestMultiPoolWithFunc 测试分片函数池
+func TestMultiPoolWithFunc(t *testing.T) {
+	var sum int64
+	var wg sync.WaitGroup
+	mp, err := NewMultiPoolWithFunc(4, 2, func(i interface{}) {
+		atomic.AddInt64(&sum, int64(i.(int)))
+		wg.Done()
+	}, RoundRobin, WithLatencyHistogram())
+	if err != nil {
+		t.Fatalf("创建分片函数池失败: %v", err)
+	}
+
+	if mp.Cap() != 8 {
+		t.Errorf("Cap() 期望 8，实际 %d", mp.Cap())
+	}
+
+	wg.Add(100)
+	for i := 1; i <= 100; i++ {
+		if err := mp.Invoke(i); err != nil {
+			t.Fatalf("提交任务失败: %v", err)
+		}
+	}
+	wg.Wait()
+
+	if sum != 5050 {
+		t.Errorf("期望总和为 5050，实际为 %d", sum)
+	}
+
+	// 轮询策略下每个子池应该收到相同数量的任务
+	for i, p := range mp.funcShards {
+		if n := p.Stats().Submitted; n != 25 {
+			t.Errorf("子池 %d 期望收到 25 个任务，实际 %d", i, n)
+		}
+	}
+
+	s := mp.Stats()
+	if s.Submitted != 100 || s.Cap != 8 {
+		t.Errorf("汇总状态不正确: %+v", s)
+	}
+
+	mp.Release()
+	if !mp.IsClosed() {
+		t.Error("Release 后应该是关闭状态")
+	}
+	if err := mp.Invoke(1); err != ErrPoolClosed {
+		t.Errorf("期望返回 ErrPoolClosed，实际返回: %v", err)
+	}
+}
+
+// TestNewMultiPoolWithFuncInvalid 测试无效参数
+func TestNewMultiPoolWithFuncInvalid(t *testing.T) {
+	pf := func(interface{}) {}
+
+	if _, err := NewMultiPoolWithFunc(0, 1, pf, RoundRobin); err != ErrInvalidPoolSize {
+		t.Errorf("期望返回 ErrInvalidPoolSize，实际返回: %v", err)
+	}
+	if _, err := NewMultiPoolWithFunc(2, 1, pf, LoadBalancingStrategy(99)); err != ErrInvalidLoadBalancingStrategy {
+		t.Errorf("期望返回 ErrInvalidLoadBalancingStrategy，实际返回: %v", err)
+	}
+	if _, err := NewMultiPoolWithFunc(2, -5, pf, LeastBusy); err != ErrInvalidPoolSize {
+		t.Errorf("期望返回 ErrInvalidPoolSize，实际返回: %v", err)
+	}
+}
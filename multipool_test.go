@@ -0,0 +1,277 @@
+package laborer
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestMultiPool 测试分片池的提交和汇总状态
+func TestMultiPool(t *testing.T) {
+	for _, lbs := range []LoadBalancingStrategy{RoundRobin, LeastBusy, Random} {
+		mp, err := NewMultiPool(4, 2, lbs)
+		if err != nil {
+			t.Fatalf("创建分片池失败: %v", err)
+		}
+
+		var counter int32
+		var wg sync.WaitGroup
+		for i := 0; i < 40; i++ {
+			wg.Add(1)
+			if err := mp.Submit(func() {
+				time.Sleep(time.Millisecond)
+				atomic.AddInt32(&counter, 1)
+				wg.Done()
+			}); err != nil {
+				t.Fatalf("提交任务失败: %v", err)
+			}
+		}
+		wg.Wait()
+
+		if counter != 40 {
+			t.Errorf("策略 %d: 期望执行 40 个任务，实际 %d 个", lbs, counter)
+		}
+		if s := mp.Stats(); s.Submitted != 40 || s.Cap != 8 {
+			t.Errorf("策略 %d: 汇总状态不正确: %+v", lbs, s)
+		}
+
+		future, err := mp.SubmitWithResult(func() (interface{}, error) { return 42, nil })
+		if err != nil {
+			t.Fatalf("提交任务失败: %v", err)
+		}
+		if result, _ := future.Get(); result != 42 {
+			t.Errorf("期望结果为 42，实际为 %v", result)
+		}
+
+		mp.Release()
+		if err := mp.Submit(func() {}); err != ErrPoolClosed {
+			t.Errorf("期望返回 ErrPoolClosed，实际返回: %v", err)
+		}
+	}
+}
+
+// TestMultiPoolLeastBusy 测试 LeastBusy 策略避开忙碌的子池
+func TestMultiPoolLeastBusy(t *testing.T) {
+	mp, err := NewMultiPool(2, 4, LeastBusy)
+	if err != nil {
+		t.Fatalf("创建分片池失败: %v", err)
+	}
+	defer mp.Release()
+
+	block := make(chan struct{})
+	defer close(block)
+	_ = mp.poolShards[0].Submit(func() { <-block })
+	_ = mp.poolShards[0].Submit(func() { <-block })
+
+	for i := 0; i < 2; i++ {
+		_ = mp.Submit(func() { <-block })
+	}
+	if n := mp.poolShards[1].Running(); n != 2 {
+		t.Errorf("新任务应该分配到空闲的子池，子池 1 运行数为 %d", n)
+	}
+}
+
+// TestMultiPoolLeastTasks 测试 LeastTasks 策略同时考虑等待中的任务
+func TestMultiPoolLeastTasks(t *testing.T) {
+	mp, err := NewMultiPool(2, 1, LeastTasks)
+	if err != nil {
+		t.Fatalf("创建分片池失败: %v", err)
+	}
+	defer mp.Release()
+
+	block := make(chan struct{})
+	defer close(block)
+
+	// 两个子池都有 1 个运行中的任务，子池 0 另有 1 个等待的提交
+	_ = mp.poolShards[0].Submit(func() { <-block })
+	_ = mp.poolShards[1].Submit(func() { <-block })
+	go func() { _ = mp.poolShards[0].Submit(func() {}) }()
+	waitFor(t, func() bool { return mp.poolShards[0].Waiting() == 1 })
+
+	go func() { _ = mp.Submit(func() {}) }()
+	waitFor(t, func() bool { return mp.poolShards[1].Waiting() == 1 })
+
+	if n := mp.poolShards[0].Waiting(); n != 1 {
+		t.Errorf("新任务不应该分配到等待任务更多的子池 0，其等待数为 %d", n)
+	}
+}
+
+// waitFor 轮询等待条件成立，超时则测试失败
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("等待条件成立超时")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// TestMultiPoolWorkStealing 测试选中的子池已满时使用其他子池的空闲容量
+func TestMultiPoolWorkStealing(t *testing.T) {
+	mp, err := NewMultiPool(2, 1, RoundRobin, WithNonblocking(true), WithWorkStealing(true))
+	if err != nil {
+		t.Fatalf("创建分片池失败: %v", err)
+	}
+	defer mp.Release()
+
+	// 第一个任务占满子池 0，第二个任务在子池 1 上执行完后留下一个空闲 worker
+	block := make(chan struct{})
+	if err := mp.Submit(func() { <-block }); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	if err := mp.Submit(func() {}); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for mp.Free() != 1 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	// 轮询再次选中已满的子池 0，任务应被子池 1 窃取而不是被拒绝
+	done := make(chan struct{})
+	if err := mp.Submit(func() { close(done) }); err != nil {
+		t.Fatalf("期望任务被其他子池执行，实际返回: %v", err)
+	}
+	<-done
+
+	// 所有子池都已满时按非阻塞配置拒绝
+	if err := mp.Submit(func() { <-block }); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	if err := mp.Submit(func() {}); err != ErrPoolOverload {
+		t.Errorf("期望返回 ErrPoolOverload，实际返回: %v", err)
+	}
+	close(block)
+}
+
+// TestMultiPoolWorkStealingBlocking 测试阻塞模式下所有子池都已满时，由最先空出 worker 的子池接手提交
+func TestMultiPoolWorkStealingBlocking(t *testing.T) {
+	mp, err := NewMultiPool(2, 1, RoundRobin, WithWorkStealing(true))
+	if err != nil {
+		t.Fatalf("创建分片池失败: %v", err)
+	}
+	defer mp.Release()
+
+	// 两个子池各被一个任务占满
+	first, second := make(chan struct{}), make(chan struct{})
+	defer close(first)
+	for _, block := range []chan struct{}{first, second} {
+		block := block
+		if err := mp.Submit(func() { <-block }); err != nil {
+			t.Fatalf("提交任务失败: %v", err)
+		}
+	}
+
+	// 轮询选中子池 0，提交阻塞等待
+	done := make(chan struct{})
+	go func() {
+		_ = mp.Submit(func() { close(done) })
+	}()
+	waitFor(t, func() bool { return mp.BlockedSubmitters() == 1 })
+
+	// 子池 1 先空出 worker，任务不必等待子池 0
+	close(second)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("期望空闲的子池接手阻塞的提交")
+	}
+	if n := mp.BlockedSubmitters(); n != 0 {
+		t.Errorf("期望没有阻塞的提交者，实际 %d", n)
+	}
+}
+
+// TestMultiPoolKeyHash 测试 KeyHash 策略将同一个键路由到同一个子池
+func TestMultiPoolKeyHash(t *testing.T) {
+	mp, err := NewMultiPool(4, 1, KeyHash)
+	if err != nil {
+		t.Fatalf("创建分片池失败: %v", err)
+	}
+	defer mp.Release()
+
+	// 每个子池容量为 1，同一个键的任务只能在同一个子池中依次执行
+	var wg sync.WaitGroup
+	var cur, peak int32
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		if err := mp.SubmitWithKey("user-1", func() {
+			defer wg.Done()
+			if n := atomic.AddInt32(&cur, 1); n > atomic.LoadInt32(&peak) {
+				atomic.StoreInt32(&peak, n)
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&cur, -1)
+		}); err != nil {
+			t.Fatalf("提交任务失败: %v", err)
+		}
+	}
+	wg.Wait()
+
+	if peak != 1 {
+		t.Errorf("期望同一个键的任务串行执行，实际并发峰值 %d", peak)
+	}
+	if s := mp.poolShards[hashIndex("user-1", 4)].Stats(); s.Submitted != 10 {
+		t.Errorf("期望 10 个任务都提交到同一个子池，实际 %d 个", s.Submitted)
+	}
+}
+
+// TestMultiPoolManagement 测试分片池的整体调整容量、关闭和重启
+func TestMultiPoolManagement(t *testing.T) {
+	mp, err := NewMultiPool(4, 2, RoundRobin)
+	if err != nil {
+		t.Fatalf("创建分片池失败: %v", err)
+	}
+
+	mp.Tune(5)
+	if mp.Cap() != 20 {
+		t.Errorf("期望总容量为 20，实际为 %d", mp.Cap())
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		mp.Submit(wg.Done)
+	}
+	wg.Wait()
+	if s := mp.Stats(); s.Submitted != 8 || s.Cap != 20 {
+		t.Errorf("汇总状态不正确: %+v", s)
+	}
+
+	if err := mp.ReleaseTimeout(time.Second); err != nil {
+		t.Fatalf("关闭分片池失败: %v", err)
+	}
+	if !mp.IsClosed() {
+		t.Error("期望分片池已关闭")
+	}
+
+	mp.Reboot()
+	defer mp.Release()
+	if mp.IsClosed() {
+		t.Error("期望分片池已重启")
+	}
+	done := make(chan struct{})
+	if err := mp.Submit(func() { close(done) }); err != nil {
+		t.Fatalf("重启后提交任务失败: %v", err)
+	}
+	<-done
+}
+
+// TestBalancerRoundRobinWrap 测试轮询序号越过 32 位上限后仍按顺序选择子池
+func TestBalancerRoundRobinWrap(t *testing.T) {
+	var b balancer
+	b.next.Store(math.MaxUint32 - 1)
+
+	load := func(i int) int { return 0 }
+	prev := b.pick(3, load)
+	for i := 0; i < 6; i++ {
+		idx := b.pick(3, load)
+		if idx != (prev+1)%3 {
+			t.Fatalf("期望选择子池 %d，实际选择 %d", (prev+1)%3, idx)
+		}
+		prev = idx
+	}
+}
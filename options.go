@@ -1,6 +1,10 @@
 package laborer
 
-import "time"
+import (
+	"fmt"
+	"runtime"
+	"time"
+)
 
 // Options 定义了 goroutine 池的配置选项。
 //
@@ -19,6 +23,7 @@ import "time"
 // 默认配置常量
 const (
 	// DefaultCleanIntervalTime 默认清理间隔时间
+	// 当 ExpiryDuration 小于此值时，清理间隔取 ExpiryDuration
 	DefaultCleanIntervalTime = 1 * time.Second
 
 	// DefaultExpiryDuration 默认 Worker 空闲超时时间
@@ -34,13 +39,36 @@ type Options struct {
 	// 默认值: 10 秒
 	ExpiryDuration time.Duration
 
+	// CleanInterval 定义清理 goroutine 扫描过期 worker 的间隔。
+	// 为 0 时取 DefaultCleanIntervalTime 与 ExpiryDuration 中的较小值。
+	// 默认值: 0
+	CleanInterval time.Duration
+
+	// Clock 定义过期回收使用的时钟。
+	// 测试中替换为可控的时钟后可以推进时间触发过期回收。
+	// 默认值: nil（使用系统时间）
+	Clock Clock
+
+	// Name 定义池的名称。
+	// 用于在监控、日志和调试页面中区分同一进程内的多个池。
+	// 默认值: ""
+	Name string
+
+	// SizeMultiplier 定义容量为 0 时按 CPU 数量换算容量的倍数。
+	// 容量为 0 的池的实际容量为 runtime.GOMAXPROCS(0) * SizeMultiplier，
+	// IO 密集的任务可以设置更大的倍数。
+	// 默认值: 0（按 1 处理）
+	SizeMultiplier int
+
 	// PreAlloc 指定是否预分配 worker 切片。
 	// 启用后会在池创建时预先分配内存，适合容量固定的场景。
 	// 默认值: false
 	PreAlloc bool
 
-	// MaxBlockingTasks 定义最大阻塞任务数量（当前未使用）。
-	// 保留用于未来扩展。
+	// MaxBlockingTasks 定义阻塞模式下同时等待 worker 的提交数上限。
+	// 等待的提交数已达上限时，新的提交不再等待，按池已满处理并返回 ErrPoolOverload。
+	// 0 表示不限制；非阻塞模式下不生效。
+	// 默认值: 0（不限制）
 	MaxBlockingTasks int
 
 	// Nonblocking 指定池是否使用非阻塞模式。
@@ -50,14 +78,178 @@ type Options struct {
 	Nonblocking bool
 
 	// PanicHandler 定义任务执行时发生 panic 的处理函数。
-	// 如果未设置，panic 会被记录到日志中。
+	// 如果未设置，panic 以 Error 级别的 worker_panic 事件记录到日志中，堆栈以 Debug 级别单独记录。
 	// 默认值: nil
 	PanicHandler func(interface{})
 
+	// PanicHandlerV2 定义带有栈和任务元数据的 panic 处理函数。
+	// 设置后优先于 PanicHandler 调用。
+	// 默认值: nil
+	PanicHandlerV2 func(recovered interface{}, stack []byte, info TaskInfo)
+
+	// DisablePurge 指定是否禁用过期 worker 的清理。
+	// 启用后不会创建后台清理 goroutine，worker 创建后将常驻直到池关闭。
+	// 默认值: false
+	DisablePurge bool
+
+	// LatencyBuckets 定义任务延迟直方图的桶上界。
+	// 非空时启用排队等待时间和执行时间的统计，结果通过 Stats() 获取。
+	// 默认值: nil（不统计）
+	LatencyBuckets []time.Duration
+
+	// OnTaskStart 在每个任务开始执行前调用。
+	// 默认值: nil
+	OnTaskStart func(TaskInfo)
+
+	// OnTaskComplete 在每个任务执行结束后调用（包括返回错误和发生 panic 的情况）。
+	// 默认值: nil
+	OnTaskComplete func(TaskInfo)
+
+	// OnWorkerCreate 在 worker goroutine 启动时调用，参数为 worker ID。
+	// 默认值: nil
+	OnWorkerCreate func(workerID int)
+
+	// OnWorkerExpire 在 worker 因空闲超时被回收时调用，参数为 worker ID。
+	// 默认值: nil
+	OnWorkerExpire func(workerID int)
+
+	// OnWorkerExit 在 worker goroutine 退出时调用（无论退出原因），参数为 worker ID。
+	// 默认值: nil
+	OnWorkerExit func(workerID int)
+
+	// OverloadHandler 在提交因池过载（ErrPoolOverload）被拒绝时调用。
+	// 默认值: nil
+	OverloadHandler func(OverloadInfo)
+
+	// WatchdogLimit 定义任务执行时间的上限，超过后视为卡住的 worker。
+	// 为 0 时不启用看门狗。
+	// 默认值: 0
+	WatchdogLimit time.Duration
+
+	// OnStuckWorker 在看门狗发现卡住的 worker 时调用。
+	// 未设置时将 worker 的栈写入日志。
+	// 默认值: nil
+	OnStuckWorker func(WorkerStack)
+
+	// StackDumps 指定是否记录 worker 的 goroutine 和忙碌状态，供 DumpStacks 使用。
+	// 启用看门狗（WatchdogLimit）或泄漏检查（LeakCheckGrace）时自动记录。
+	// 默认值: false
+	StackDumps bool
+
+	// PprofLabels 指定是否为 worker goroutine 设置 pprof 标签。
+	// 默认值: false
+	PprofLabels bool
+
+	// WorkerInit 在创建新 worker 时调用，返回值作为该 worker 的 per-worker 资源。
+	// 仅对 Pool 生效，PoolWithFunc 拒绝此选项。
+	// 默认值: nil
+	WorkerInit func() (interface{}, error)
+
+	// WorkerTeardown 在 worker 退出时调用，用于释放 WorkerInit 创建的资源。
+	// 仅对 Pool 生效，PoolWithFunc 拒绝此选项。
+	// 默认值: nil
+	WorkerTeardown func(interface{})
+
+	// QuarantineThreshold 函数池连续 panic 达到此次数时暂停接收任务。
+	// 0 表示不启用。目前仅对 PoolWithFunc 生效。
+	// 默认值: 0
+	QuarantineThreshold int
+
+	// QuarantineCooldown 函数池暂停接收任务的时长。
+	// 默认值: 0
+	QuarantineCooldown time.Duration
+
+	// OnQuarantine 在函数池进入隔离状态时调用。
+	// 默认值: nil
+	OnQuarantine func(QuarantineInfo)
+
+	// TaskQueue 函数池没有空闲 worker 时暂存提交的参数的队列。
+	// 仅对 PoolWithFunc 生效，Pool 拒绝此选项。
+	// 默认值: nil（按阻塞/非阻塞配置处理）
+	TaskQueue TaskQueue
+
+	// WorkStealing 指定分片池在选中的子池已满时，是否先尝试其他子池的空闲容量，
+	// 并让阻塞的提交由最先空出 worker 的子池接手。
+	// 仅对 MultiPool 和 MultiPoolWithFunc 生效。
+	// 默认值: false
+	WorkStealing bool
+
+	// Budget 定义与其他池共享的并发预算。
+	// 设置后池中同时执行的任务还受预算容量限制。
+	// 默认值: nil（不限制）
+	Budget *Budget
+
+	// SpilloverLimit 池饱和时最多允许多少个任务在临时的溢出 worker 上执行。
+	// 0 表示不启用，池饱和时按阻塞/非阻塞配置处理。
+	// 默认值: 0
+	SpilloverLimit int
+
+	// ShardedLocking 指定是否将空闲 worker 分散到 GOMAXPROCS 个独立加锁的桶中。
+	// 默认值: false
+	ShardedLocking bool
+
+	// SpinLock 指定池的锁是否使用带指数退避的自旋锁代替 sync.Mutex。
+	// 默认值: false
+	SpinLock bool
+
+	// LeakCheckGrace 定义池关闭后等待所有 worker 退出的时间，超时仍未退出的 worker 视为泄漏。
+	// 为 0 时不检查。
+	// 默认值: 0
+	LeakCheckGrace time.Duration
+
+	// OnLeak 在关闭后的泄漏检查发现未退出的 worker 时调用。
+	// 未设置时将这些 worker 的栈写入日志。
+	// 默认值: nil
+	OnLeak func(LeakReport)
+
+	// QueueType 定义空闲 worker 队列使用的数据结构。
+	// AutoQueue 时按容量和 QueueThreshold 自动选择。
+	// 默认值: AutoQueue
+	QueueType QueueType
+
+	// QueueThreshold 定义 AutoQueue 下改用循环队列的容量阈值。
+	// 为 0 时使用 1000。
+	// 默认值: 0
+	QueueThreshold int
+
+	// WorkerQueueFactory 创建自定义的空闲 worker 队列，参数为池的容量。
+	// 设置后优先于 QueueType。
+	// 默认值: nil（使用内置的栈或循环队列）
+	WorkerQueueFactory func(size int) WorkerQueue
+
 	// Logger 定义日志记录器接口。
 	// 用于记录池的运行状态和错误信息。
 	// 默认值: 空日志记录器（不输出）
 	Logger Logger
+
+	// EventLogger 接收结构化日志事件的日志记录器。
+	// 设置后池的日志事件交给它而不是 Logger。
+	// 默认值: nil（事件格式化为 logfmt 文本后写入 Logger）
+	EventLogger EventLogger
+
+	// LogSampleBurst 每个 LogSamplePeriod 内每种日志事件最多记录的条数。
+	// 超出的事件被丢弃，数量记录在下一条同名事件的 suppressed 字段中。
+	// 默认值: 0（不采样，记录所有事件）
+	LogSampleBurst int
+
+	// LogSamplePeriod 日志采样的时间窗口。
+	// 默认值: 0
+	LogSamplePeriod time.Duration
+
+	// Trace 创建池时是否开启逐个任务的追踪日志，之后可以通过 SetTrace 开关。
+	// 默认值: false
+	Trace bool
+
+	// EventBuffer Events 返回的 channel 的缓冲大小，为 0 时使用 DefaultEventBuffer。
+	// 默认值: 0
+	EventBuffer int
+
+	// ErrorHandler 接收池内部的运行错误，例如队列操作失败和内部状态不一致。
+	// 默认值: nil（以 pool_error 事件写入日志）
+	ErrorHandler func(err error)
+
+	// sampler 按 LogSampleBurst 和 LogSamplePeriod 创建的采样器，由 NewOptions 创建
+	sampler *logSampler
 }
 
 // Option 定义函数式选项类型。
@@ -93,9 +285,86 @@ func NewOptions(opts ...Option) *Options {
 		opt(options)
 	}
 
+	// 每个池拥有独立的采样状态
+	if options.LogSampleBurst > 0 && options.LogSamplePeriod > 0 {
+		options.sampler = newLogSampler(options.LogSampleBurst, options.LogSamplePeriod, options.logSuppressed)
+	}
+
 	return options
 }
 
+// clone 返回选项的副本，切片字段也被复制
+func (opts *Options) clone() Options {
+	c := *opts
+	if opts.LatencyBuckets != nil {
+		c.LatencyBuckets = append([]time.Duration(nil), opts.LatencyBuckets...)
+	}
+	return c
+}
+
+// validate 检查组合后的选项对容量为 size 的池是否有效
+// 过期时间和清理间隔为负数时分别返回 ErrInvalidPoolExpiry 和 ErrInvalidCleanInterval，
+// 其他无意义的配置返回包装了 ErrInvalidOption 并说明原因的错误，使配置错误在创建池时暴露。
+func (opts *Options) validate(size int) error {
+	if opts.ExpiryDuration < 0 {
+		return ErrInvalidPoolExpiry
+	}
+	if opts.CleanInterval < 0 {
+		return ErrInvalidCleanInterval
+	}
+
+	switch {
+	case opts.Logger == nil:
+		return invalidOption("Logger must not be nil")
+	case opts.PreAlloc && size == -1:
+		return invalidOption("PreAlloc requires a bounded pool size")
+	case opts.SizeMultiplier < 0:
+		return invalidOption("size multiplier must not be negative: %d", opts.SizeMultiplier)
+	case opts.MaxBlockingTasks < 0:
+		return invalidOption("MaxBlockingTasks must not be negative: %d", opts.MaxBlockingTasks)
+	case opts.WatchdogLimit < 0:
+		return invalidOption("watchdog limit must not be negative: %v", opts.WatchdogLimit)
+	case opts.QuarantineThreshold > 0 && opts.QuarantineCooldown <= 0:
+		return invalidOption("panic quarantine requires a positive cooldown: %v", opts.QuarantineCooldown)
+	case opts.LeakCheckGrace < 0:
+		return invalidOption("leak check grace must not be negative: %v", opts.LeakCheckGrace)
+	case !opts.QueueType.valid():
+		return invalidOption("unknown queue type: %d", opts.QueueType)
+	case opts.QueueType == LoopQueue && size == -1:
+		return invalidOption("LoopQueue requires a bounded pool size")
+	case opts.QueueThreshold < 0:
+		return invalidOption("queue threshold must not be negative: %d", opts.QueueThreshold)
+	case opts.EventBuffer < 0:
+		return invalidOption("event buffer must not be negative: %d", opts.EventBuffer)
+	case opts.LogSampleBurst < 0:
+		return invalidOption("log sample burst must not be negative: %d", opts.LogSampleBurst)
+	case opts.LogSampleBurst > 0 && opts.LogSamplePeriod <= 0:
+		return invalidOption("log sampling requires a positive period: %v", opts.LogSamplePeriod)
+	}
+	return nil
+}
+
+// queueType 返回容量为 size 的池实际使用的队列类型，总是 Stack 或 LoopQueue
+func (opts *Options) queueType(size int) QueueType {
+	if opts.QueueType != AutoQueue {
+		return opts.QueueType
+	}
+
+	threshold := opts.QueueThreshold
+	if threshold == 0 {
+		threshold = queueSizeThreshold
+	}
+	if size == -1 || size < threshold {
+		return Stack
+	}
+	return LoopQueue
+}
+
+// invalidOption 返回包装了 ErrInvalidOption 的错误，说明哪个选项无效
+func invalidOption(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: "+format, append([]interface{}{ErrInvalidOption}, args...)...)
+}
+
 // WithExpiryDuration 设置 Worker 的空闲超时时间。
 //
 // Worker 空闲时间超过此值后将被回收以释放资源。
@@ -117,6 +386,77 @@ func WithExpiryDuration(duration time.Duration) Option {
 	}
 }
 
+// WithCleanInterval 设置清理 goroutine 扫描过期 worker 的间隔。
+//
+// 扫描间隔与 ExpiryDuration 相互独立：ExpiryDuration 决定 worker 可以空闲多久，
+// CleanInterval 决定多久检查一次。例如 10 分钟的过期时间配合 1 秒的扫描间隔，
+// 过期的 worker 最多延迟 1 秒即被回收。
+//
+// 参数:
+//   - interval: 扫描间隔，必须为非负数，0 表示使用默认值
+//
+// 返回:
+//   - Option: 配置选项函数
+//
+// 示例:
+//
+//	pool, _ := laborer.NewPool(10,
+//	    laborer.WithExpiryDuration(10*time.Minute),
+//	    laborer.WithCleanInterval(time.Second))
+func WithCleanInterval(interval time.Duration) Option {
+	return func(opts *Options) {
+		opts.CleanInterval = interval
+	}
+}
+
+// WithClock 设置过期回收使用的时钟。
+//
+// worker 的创建和归还时间、清理 goroutine 的扫描周期和过期判断都从时钟读取，
+// 其他计时（任务超时、看门狗、延迟统计等）仍使用系统时间。
+// 主要用于测试：配合 laborertest.FakeClock 推进时间即可触发过期回收。
+// 自定义的 WorkerQueue 在 Refresh 中需要自行使用同一个时钟判断过期。
+//
+// 参数:
+//   - clock: 时钟，nil 表示使用系统时间
+//
+// 返回:
+//   - Option: 配置选项函数
+//
+// 示例:
+//
+//	clock := laborertest.NewFakeClock(time.Now())
+//	pool, _ := laborer.NewPool(10,
+//	    laborer.WithExpiryDuration(time.Minute),
+//	    laborer.WithClock(clock))
+//	clock.Advance(2 * time.Minute) // 空闲的 worker 在下一次扫描时被回收
+func WithClock(clock Clock) Option {
+	return func(opts *Options) {
+		opts.Clock = clock
+	}
+}
+
+// WithSizeMultiplier 设置容量为 0 时按 CPU 数量换算容量的倍数。
+//
+// NewPool(0) 和 NewPoolWithFunc(0, ...) 创建容量为 runtime.GOMAXPROCS(0) * multiplier 的池，
+// 使只需要"合适大小的池"的调用方不必硬编码 CPU 数量。指定了正数容量时此选项不生效。
+// CPU 密集的任务通常使用默认的 1 倍，IO 密集的任务可以使用更大的倍数。
+//
+// 参数:
+//   - multiplier: 倍数，必须为非负数，0 表示使用默认值 1
+//
+// 返回:
+//   - Option: 配置选项函数
+//
+// 示例:
+//
+//	// 8 核机器上创建容量为 32 的池
+//	pool, _ := laborer.NewPool(0, laborer.WithSizeMultiplier(4))
+func WithSizeMultiplier(multiplier int) Option {
+	return func(opts *Options) {
+		opts.SizeMultiplier = multiplier
+	}
+}
+
 // WithPreAlloc 设置是否预分配 worker 切片。
 //
 // 启用预分配会在池创建时立即分配所有 worker 的内存空间，
@@ -124,7 +464,7 @@ func WithExpiryDuration(duration time.Duration) Option {
 // 适合容量固定且已知的场景。
 //
 // 参数:
-//   - preAlloc: true 表示启用预分配，false 表示按需分配
+//   - preAlloc: true 表示启用预分配，false 表示按需分配；无限容量的池不能启用
 //
 // 返回:
 //   - Option: 配置选项函数
@@ -138,12 +478,14 @@ func WithPreAlloc(preAlloc bool) Option {
 	}
 }
 
-// WithMaxBlockingTasks 设置最大阻塞任务数量。
+// WithMaxBlockingTasks 设置阻塞模式下同时等待 worker 的提交数上限。
 //
-// 此选项当前保留用于未来扩展，暂未实现具体功能。
+// 池已满且等待 worker 的提交数已达上限时，新的提交不再等待，
+// 与非阻塞模式下池已满时相同：尝试在溢出 worker 上执行，仍无法执行时返回 ErrPoolOverload
+// 并计入被拒绝的任务数。用于限制阻塞等待的 goroutine 数量，避免突发流量下无限堆积。
 //
 // 参数:
-//   - maxBlockingTasks: 最大阻塞任务数量
+//   - maxBlockingTasks: 等待 worker 的提交数上限，0 表示不限制，不能为负数
 //
 // 返回:
 //   - Option: 配置选项函数
@@ -183,7 +525,8 @@ func WithNonblocking(nonblocking bool) Option {
 // WithPanicHandler 设置任务执行时的 panic 处理函数。
 //
 // 当任务执行过程中发生 panic 时，会调用此处理函数。
-// 如果未设置，panic 会被记录到日志中。
+// 如果未设置，panic 以 Error 级别的 worker_panic 事件记录到日志中，
+// 完整的堆栈以 Debug 级别的 worker_panic_stack 事件单独记录。
 // 处理函数可以用于记录错误、发送告警或执行清理操作。
 //
 // 参数:
@@ -204,13 +547,39 @@ func WithPanicHandler(panicHandler func(interface{})) Option {
 	}
 }
 
+// WithPanicHandlerV2 设置带有栈和任务元数据的 panic 处理函数。
+//
+// 相比 WithPanicHandler，处理函数额外收到 panic 发生时的 goroutine 栈
+// 和任务元数据（所属池、提交时间、排队等待时间、执行时间等），
+// 便于在崩溃报告中定位任务的来源。设置后优先于 WithPanicHandler。
+// 启用后池会为每个任务记录时间元数据。
+//
+// 参数:
+//   - handler: panic 处理函数
+//
+// 返回:
+//   - Option: 配置选项函数
+//
+// 示例:
+//
+//	pool, _ := laborer.NewPool(10, laborer.WithPanicHandlerV2(
+//	    func(recovered interface{}, stack []byte, info laborer.TaskInfo) {
+//	        log.Printf("pool %s: task panicked after %v: %v\n%s",
+//	            info.Pool, info.Duration, recovered, stack)
+//	    }))
+func WithPanicHandlerV2(handler func(recovered interface{}, stack []byte, info TaskInfo)) Option {
+	return func(opts *Options) {
+		opts.PanicHandlerV2 = handler
+	}
+}
+
 // WithLogger 设置自定义日志记录器。
 //
 // 日志记录器用于记录池的运行状态、错误信息和调试信息。
 // 必须实现 Logger 接口。
 //
 // 参数:
-//   - logger: 实现了 Logger 接口的日志记录器
+//   - logger: 实现了 Logger 接口的日志记录器，不能为 nil
 //
 // 返回:
 //   - Option: 配置选项函数
@@ -228,3 +597,722 @@ func WithLogger(logger Logger) Option {
 		opts.Logger = logger
 	}
 }
+
+// WithEventLogger 设置接收结构化日志事件的日志记录器。
+//
+// 池的日志以 LogEvent 的形式交给 logger，字段保持原始类型，
+// 便于日志管道按事件名称和字段解析。设置后不再写入 Logger。
+//
+// 参数:
+//   - logger: 实现了 EventLogger 接口的日志记录器，nil 表示使用 Logger
+//
+// 返回:
+//   - Option: 配置选项函数
+//
+// 示例:
+//
+//	pool, _ := laborer.NewPool(10, laborer.WithEventLogger(slogEvents{slog.Default()}))
+func WithEventLogger(logger EventLogger) Option {
+	return func(opts *Options) {
+		opts.EventLogger = logger
+	}
+}
+
+// WithLogSampling 限制重复日志事件的速率。
+//
+// 大量 worker 同时过期或 panic 风暴时，池可能在短时间内写入成千上万条相同的日志。
+// 启用后每种事件（按 LogEvent.Event 区分）在每个 period 内最多记录 burst 条，
+// 其余的被丢弃并计数。窗口结束时记录一条 Warn 级别的 log_suppressed 事件，
+// 其 sampled_event 和 suppressed 字段给出被丢弃的事件名称和数量。
+// 只影响日志，不影响 OnStuckWorker、OnLeak 等回调。
+//
+// 参数:
+//   - burst: 每个时间窗口内每种事件最多记录的条数，0 表示不采样
+//   - period: 时间窗口，burst 大于 0 时必须为正数
+//
+// 返回:
+//   - Option: 配置选项函数
+//
+// 示例:
+//
+//	// 每种事件每秒最多 10 条
+//	pool, _ := laborer.NewPool(1000,
+//	    laborer.WithLogger(log.Default()),
+//	    laborer.WithLogSampling(10, time.Second))
+func WithLogSampling(burst int, period time.Duration) Option {
+	return func(opts *Options) {
+		opts.LogSampleBurst = burst
+		opts.LogSamplePeriod = period
+	}
+}
+
+// WithTrace 设置创建池时是否开启逐个任务的追踪日志。
+//
+// 开启后每个任务以 LevelDebug 记录以下事件，字段中的 task_id 在池内唯一:
+//
+//	task_submitted   task_id                           任务被提交
+//	task_dispatched  task_id, worker_id, wait          任务交给 worker 开始执行
+//	task_completed   task_id, worker_id, duration, panicked
+//	task_rejected    task_id, error                    任务没有被执行，例如池已满或已关闭
+//	task_queued      task_id                           函数池的调用放入了任务队列，之后不再追踪
+//
+// 追踪会为每个任务写入多条日志，只适合在测试环境排查问题时使用。
+// 池运行期间可以通过 SetTrace 随时开关，不必重新创建池。
+//
+// 参数:
+//   - enabled: 是否开启追踪
+//
+// 返回:
+//   - Option: 配置选项函数
+//
+// 示例:
+//
+//	pool, _ := laborer.NewPool(10,
+//	    laborer.WithLogger(log.Default()),
+//	    laborer.WithTrace(os.Getenv("POOL_TRACE") != ""))
+//
+//	// 运行期间开关
+//	pool.SetTrace(true)
+func WithTrace(enabled bool) Option {
+	return func(opts *Options) {
+		opts.Trace = enabled
+	}
+}
+
+// WithEventBuffer 设置 Events 返回的 channel 的缓冲大小。
+//
+// 消费者处理不及时时，缓冲可以吸收突发的事件（例如大量 worker 同时过期），
+// 缓冲已满时新的事件被丢弃并计入 DroppedEvents。
+//
+// 参数:
+//   - size: 缓冲大小，0 表示使用 DefaultEventBuffer
+//
+// 返回:
+//   - Option: 配置选项函数
+//
+// 示例:
+//
+//	pool, _ := laborer.NewPool(100, laborer.WithEventBuffer(1024))
+//	go func() {
+//	    for e := range pool.Events() {
+//	        if e.Type == laborer.TaskRejected {
+//	            autoscaler.ScaleUp()
+//	        }
+//	    }
+//	}()
+func WithEventBuffer(size int) Option {
+	return func(opts *Options) {
+		opts.EventBuffer = size
+	}
+}
+
+// WithErrorHandler 设置池内部运行错误的处理函数。
+//
+// 提交无法返回的内部错误默认以 pool_error 事件写入日志，设置后改为调用 handler，
+// 程序可以对这些错误计数或告警，而不必在日志中搜索。错误包装了以下 sentinel 之一，
+// 可以用 errors.Is 区分：
+//   - ErrWorkerQueue: 空闲 worker 无法放回队列，或自定义 WorkerQueue 返回了不属于此池的 worker
+//   - ErrInvariant: 池检测到内部状态不一致，例如运行中的 worker 计数变为负数
+//   - ErrTaskQueue: TaskQueue 取出或确认参数失败
+//
+// 设置了池名称时错误带有 pool "<name>": 前缀。handler 可能在池持有内部锁时被调用，
+// 应该尽快返回，不能调用池的方法。
+//
+// 参数:
+//   - handler: 错误处理函数，nil 表示写入日志
+//
+// 返回:
+//   - Option: 配置选项函数
+//
+// 示例:
+//
+//	pool, _ := laborer.NewPool(10, laborer.WithErrorHandler(func(err error) {
+//	    poolErrors.Inc()
+//	    log.Printf("internal pool error: %v", err)
+//	}))
+func WithErrorHandler(handler func(err error)) Option {
+	return func(opts *Options) {
+		opts.ErrorHandler = handler
+	}
+}
+
+// WithDisablePurge 设置是否禁用过期 worker 的清理。
+//
+// 禁用后池不会启动后台清理 goroutine，空闲的 worker 不会因超时被回收，
+// 适合希望 worker 长期常驻、避免反复创建的场景。
+// 仍然可以通过 PurgeNow 手动回收空闲 worker。
+//
+// 参数:
+//   - disable: true 表示禁用清理，false 表示启用清理
+//
+// 返回:
+//   - Option: 配置选项函数
+//
+// 示例:
+//
+//	pool, _ := laborer.NewPool(10, laborer.WithDisablePurge(true))
+func WithDisablePurge(disable bool) Option {
+	return func(opts *Options) {
+		opts.DisablePurge = disable
+	}
+}
+
+// blockingFull 检查等待 worker 的提交数是否已达 MaxBlockingTasks 上限
+func (opts *Options) blockingFull(waiting int64) bool {
+	return opts.MaxBlockingTasks > 0 && waiting >= int64(opts.MaxBlockingTasks)
+}
+
+// poolSize 返回容量参数对应的实际容量
+// 容量为 0 时换算为 runtime.GOMAXPROCS(0) 乘以 SizeMultiplier，其他容量原样返回。
+func (opts *Options) poolSize(size int) int {
+	if size != 0 {
+		return size
+	}
+	multiplier := opts.SizeMultiplier
+	if multiplier <= 0 {
+		multiplier = 1
+	}
+	return runtime.GOMAXPROCS(0) * multiplier
+}
+
+// cleanInterval 返回实际生效的清理扫描间隔
+func (opts *Options) cleanInterval() time.Duration {
+	if opts.CleanInterval > 0 {
+		return opts.CleanInterval
+	}
+
+	if opts.ExpiryDuration > 0 && opts.ExpiryDuration < DefaultCleanIntervalTime {
+		return opts.ExpiryDuration
+	}
+
+	return DefaultCleanIntervalTime
+}
+
+// WithLatencyHistogram 启用任务延迟直方图统计。
+//
+// 启用后池会分别记录每个任务的排队等待时间（从提交到开始执行）
+// 和执行时间，并在 Stats() 中提供 min/avg/p50/p95/p99/max 及各桶分布，
+// 用于区分"池容量不足"与"任务本身过慢"两类问题。
+// 统计会为每个任务引入一次闭包包装和两次时间采样的开销。
+//
+// 参数:
+//   - buckets: 直方图桶上界，为空时使用 DefaultLatencyBuckets
+//
+// 返回:
+//   - Option: 配置选项函数
+//
+// 示例:
+//
+//	pool, _ := laborer.NewPool(10, laborer.WithLatencyHistogram())
+//	stats := pool.Stats()
+//	log.Printf("wait p95=%v exec p95=%v", stats.QueueWait.P95, stats.Execution.P95)
+func WithLatencyHistogram(buckets ...time.Duration) Option {
+	return func(opts *Options) {
+		if len(buckets) == 0 {
+			buckets = DefaultLatencyBuckets
+		}
+		opts.LatencyBuckets = buckets
+	}
+}
+
+// WithName 设置池的名称。
+//
+// 名称用于在 Pools() 注册表、监控指标和 DebugHandler 中区分不同的池，
+// 例如 "image-resize" 与 "webhook"。名称不要求唯一。
+// 设置后池的日志事件带有 pool 字段，PanicError 以及 ReleaseTimeout 和 worker 初始化失败的错误
+// 带有 pool "<name>": 前缀，多个池共用一个 Logger 时可以区分来源；
+// Submit 返回的 ErrPoolClosed、ErrPoolOverload 等 sentinel 错误不会被包装。
+//
+// 参数:
+//   - name: 池的名称
+//
+// 返回:
+//   - Option: 配置选项函数
+//
+// 示例:
+//
+//	pool, _ := laborer.NewPool(10, laborer.WithName("image-resize"))
+func WithName(name string) Option {
+	return func(opts *Options) {
+		opts.Name = name
+	}
+}
+
+// WithTaskHooks 设置任务生命周期钩子。
+//
+// onStart 在任务开始执行前调用，onComplete 在任务结束后调用，
+// 两者都会收到包含排队等待时间、执行时间、错误和 panic 信息的 TaskInfo。
+// 这是接入自定义指标、审计日志和 SLO 统计的扩展点。
+//
+// 钩子在 worker goroutine 中同步执行，应当尽量轻量；
+// 钩子本身发生 panic 会被视为任务 panic，每个任务的 onComplete 仍然只调用一次。任意一个参数可以为 nil。
+//
+// 参数:
+//   - onStart: 任务开始时的回调
+//   - onComplete: 任务结束时的回调
+//
+// 返回:
+//   - Option: 配置选项函数
+//
+// 示例:
+//
+//	pool, _ := laborer.NewPool(10, laborer.WithTaskHooks(nil, func(info laborer.TaskInfo) {
+//	    if info.Panic != nil || info.Err != nil {
+//	        metrics.TaskFailures.Inc()
+//	    }
+//	    metrics.TaskDuration.Observe(info.Duration.Seconds())
+//	}))
+func WithTaskHooks(onStart, onComplete func(TaskInfo)) Option {
+	return func(opts *Options) {
+		opts.OnTaskStart = onStart
+		opts.OnTaskComplete = onComplete
+	}
+}
+
+// WithWorkerHooks 设置 worker 生命周期钩子。
+//
+// 每个 worker 启动时分配一个在池内唯一的 ID：
+//   - onCreate: worker goroutine 启动时调用
+//   - onExpire: worker 因空闲超时被清理时调用（随后还会调用 onExit）
+//   - onExit: worker goroutine 退出时调用，包括超时回收、任务 panic 和池关闭
+//
+// 钩子在 worker goroutine 中执行，可用于跟踪 worker 的创建与回收频率、
+// 绑定 per-worker 资源或排查异常的 worker 更替。任意一个参数可以为 nil。
+//
+// 参数:
+//   - onCreate: worker 创建时的回调
+//   - onExpire: worker 过期时的回调
+//   - onExit: worker 退出时的回调
+//
+// 返回:
+//   - Option: 配置选项函数
+//
+// 示例:
+//
+//	pool, _ := laborer.NewPool(10, laborer.WithWorkerHooks(
+//	    func(id int) { log.Printf("worker %d created", id) },
+//	    func(id int) { log.Printf("worker %d expired", id) },
+//	    func(id int) { log.Printf("worker %d exited", id) },
+//	))
+func WithWorkerHooks(onCreate, onExpire, onExit func(workerID int)) Option {
+	return func(opts *Options) {
+		opts.OnWorkerCreate = onCreate
+		opts.OnWorkerExpire = onExpire
+		opts.OnWorkerExit = onExit
+	}
+}
+
+// WithOverloadHandler 设置池过载时的回调函数。
+//
+// 每当提交因 ErrPoolOverload 被拒绝时，会在提交方的 goroutine 中同步调用此回调，
+// 参数包含当时的运行数、等待数和容量。服务可以借此输出有针对性的告警或触发扩容，
+// 而不必在每个调用点统计错误。回调应当尽量轻量。
+//
+// 参数:
+//   - handler: 过载回调函数
+//
+// 返回:
+//   - Option: 配置选项函数
+//
+// 示例:
+//
+//	pool, _ := laborer.NewPool(10, laborer.WithNonblocking(true),
+//	    laborer.WithOverloadHandler(func(info laborer.OverloadInfo) {
+//	        log.Printf("pool %s overloaded: running=%d cap=%d", info.Pool, info.Running, info.Cap)
+//	    }))
+func WithOverloadHandler(handler func(OverloadInfo)) Option {
+	return func(opts *Options) {
+		opts.OverloadHandler = handler
+	}
+}
+
+// WithWatchdog 启用卡住 worker 的看门狗。
+//
+// 看门狗每隔 limit/2 检查一次，发现某个 worker 的当前任务执行超过 limit 时，
+// 获取该 worker 的 goroutine 栈并调用 onStuck（未设置时写入日志）。
+// 同一个任务只会上报一次。适合在生产环境中定位挂起的任务，
+// 而不必导出完整的 goroutine dump。
+//
+// 参数:
+//   - limit: 任务执行时间上限，必须为正数
+//   - onStuck: 发现卡住 worker 时的回调，可以为 nil
+//
+// 返回:
+//   - Option: 配置选项函数
+//
+// 示例:
+//
+//	pool, _ := laborer.NewPool(10, laborer.WithWatchdog(time.Minute, func(s laborer.WorkerStack) {
+//	    log.Printf("worker %d stuck for %v:\n%s", s.WorkerID, s.BusyFor, s.Stack)
+//	}))
+func WithWatchdog(limit time.Duration, onStuck func(WorkerStack)) Option {
+	return func(opts *Options) {
+		opts.WatchdogLimit = limit
+		opts.OnStuckWorker = onStuck
+	}
+}
+
+// WithStackDumps 设置是否记录 worker 的 goroutine 和忙碌状态，使 DumpStacks 可用。
+//
+// 记录需要在每个 worker 启动时解析 goroutine ID，并在每个任务前后读取时间，
+// 因此默认关闭，未启用时 DumpStacks 返回 nil。启用看门狗（WithWatchdog）或
+// 泄漏检查（WithLeakCheck）时会自动记录，不必再设置此选项。
+//
+// 参数:
+//   - enable: true 表示记录，false 表示不记录
+//
+// 返回:
+//   - Option: 配置选项函数
+//
+// 示例:
+//
+//	pool, _ := laborer.NewPool(10, laborer.WithStackDumps(true))
+//	for _, s := range pool.DumpStacks() {
+//	    log.Printf("worker %d busy for %v:\n%s", s.WorkerID, s.BusyFor, s.Stack)
+//	}
+func WithStackDumps(enable bool) Option {
+	return func(opts *Options) {
+		opts.StackDumps = enable
+	}
+}
+
+// WithPprofLabels 设置是否为池中的任务附加 pprof 标签。
+//
+// 启用后 worker goroutine 会带有 laborer_pool=<池名称> 标签，
+// CPU profile 和 goroutine profile 可以将耗时归属到具体的池，
+// 而不是一堆匿名闭包。标签在 worker 启动时设置一次，不影响任务执行的性能。
+// 建议与 WithName 一起使用。
+//
+// 参数:
+//   - enable: true 表示启用 pprof 标签
+//
+// 返回:
+//   - Option: 配置选项函数
+//
+// 示例:
+//
+//	pool, _ := laborer.NewPool(10,
+//	    laborer.WithName("image-resize"),
+//	    laborer.WithPprofLabels(true))
+func WithPprofLabels(enable bool) Option {
+	return func(opts *Options) {
+		opts.PprofLabels = enable
+	}
+}
+
+// WithWorkerInit 设置 worker 初始化函数。
+//
+// 每个 worker 创建时调用一次，返回的值会在该 worker 的整个生命周期内保留，
+// 并作为参数传给通过 SubmitWithState 提交的任务。这使得"每个 worker 一个
+// 数据库连接 / 缓冲区 / cgo 句柄"的模式成为可能。
+//
+// 初始化在提交方的 goroutine 中同步执行；返回错误时本次提交失败，
+// 返回包装了 ErrWorkerInit 的错误。仅对 Pool 生效，NewPoolWithFunc 遇到
+// 此选项时返回包装了 ErrInvalidOption 的错误。
+//
+// 参数:
+//   - init: 初始化函数
+//
+// 返回:
+//   - Option: 配置选项函数
+//
+// 示例:
+//
+//	pool, _ := laborer.NewPool(10,
+//	    laborer.WithWorkerInit(func() (interface{}, error) { return sql.Open("postgres", dsn) }),
+//	    laborer.WithWorkerTeardown(func(v interface{}) { v.(*sql.DB).Close() }))
+//
+//	pool.SubmitWithState(func(state interface{}) {
+//	    db := state.(*sql.DB)
+//	    // 使用 db
+//	})
+func WithWorkerInit(init func() (interface{}, error)) Option {
+	return func(opts *Options) {
+		opts.WorkerInit = init
+	}
+}
+
+// WithWorkerTeardown 设置 worker 退出时的清理函数。
+//
+// worker 因空闲超时、任务 panic 或池关闭而退出时调用，
+// 参数为 WithWorkerInit 创建的值。只有设置了 WithWorkerInit 时才会调用。
+// 与 WithWorkerInit 一样仅对 Pool 生效。
+//
+// 参数:
+//   - teardown: 清理函数
+//
+// 返回:
+//   - Option: 配置选项函数
+func WithWorkerTeardown(teardown func(interface{})) Option {
+	return func(opts *Options) {
+		opts.WorkerTeardown = teardown
+	}
+}
+
+// WithPanicQuarantine 启用函数池的 panic 隔离。
+//
+// 固定函数连续 panic threshold 次时（中间没有成功执行的调用），池进入隔离状态：
+// cooldown 时间内 Invoke 直接返回 ErrPoolQuarantined，并调用 onQuarantine。
+// 冷却结束后自动恢复接收任务。这可以防止有毒的输入让 worker 陷入 panic 循环
+// 持续消耗 CPU。onQuarantine 可以为 nil。目前仅对 PoolWithFunc 生效。
+//
+// 参数:
+//   - threshold: 触发隔离的连续 panic 次数，小于等于 0 表示不启用
+//   - cooldown: 隔离时长
+//   - onQuarantine: 进入隔离时的回调函数
+//
+// 返回:
+//   - Option: 配置选项函数
+//
+// 示例:
+//
+//	pool, _ := laborer.NewPoolWithFunc(10, handle,
+//	    laborer.WithPanicQuarantine(5, 30*time.Second, func(info laborer.QuarantineInfo) {
+//	        log.Printf("pool %s quarantined until %v", info.Pool, info.Until)
+//	    }))
+func WithPanicQuarantine(threshold int, cooldown time.Duration, onQuarantine func(QuarantineInfo)) Option {
+	return func(opts *Options) {
+		opts.QuarantineThreshold = threshold
+		opts.QuarantineCooldown = cooldown
+		opts.OnQuarantine = onQuarantine
+	}
+}
+
+// WithTaskQueue 为函数池设置待执行任务队列。
+//
+// 设置后池没有空闲 worker 时，Invoke 将参数放入 queue 并立即返回，而不是阻塞或返回
+// ErrPoolOverload；worker 执行完当前的调用后从队列中取出下一个参数。队列已满
+// （Push 返回 ErrTaskQueueFull）时按阻塞/非阻塞配置处理。Waiting 包括队列中的参数，
+// Wait 和 Drain 会等待通过池提交的参数执行完毕。池关闭时队列中的参数保留在队列中，
+// Reboot 后继续执行。以 Redis 或磁盘实现 TaskQueue 可以让未执行的任务在进程重启后
+// 继续执行：创建池时队列中已有的参数会被执行，取出后计为待执行的调用。
+// 仅对 PoolWithFunc 生效：Pool 的任务是闭包，无法写入持久化队列，
+// NewPool 遇到此选项时返回包装了 ErrInvalidOption 的错误。
+//
+// 参数:
+//   - queue: 任务队列，nil 表示不使用队列
+//
+// 返回:
+//   - Option: 配置选项函数
+//
+// 示例:
+//
+//	// 最多缓冲 10000 个参数，超出时阻塞提交方
+//	pool, _ := laborer.NewPoolWithFunc(100, handle,
+//	    laborer.WithTaskQueue(laborer.NewMemoryTaskQueue(10000)))
+func WithTaskQueue(queue TaskQueue) Option {
+	return func(opts *Options) {
+		opts.TaskQueue = queue
+	}
+}
+
+// WithWorkStealing 启用分片池的工作窃取。
+//
+// 负载均衡策略选中的子池已满时，提交不会立即在该子池上等待，
+// 而是依次尝试其他子池的空闲 worker 或剩余容量。所有子池都已满时：
+// 非阻塞模式返回 ErrPoolOverload；阻塞模式下提交在所有子池共享的队列中等待，
+// 任意子池空出 worker 且没有自己的等待者时接手这些积压的提交，而不是只能等待
+// 选中的子池。这样突发流量集中到某个子池时，其他子池的空闲容量不会被浪费。
+// 设置了 TaskQueue 的函数池把参数放入共享的队列，由空闲的子池取出；设置了 Budget 时
+// 阻塞的提交仍在选中的子池上等待预算。仅对 MultiPool 和 MultiPoolWithFunc 生效。
+//
+// 参数:
+//   - enable: 是否启用工作窃取
+//
+// 返回:
+//   - Option: 配置选项函数
+//
+// 示例:
+//
+//	mp, _ := laborer.NewMultiPool(8, 100, laborer.RoundRobin, laborer.WithWorkStealing(true))
+func WithWorkStealing(enable bool) Option {
+	return func(opts *Options) {
+		opts.WorkStealing = enable
+	}
+}
+
+// WithBudget 将池挂载到一个共享的并发预算上。
+//
+// 挂载了同一个 Budget 的所有池，同时执行的任务总数不超过预算容量，
+// 而每个池仍保留自己的容量、队列和其他配置。预算在提交时占用：用尽时
+// 阻塞模式的提交等待其他池的任务结束（遵守 context 和超时），非阻塞模式的
+// 提交返回 ErrPoolOverload，等待期间不占用 worker。
+//
+// 参数:
+//   - budget: 共享的并发预算，nil 表示不限制
+//
+// 返回:
+//   - Option: 配置选项函数
+//
+// 示例:
+//
+//	budget, _ := laborer.NewBudget(50) // 与数据库连接池大小一致
+//	reads, _ := laborer.NewPool(100, laborer.WithBudget(budget))
+//	writes, _ := laborer.NewPool(20, laborer.WithBudget(budget))
+func WithBudget(budget *Budget) Option {
+	return func(opts *Options) {
+		opts.Budget = budget
+	}
+}
+
+// WithSpillover 启用池饱和时的有界溢出。
+//
+// 非阻塞模式下池已满时，提交不会立即返回 ErrPoolOverload，而是在临时创建的
+// 溢出 worker 上执行，同时运行的溢出 worker 最多 limit 个，超过后才拒绝。
+// 溢出 worker 只执行一个任务，同样受 panic 恢复、任务钩子和统计的覆盖，
+// 当前数量和累计次数分别通过 Stats 的 Spilling 和 Spilled 获取。
+// 这为突发流量提供了一个可控的泄压阀，而不是硬性拒绝。
+//
+// 参数:
+//   - limit: 同时运行的溢出 worker 上限，小于等于 0 表示不启用
+//
+// 返回:
+//   - Option: 配置选项函数
+//
+// 示例:
+//
+//	pool, _ := laborer.NewPool(100, laborer.WithNonblocking(true), laborer.WithSpillover(20))
+func WithSpillover(limit int) Option {
+	return func(opts *Options) {
+		opts.SpilloverLimit = limit
+	}
+}
+
+// WithShardedLocking 启用空闲 worker 的分片锁。
+//
+// 默认情况下所有提交和 worker 归还都在池的一把锁上串行。启用后，
+// 没有阻塞等待者时归还的 worker 放入当前 P 对应的桶（共 GOMAXPROCS 个独立加锁的桶），
+// 提交从当前 P 的桶开始获取空闲 worker，高并发提交不再争用同一把锁。
+// 池已满需要等待、创建新 worker 等慢路径仍使用池的锁，阻塞等待者的队列不分片。
+// 代价是空闲 worker 不再严格按栈或队列的顺序复用。
+//
+// 参数:
+//   - enable: 是否启用分片锁
+//
+// 返回:
+//   - Option: 配置选项函数
+//
+// 示例:
+//
+//	pool, _ := laborer.NewPool(10000, laborer.WithShardedLocking(true))
+func WithShardedLocking(enable bool) Option {
+	return func(opts *Options) {
+		opts.ShardedLocking = enable
+	}
+}
+
+// WithSpinLock 使用自旋锁作为池的锁。
+//
+// 获取和归还 worker 的临界区很短，高并发提交时带指数退避的自旋锁
+// 通常比 sync.Mutex 的吞吐更高。临界区较长或 GOMAXPROCS 很小时
+// 自旋会浪费 CPU，建议通过基准测试确认收益后再启用。
+//
+// 参数:
+//   - enable: 是否使用自旋锁
+//
+// 返回:
+//   - Option: 配置选项函数
+//
+// 示例:
+//
+//	pool, _ := laborer.NewPool(1000, laborer.WithSpinLock(true))
+func WithSpinLock(enable bool) Option {
+	return func(opts *Options) {
+		opts.SpinLock = enable
+	}
+}
+
+// WithLeakCheck 启用关闭后的 worker 泄漏检查（调试用）。
+//
+// 启用后每次 Release 或 ReleaseTimeout 关闭池时，会在后台等待最多 grace，
+// 检查运行计数是否降为 0、所有 worker goroutine 是否都已退出。
+// 超时后仍有 worker 未退出时，获取它们的 goroutine 栈并调用 onLeak（未设置时写入日志）。
+// 生命周期上的缺陷通常表现为生产环境中缓慢增长的 goroutine 泄漏，
+// 这个检查可以在测试和预发环境中尽早发现它们。
+// grace 应当大于任务的最长执行时间，否则关闭时仍在执行的任务也会被上报。
+//
+// 参数:
+//   - grace: 关闭后等待 worker 退出的时间，必须为正数
+//   - onLeak: 发现泄漏时的回调，可以为 nil
+//
+// 返回:
+//   - Option: 配置选项函数
+//
+// 示例:
+//
+//	pool, _ := laborer.NewPool(10, laborer.WithLeakCheck(5*time.Second, func(r laborer.LeakReport) {
+//	    log.Printf("pool %s leaked %d workers", r.Pool, len(r.Workers))
+//	}))
+func WithLeakCheck(grace time.Duration, onLeak func(LeakReport)) Option {
+	return func(opts *Options) {
+		opts.LeakCheckGrace = grace
+		opts.OnLeak = onLeak
+	}
+}
+
+// WithQueueType 设置空闲 worker 队列使用的数据结构。
+//
+// 默认按容量自动选择：容量较小时使用栈，较大时使用循环队列。
+// 栈（Stack）优先复用最近归还的 worker，它们的栈和 CPU 缓存仍然是热的，
+// 适合短小、频繁的任务；循环队列（LoopQueue）按归还顺序轮流复用 worker，
+// 负载分散到所有 worker 上，空闲 worker 也更少因过期被回收。
+// LoopQueue 不能用于无限容量的池。
+//
+// 参数:
+//   - queueType: AutoQueue、Stack 或 LoopQueue
+//
+// 返回:
+//   - Option: 配置选项函数
+//
+// 示例:
+//
+//	pool, _ := laborer.NewPool(100, laborer.WithQueueType(laborer.LoopQueue))
+func WithQueueType(queueType QueueType) Option {
+	return func(opts *Options) {
+		opts.QueueType = queueType
+	}
+}
+
+// WithQueueThreshold 设置自动选择队列类型时改用循环队列的容量阈值。
+//
+// 仅在队列类型为 AutoQueue 时生效：容量小于 threshold 时使用栈，否则使用循环队列。
+//
+// 参数:
+//   - threshold: 容量阈值，必须为非负数，0 表示使用默认值 1000
+//
+// 返回:
+//   - Option: 配置选项函数
+//
+// 示例:
+//
+//	// 容量达到 200 即使用循环队列
+//	pool, _ := laborer.NewPool(500, laborer.WithQueueThreshold(200))
+func WithQueueThreshold(threshold int) Option {
+	return func(opts *Options) {
+		opts.QueueThreshold = threshold
+	}
+}
+
+// WithWorkerQueue 使用自定义的空闲 worker 队列。
+//
+// factory 在创建池时以池的容量（无限容量为 -1）调用一次，返回的队列代替内置的
+// 栈和循环队列，优先于 WithQueueType。队列的所有方法都在持有池的锁时调用；
+// 容量可能因 Tune 扩大，Insert 返回错误时归还的 worker 直接退出。
+// 适合尝试无锁队列、NUMA 感知的结构等，而不必 fork 整个包。
+//
+// 参数:
+//   - factory: 创建队列的函数，不能返回 nil
+//
+// 返回:
+//   - Option: 配置选项函数
+//
+// 示例:
+//
+//	pool, _ := laborer.NewPool(100, laborer.WithWorkerQueue(func(size int) laborer.WorkerQueue {
+//	    return newRandomQueue(size)
+//	}))
+func WithWorkerQueue(factory func(size int) WorkerQueue) Option {
+	return func(opts *Options) {
+		opts.WorkerQueueFactory = factory
+	}
+}
@@ -0,0 +1,155 @@
+package laborer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestCleanInterval 测试清理间隔的取值规则
+func TestCleanInterval(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		expected time.Duration
+	}{
+		{"默认值", nil, DefaultCleanIntervalTime},
+		{"过期时间较短", []Option{WithExpiryDuration(200 * time.Millisecond)}, 200 * time.Millisecond},
+		{"过期时间较长", []Option{WithExpiryDuration(10 * time.Minute)}, DefaultCleanIntervalTime},
+		{"显式设置", []Option{WithExpiryDuration(10 * time.Minute), WithCleanInterval(5 * time.Second)}, 5 * time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := NewOptions(tt.opts...).cleanInterval(); got != tt.expected {
+			t.Errorf("%s: 期望 %v，实际 %v", tt.name, tt.expected, got)
+		}
+	}
+
+	// 负数间隔应该返回错误
+	if _, err := NewPool(1, WithCleanInterval(-time.Second)); err != ErrInvalidCleanInterval {
+		t.Errorf("期望返回 ErrInvalidCleanInterval，实际返回: %v", err)
+	}
+}
+
+// TestOptionsValidate 测试创建池时拒绝无意义的选项组合
+func TestOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		size int
+		opts []Option
+	}{
+		{"nil Logger", 10, []Option{WithLogger(nil)}},
+		{"无限容量预分配", -1, []Option{WithPreAlloc(true)}},
+		{"负数 MaxBlockingTasks", 10, []Option{WithMaxBlockingTasks(-1)}},
+		{"负数看门狗时限", 10, []Option{WithWatchdog(-time.Second, nil)}},
+		{"隔离没有冷却时间", 10, []Option{WithPanicQuarantine(3, 0, nil)}},
+		{"负数泄漏检查时间", 10, []Option{WithLeakCheck(-time.Second, nil)}},
+		{"负数日志采样条数", 10, []Option{WithLogSampling(-1, time.Second)}},
+		{"日志采样没有时间窗口", 10, []Option{WithLogSampling(10, 0)}},
+	}
+
+	for _, tt := range tests {
+		if _, err := NewPool(tt.size, tt.opts...); !errors.Is(err, ErrInvalidOption) {
+			t.Errorf("%s: 期望返回 ErrInvalidOption，实际返回: %v", tt.name, err)
+		}
+		if _, err := NewPoolWithFunc(tt.size, func(interface{}) {}, tt.opts...); !errors.Is(err, ErrInvalidOption) {
+			t.Errorf("%s: 函数池期望返回 ErrInvalidOption，实际返回: %v", tt.name, err)
+		}
+	}
+
+	// 错误信息说明具体原因
+	_, err := NewPool(-1, WithPreAlloc(true))
+	if err == nil || !strings.Contains(err.Error(), "PreAlloc") {
+		t.Errorf("错误信息应该包含无效的选项，实际: %v", err)
+	}
+
+	// 有效的组合不受影响
+	pool, err := NewPool(10, WithPreAlloc(true), WithPanicQuarantine(3, time.Second, nil))
+	if err != nil {
+		t.Fatalf("有效的选项不应该返回错误: %v", err)
+	}
+	pool.Release()
+}
+
+// TestQueueType 测试按配置选择空闲 worker 队列的数据结构
+func TestQueueType(t *testing.T) {
+	tests := []struct {
+		name string
+		size int
+		opts []Option
+		loop bool
+	}{
+		{"小容量默认使用栈", 10, nil, false},
+		{"大容量默认使用循环队列", 2000, nil, true},
+		{"无限容量使用栈", -1, nil, false},
+		{"小容量指定循环队列", 10, []Option{WithQueueType(LoopQueue)}, true},
+		{"大容量指定栈", 2000, []Option{WithQueueType(Stack)}, false},
+		{"降低阈值", 200, []Option{WithQueueThreshold(100)}, true},
+		{"提高阈值", 2000, []Option{WithQueueThreshold(5000)}, false},
+	}
+
+	for _, tt := range tests {
+		pool, err := NewPool(tt.size, tt.opts...)
+		if err != nil {
+			t.Fatalf("%s: 创建池失败: %v", tt.name, err)
+		}
+		if _, loop := pool.workers.(*loopQueue); loop != tt.loop {
+			t.Errorf("%s: 期望使用循环队列 %v，实际 %T", tt.name, tt.loop, pool.workers)
+		}
+		pool.Release()
+
+		fp, err := NewPoolWithFunc(tt.size, func(interface{}) {}, tt.opts...)
+		if err != nil {
+			t.Fatalf("%s: 创建函数池失败: %v", tt.name, err)
+		}
+		if _, loop := fp.workers.(*loopQueueWithFunc); loop != tt.loop {
+			t.Errorf("%s: 函数池期望使用循环队列 %v，实际 %T", tt.name, tt.loop, fp.workers)
+		}
+		fp.Release()
+	}
+
+	// 无效的配置
+	for _, opts := range [][]Option{
+		{WithQueueType(QueueType(99))},
+		{WithQueueType(LoopQueue)},
+		{WithQueueThreshold(-1)},
+	} {
+		if _, err := NewPool(-1, opts...); !errors.Is(err, ErrInvalidOption) {
+			t.Errorf("期望返回 ErrInvalidOption，实际返回: %v", err)
+		}
+	}
+}
+
+// TestPoolOptions 测试 Options 返回实际生效的配置的副本
+func TestPoolOptions(t *testing.T) {
+	pool, err := NewPool(10,
+		WithName("introspect"),
+		WithNonblocking(true),
+		WithLatencyHistogram(time.Millisecond, time.Second))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	opts := pool.Options()
+	if opts.Name != "introspect" || !opts.Nonblocking {
+		t.Errorf("配置不一致: %+v", opts)
+	}
+	if opts.ExpiryDuration != DefaultExpiryDuration || opts.Logger == nil {
+		t.Errorf("应该包含默认值: %+v", opts)
+	}
+
+	// 修改副本不影响池
+	opts.LatencyBuckets[0] = time.Hour
+	opts.Name = "changed"
+	if again := pool.Options(); again.LatencyBuckets[0] != time.Millisecond || again.Name != "introspect" {
+		t.Errorf("修改副本影响了池的配置: %+v", again)
+	}
+
+	fp, _ := NewPoolWithFunc(2, func(interface{}) {}, WithName("func"))
+	defer fp.Release()
+	if fp.Options().Name != "func" {
+		t.Errorf("函数池配置不一致: %+v", fp.Options())
+	}
+}
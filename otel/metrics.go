@@ -0,0 +1,193 @@
+// Package otel 提供 laborer 池与 OpenTelemetry 的集成。
+//
+// Metrics 将池的状态注册为 OTel 指标：
+//   - laborer.pool.workers.running / idle、laborer.pool.tasks.waiting（gauge）
+//   - laborer.pool.tasks.submitted / completed / rejected / failed / panicked（counter）
+//   - laborer.task.duration（histogram，单位秒，带名称的任务额外带有 "task" 属性）
+//
+// 示例:
+//
+//	m, err := otel.NewMetrics(nil) // 使用全局 MeterProvider
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer m.Close()
+//
+//	pool, _ := laborer.NewPool(10,
+//	    laborer.WithName("image-resize"),
+//	    laborer.WithTaskHooks(nil, m.OnTaskComplete))
+//	m.Add("image-resize", pool)
+//
+// Tracer 为每个任务创建排队和执行 span，并以提交方的 span 为父 span:
+//
+//	tracer := otel.NewTracer(nil)
+//	tracer.Submit(ctx, "image-resize", pool, task)
+package otel
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kawaiirei0/laborer"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// instrumentationName 本包作为 OTel instrumentation scope 的名称
+const instrumentationName = "github.com/kawaiirei0/laborer/otel"
+
+// StatsSource 定义可以提供状态快照的池
+//
+// laborer.Pool 和 laborer.PoolWithFunc 都实现了此接口。
+type StatsSource interface {
+	Stats() laborer.Stats
+}
+
+// Metrics 将一组池的状态导出为 OTel 指标。
+//
+// gauge 和 counter 为异步指标，在 OTel 采集时读取池的 Stats()；
+// 任务耗时直方图通过 Wrap 包装的任务同步记录。Metrics 是线程安全的。
+type Metrics struct {
+	mu    sync.RWMutex
+	pools map[string]StatsSource
+
+	duration metric.Float64Histogram
+	reg      metric.Registration
+}
+
+// NewMetrics 创建 Metrics 并向 MeterProvider 注册指标
+// mp 为 nil 时使用全局 MeterProvider
+func NewMetrics(mp metric.MeterProvider) (*Metrics, error) {
+	if mp == nil {
+		mp = otel.GetMeterProvider()
+	}
+	meter := mp.Meter(instrumentationName)
+
+	m := &Metrics{pools: make(map[string]StatsSource)}
+
+	var err error
+	m.duration, err = meter.Float64Histogram("laborer.task.duration",
+		metric.WithDescription("Time tasks spent executing."),
+		metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+
+	running, err := meter.Int64ObservableGauge("laborer.pool.workers.running",
+		metric.WithDescription("Number of running workers."))
+	if err != nil {
+		return nil, err
+	}
+	idle, err := meter.Int64ObservableGauge("laborer.pool.workers.idle",
+		metric.WithDescription("Number of idle workers."))
+	if err != nil {
+		return nil, err
+	}
+	waiting, err := meter.Int64ObservableGauge("laborer.pool.tasks.waiting",
+		metric.WithDescription("Number of tasks waiting for a worker."))
+	if err != nil {
+		return nil, err
+	}
+	submitted, err := meter.Int64ObservableCounter("laborer.pool.tasks.submitted",
+		metric.WithDescription("Total number of accepted task submissions."))
+	if err != nil {
+		return nil, err
+	}
+	completed, err := meter.Int64ObservableCounter("laborer.pool.tasks.completed",
+		metric.WithDescription("Total number of tasks that completed without panicking."))
+	if err != nil {
+		return nil, err
+	}
+	rejected, err := meter.Int64ObservableCounter("laborer.pool.tasks.rejected",
+		metric.WithDescription("Total number of submissions rejected because the pool was overloaded."))
+	if err != nil {
+		return nil, err
+	}
+	failed, err := meter.Int64ObservableCounter("laborer.pool.tasks.failed",
+		metric.WithDescription("Total number of tasks that returned an error."))
+	if err != nil {
+		return nil, err
+	}
+	panicked, err := meter.Int64ObservableCounter("laborer.pool.tasks.panicked",
+		metric.WithDescription("Total number of tasks that panicked."))
+	if err != nil {
+		return nil, err
+	}
+
+	m.reg, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		m.mu.RLock()
+		defer m.mu.RUnlock()
+
+		for name, pool := range m.pools {
+			s := pool.Stats()
+			attrs := metric.WithAttributes(attribute.String("pool", name))
+
+			o.ObserveInt64(running, int64(s.Running), attrs)
+			o.ObserveInt64(idle, int64(s.Idle), attrs)
+			o.ObserveInt64(waiting, int64(s.Waiting), attrs)
+			o.ObserveInt64(submitted, s.Submitted, attrs)
+			o.ObserveInt64(completed, s.Completed, attrs)
+			o.ObserveInt64(rejected, s.Rejected, attrs)
+			o.ObserveInt64(failed, s.Failed, attrs)
+			o.ObserveInt64(panicked, s.Panicked, attrs)
+		}
+		return nil
+	}, running, idle, waiting, submitted, completed, rejected, failed, panicked)
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Add 添加一个需要导出指标的池，name 作为 "pool" 属性的值
+// 重复添加同名池会覆盖之前的池
+func (m *Metrics) Add(name string, pool StatsSource) {
+	m.mu.Lock()
+	m.pools[name] = pool
+	m.mu.Unlock()
+}
+
+// Remove 移除指定名称的池
+func (m *Metrics) Remove(name string) {
+	m.mu.Lock()
+	delete(m.pools, name)
+	m.mu.Unlock()
+}
+
+// Wrap 包装任务，在任务执行结束后记录耗时到 laborer.task.duration
+// 任务发生 panic 时同样会记录耗时，panic 会继续向上传播由池处理
+func (m *Metrics) Wrap(pool string, task func()) func() {
+	attrs := metric.WithAttributes(attribute.String("pool", pool))
+	return func() {
+		start := time.Now()
+		defer func() {
+			m.duration.Record(context.Background(), time.Since(start).Seconds(), attrs)
+		}()
+		task()
+	}
+}
+
+// OnTaskComplete 记录任务耗时到 laborer.task.duration，可直接作为池的任务钩子使用
+// "pool" 属性取自 TaskInfo.Pool，即池通过 WithName 设置的名称；
+// 通过 SubmitNamed 提交的任务还带有取自 TaskInfo.Name 的 "task" 属性
+//
+// 示例:
+//
+//	pool, _ := laborer.NewPool(10,
+//	    laborer.WithName("image-resize"),
+//	    laborer.WithTaskHooks(nil, m.OnTaskComplete))
+func (m *Metrics) OnTaskComplete(info laborer.TaskInfo) {
+	attrs := []attribute.KeyValue{attribute.String("pool", info.Pool)}
+	if info.Name != "" {
+		attrs = append(attrs, attribute.String("task", info.Name))
+	}
+	m.duration.Record(context.Background(), info.Duration.Seconds(), metric.WithAttributes(attrs...))
+}
+
+// Close 注销异步指标的回调
+func (m *Metrics) Close() error {
+	return m.reg.Unregister()
+}
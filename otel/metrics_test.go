@@ -0,0 +1,66 @@
+package otel
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kawaiirei0/laborer"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// TestMetrics 测试池指标的采集
+func TestMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	m, err := NewMetrics(mp)
+	if err != nil {
+		t.Fatalf("创建 Metrics 失败: %v", err)
+	}
+	defer m.Close()
+
+	pool, err := laborer.NewPool(2, laborer.WithName("test"), laborer.WithTaskHooks(nil, m.OnTaskComplete))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+	m.Add("test", pool)
+
+	done := make(chan struct{})
+	if err := pool.SubmitNamed("resize", func() { close(done) }); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	<-done
+
+	// 等待 onComplete 钩子执行完毕
+	time.Sleep(20 * time.Millisecond)
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("采集指标失败: %v", err)
+	}
+
+	found := make(map[string]bool)
+	for _, sm := range rm.ScopeMetrics {
+		for _, md := range sm.Metrics {
+			found[md.Name] = true
+
+			// 带名称的任务的耗时带有 "task" 属性
+			if h, ok := md.Data.(metricdata.Histogram[float64]); ok && md.Name == "laborer.task.duration" {
+				for _, dp := range h.DataPoints {
+					if v, ok := dp.Attributes.Value("task"); !ok || v.AsString() != "resize" {
+						t.Errorf("期望耗时带有 task=resize 属性，实际 %v", dp.Attributes)
+					}
+				}
+			}
+		}
+	}
+
+	for _, name := range []string{"laborer.pool.workers.running", "laborer.pool.tasks.submitted", "laborer.task.duration"} {
+		if !found[name] {
+			t.Errorf("缺少指标 %s", name)
+		}
+	}
+}
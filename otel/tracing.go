@@ -0,0 +1,70 @@
+package otel
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Submitter 定义可以提交无返回值任务的池
+//
+// laborer.Pool 实现了此接口。
+type Submitter interface {
+	Submit(task func()) error
+}
+
+// Tracer 为池中的任务创建 OTel span。
+//
+// 每个任务会生成两个 span，均以提交方 ctx 中的 span 为父 span：
+//   - laborer.queue_wait: 从提交到 worker 开始执行的等待时间
+//   - laborer.execute: 任务的执行时间，任务 panic 时标记为错误
+//
+// 这样慢任务在分布式追踪中不再是一段无法解释的空白。
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+// NewTracer 创建 Tracer
+// tp 为 nil 时使用全局 TracerProvider
+func NewTracer(tp trace.TracerProvider) *Tracer {
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	return &Tracer{tracer: tp.Tracer(instrumentationName)}
+}
+
+// Wrap 包装任务以记录排队和执行 span
+// 必须在提交时调用，调用时刻即视为任务的提交时间
+func (t *Tracer) Wrap(ctx context.Context, pool string, task func()) func() {
+	submitted := time.Now()
+	attrs := trace.WithAttributes(attribute.String("laborer.pool", pool))
+
+	return func() {
+		start := time.Now()
+		_, wait := t.tracer.Start(ctx, "laborer.queue_wait", attrs, trace.WithTimestamp(submitted))
+		wait.End(trace.WithTimestamp(start))
+
+		_, span := t.tracer.Start(ctx, "laborer.execute", attrs, trace.WithTimestamp(start))
+		defer func() {
+			if r := recover(); r != nil {
+				span.SetStatus(codes.Error, fmt.Sprint(r))
+				span.End()
+				panic(r)
+			}
+			span.End()
+		}()
+
+		task()
+	}
+}
+
+// Submit 包装任务并提交到池中
+// 提交失败时不会产生任何 span
+func (t *Tracer) Submit(ctx context.Context, pool string, p Submitter, task func()) error {
+	return p.Submit(t.Wrap(ctx, pool, task))
+}
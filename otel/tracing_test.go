@@ -0,0 +1,44 @@
+package otel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kawaiirei0/laborer"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// TestTracer 测试任务 span 的父子关系
+func TestTracer(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	pool, err := laborer.NewPool(1)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+	tracer := NewTracer(tp)
+
+	done := make(chan struct{})
+	if err := tracer.Submit(ctx, "test", pool, func() {}); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	// 使用第二个任务等待第一个任务的 span 结束（容量为 1，任务串行执行）
+	_ = pool.Submit(func() { close(done) })
+	<-done
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("期望 3 个 span，实际 %d", len(spans))
+	}
+	for _, s := range spans[:2] {
+		if s.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("span %s 的父 span 不正确", s.Name())
+		}
+	}
+}
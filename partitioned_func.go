@@ -0,0 +1,60 @@
+package laborer
+
+// PartitionedPoolWithFunc 按键分区的函数池，保证同一个键的参数按提交顺序处理
+//
+// 池由 partitions 个容量为 1 的子池组成，keyFunc 从参数中提取键，
+// 同一个键总是被路由到同一个分区并由该分区唯一的 worker 串行处理，
+// 不同键之间仍然并行。适用于 Kafka 等按键有序的消息流。
+//
+// 顺序保证要求同一个键的参数由同一个 goroutine 依次提交，且池处于阻塞模式：
+// 非阻塞模式下分区忙碌时 Invoke 返回 ErrPoolOverload。
+//
+// 示例:
+//
+//	pp, _ := laborer.NewPartitionedPoolWithFunc(16, func(args interface{}) string {
+//	    return args.(*Message).Key
+//	}, handleMessage)
+//	defer pp.Release()
+//
+//	for msg := range messages {
+//	    pp.Invoke(msg)
+//	}
+type PartitionedPoolWithFunc struct {
+	funcShards
+	keyFunc func(args interface{}) string
+}
+
+// NewPartitionedPoolWithFunc 创建一个新的按键分区的函数池
+// partitions: 分区数量，即最大并行度，必须为正数
+// keyFunc: 从参数中提取分区键的函数
+// pf: 所有 worker 执行的固定函数
+// options: 配置选项，应用到每个分区
+func NewPartitionedPoolWithFunc(partitions int, keyFunc func(args interface{}) string, pf func(interface{}), options ...Option) (*PartitionedPoolWithFunc, error) {
+	if partitions <= 0 {
+		return nil, ErrInvalidPoolSize
+	}
+	if keyFunc == nil {
+		return nil, ErrInvalidPoolFunc
+	}
+
+	shards, err := newFuncShards(partitions, 1, pf, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PartitionedPoolWithFunc{funcShards: shards, keyFunc: keyFunc}, nil
+}
+
+// Invoke 将参数提交到其键所在的分区执行
+func (pp *PartitionedPoolWithFunc) Invoke(args interface{}) error {
+	if pp.IsClosed() {
+		return ErrPoolClosed
+	}
+
+	return pp.funcShards[pp.Partition(args)].Invoke(args)
+}
+
+// Partition 返回参数所在的分区下标
+func (pp *PartitionedPoolWithFunc) Partition(args interface{}) int {
+	return hashIndex(pp.keyFunc(args), len(pp.funcShards))
+}
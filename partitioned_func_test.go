@@ -0,0 +1,67 @@
+package laborer
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// TestPartitionedPoolWithFunc 测试同一个键的参数按顺序处理
+func TestPartitionedPoolWithFunc(t *testing.T) {
+	type msg struct {
+		key string
+		seq int
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	seen := make(map[string][]int)
+
+	pp, err := NewPartitionedPoolWithFunc(4, func(args interface{}) string {
+		return args.(msg).key
+	}, func(args interface{}) {
+		m := args.(msg)
+		mu.Lock()
+		seen[m.key] = append(seen[m.key], m.seq)
+		mu.Unlock()
+		wg.Done()
+	})
+	if err != nil {
+		t.Fatalf("创建分区函数池失败: %v", err)
+	}
+	defer pp.Release()
+
+	keys := []string{"a", "b", "c", "d", "e"}
+	for seq := 0; seq < 50; seq++ {
+		for _, key := range keys {
+			wg.Add(1)
+			if err := pp.Invoke(msg{key, seq}); err != nil {
+				t.Fatalf("提交任务失败: %v", err)
+			}
+		}
+	}
+	wg.Wait()
+
+	for _, key := range keys {
+		got := seen[key]
+		if len(got) != 50 {
+			t.Fatalf("键 %s 期望处理 50 次，实际 %d 次", key, len(got))
+		}
+		for i, seq := range got {
+			if seq != i {
+				t.Fatalf("键 %s 的处理顺序不正确: %v", key, got)
+			}
+		}
+	}
+
+	// 同一个键总是落在同一个分区
+	for i := 0; i < 10; i++ {
+		k := fmt.Sprintf("key-%d", i)
+		if pp.Partition(msg{k, 0}) != pp.Partition(msg{k, 1}) {
+			t.Errorf("键 %s 被路由到了不同分区", k)
+		}
+	}
+	if pp.Cap() != 4 {
+		t.Errorf("Cap() 期望 4，实际 %d", pp.Cap())
+	}
+}
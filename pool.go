@@ -6,14 +6,17 @@
 // 3. 锁优化：最小化锁持有时间，在锁外执行耗时操作（如时间戳更新）
 // 4. Worker 对象复用：使用 sync.Pool 复用 worker 对象，减少 GC 压力
 // 5. 快速路径优化：在 getWorker 中使用无锁快速路径，避免不必要的锁获取
-// 6. 条件唤醒：只在有等待 goroutine 时才调用 Signal，减少不必要的系统调用
+// 6. 条件唤醒：只在有等待 goroutine 时才唤醒，等待者按 FIFO 顺序在各自的 channel 上等待
 // 7. 内存布局优化：worker 队列使用缓存友好的数据结构（栈/循环队列）
 // 8. 批量处理：在 refresh 中批量处理过期 worker，减少锁获取次数
-// 9. 切片复用：在 refresh 操作中复用 expiry 切片，减少内存分配
+// 9. 二分查找：refresh 二分查找过期边界，只为返回的过期 worker 分配一次内存
 // 10. Channel 缓冲：使用带缓冲的 channel 减少 goroutine 阻塞
 package laborer
 
 import (
+	"context"
+	"fmt"
+	"math"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -26,7 +29,10 @@ const (
 	// OPENED 表示池正在运行
 	OPENED = 0
 
-	// queueSizeThreshold 队列大小阈值，小于此值使用栈，否则使用循环队列
+	// CLOSING 表示池正在关闭：已经拒绝新的任务，但清理还没有完成
+	CLOSING = 2
+
+	// queueSizeThreshold 默认的队列大小阈值，AutoQueue 下小于此值使用栈，否则使用循环队列
 	queueSizeThreshold = 1000
 
 	// workerChanCap worker channel 的缓冲容量
@@ -43,23 +49,49 @@ type Pool struct {
 	// running 当前运行的 worker 数量
 	running int32
 
-	// state 池的状态：OPENED 或 CLOSED
+	// state 池的状态：OPENED、CLOSING 或 CLOSED
 	state int32
 
 	// lock 保护 workers 队列的锁
 	lock sync.Locker
 
-	// cond 条件变量，用于阻塞模式下的等待
-	cond *sync.Cond
+	// waiters 阻塞模式下等待 worker 的提交者，按 FIFO 顺序唤醒
+	waiters waitQueue
 
 	// workers worker 队列，存储空闲的 worker
 	workers workerQueue
 
+	// free workers 队列中的 worker 数量，在插入和取出时更新，
+	// 使 Free 不必加锁读取队列长度
+	free int32
+
+	// idle 分片的空闲 worker 缓存，仅在启用 WithShardedLocking 时使用
+	idle idleShards[*goWorker]
+
 	// options 配置选项
 	options *Options
 
+	// paused 池是否已暂停，暂停时不向 worker 分配新的任务
+	paused int32
+
+	// draining 池是否正在排空，排空时拒绝新的提交
+	draining int32
+
+	// inflight 已接受但尚未执行完毕的任务数量，包括阻塞等待 worker 的提交
+	inflight atomic.Int64
+
 	// waiting 等待执行的任务数量
-	waiting int32
+	// 使用 int64，排队的任务数量很大时也不会溢出
+	waiting atomic.Int64
+
+	// thieves 启用工作窃取的分片池中所有子池共享的窃取等待队列，其他情况下为 nil
+	thieves *thieves
+
+	// quiet inflight 降为 0 或运行计数变化的通知，Wait、Drain、ReleaseTimeout 和 WaitRunning 在其上等待
+	quiet zeroSignal
+
+	// thieving 以本池为选中子池、等待任意子池空出 worker 的提交者数量
+	thieving atomic.Int32
 
 	// stopCleaning 用于停止清理 goroutine 的 channel
 	stopCleaning chan struct{}
@@ -69,6 +101,43 @@ type Pool struct {
 
 	// workerPool 用于复用 worker 对象，减少 GC 压力
 	workerPool sync.Pool
+
+	// metrics 累计任务计数器
+	metrics poolMetrics
+
+	// named 由 SubmitNamed 提交的任务按名称的计数器
+	named namedTasks
+
+	// workerSeq 用于分配 worker ID 的递增序号
+	workerSeq int64
+
+	// live 所有存活的 worker，用于 DumpStacks 和看门狗
+	live workerSet
+
+	// watchdog 卡住 worker 的看门狗，未启用时为 nil
+	watchdog *watchdog
+
+	// panics 最近的 panic 记录
+	panics panicLog
+
+	// trackTasks 是否需要记录任务的时间元数据
+	trackTasks bool
+
+	// trackWorkers 是否需要记录 worker 的 goroutine 和忙碌状态
+	trackWorkers bool
+
+	// tracer 逐个任务的追踪日志，由 SetTrace 开关
+	tracer tracer
+
+	// events Events 返回的事件 channel
+	events eventHub
+
+	// spilling 当前正在运行的溢出 worker 数量，不计入 running
+	spilling int32
+
+	// weightedLock 串行化 SubmitWeighted 和 SubmitMany 获取多个 worker 的过程，
+	// 避免多个多槽任务各自占住一部分容量而互相等待
+	weightedLock sync.Mutex
 }
 
 // PoolInterface 定义池的接口
@@ -88,6 +157,9 @@ type PoolInterface interface {
 	// Reboot 重启已关闭的池
 	Reboot()
 
+	// PurgeNow 立即回收所有空闲的 worker
+	PurgeNow() int
+
 	// Running 返回正在运行的 worker 数量
 	Running() int
 
@@ -102,65 +174,96 @@ type PoolInterface interface {
 
 	// IsClosed 返回池是否已关闭
 	IsClosed() bool
+
+	// Stats 返回池的运行状态快照
+	Stats() Stats
+}
+
+// maxPoolSize 池容量的上限，容量以 int32 保存，更大的值会被截断
+const maxPoolSize = math.MaxInt32
+
+// validPoolSize 返回 size 是否是有效的池容量：不超过 maxPoolSize 的正整数或 -1（无限容量）
+func validPoolSize(size int) bool {
+	return (size > 0 && size <= maxPoolSize) || size == -1
 }
 
 // NewPool 创建一个新的 goroutine 池
-// size: 池的容量，-1 表示无限容量
+// size: 池的容量，-1 表示无限容量，0 表示按 CPU 数量换算（见 WithSizeMultiplier）
 // options: 配置选项
 func NewPool(size int, options ...Option) (*Pool, error) {
+	// 创建配置选项
+	opts := NewOptions(options...)
+
 	// 验证容量参数
-	if size == 0 {
+	size = opts.poolSize(size)
+	if !validPoolSize(size) {
 		return nil, ErrInvalidPoolSize
 	}
 
-	// 创建配置选项
-	opts := NewOptions(options...)
+	// 验证组合后的配置选项
+	if err := opts.validate(size); err != nil {
+		return nil, err
+	}
 
-	// 验证过期时间
-	if opts.ExpiryDuration < 0 {
-		return nil, ErrInvalidPoolExpiry
+	// 任务是闭包，无法写入持久化的任务队列，TaskQueue 仅对 PoolWithFunc 生效
+	if opts.TaskQueue != nil {
+		return nil, invalidOption("TaskQueue is only supported by PoolWithFunc")
 	}
 
 	// 创建池实例
 	pool := &Pool{
-		capacity:     int32(size),
-		options:      opts,
-		stopCleaning: make(chan struct{}),
-		cleaningDone: make(chan struct{}),
+		capacity: int32(size),
+		options:  opts,
 	}
 
+	// 启用延迟直方图统计
+	pool.metrics.enableLatency(opts.LatencyBuckets)
+	pool.trackTasks = trackTasks(opts)
+	pool.trackWorkers = trackWorkers(opts)
+	pool.tracer.enabled.Store(opts.Trace)
+
 	// 初始化锁和条件变量
-	pool.lock = new(sync.Mutex)
-	pool.cond = sync.NewCond(pool.lock)
+	if opts.SpinLock {
+		pool.lock = newSpinLock()
+	} else {
+		pool.lock = new(sync.Mutex)
+	}
 
 	// 初始化 worker 对象池，用于复用 worker 对象
 	// 优化：使用带缓冲的 channel 减少阻塞
 	pool.workerPool.New = func() interface{} {
 		return &goWorker{
 			pool: pool,
-			task: make(chan func(), workerChanCap),
+			task: make(chan taskItem, workerChanCap),
 		}
 	}
 
-	// 根据容量选择合适的 worker 队列实现
-	// 小容量使用栈（LIFO），大容量使用循环队列（FIFO）
-	if size == -1 {
-		// 无限容量，使用栈
-		pool.workers = newWorkerStack(0)
-	} else if size < queueSizeThreshold {
-		// 小容量，使用栈
-		if opts.PreAlloc {
-			pool.workers = newWorkerStack(size)
-		} else {
-			pool.workers = newWorkerStack(0)
+	// 根据配置和容量选择 worker 队列实现
+	// 默认小容量使用栈（LIFO），大容量使用循环队列（FIFO）
+	if opts.WorkerQueueFactory != nil {
+		q := opts.WorkerQueueFactory(size)
+		if q == nil {
+			return nil, invalidOption("worker queue factory returned nil")
 		}
-	} else {
-		// 大容量，使用循环队列
+		pool.workers = customQueue[*goWorker]{q: q, opts: opts}
+	} else if opts.queueType(size) == LoopQueue {
 		pool.workers = newWorkerLoopQueue(size)
+	} else if opts.PreAlloc {
+		pool.workers = newWorkerStack(size)
+	} else {
+		pool.workers = newWorkerStack(0)
+	}
+	if opts.ShardedLocking {
+		pool.idle.init()
 	}
 
 	// 启动定期清理过期 worker 的 goroutine
-	go pool.cleanExpiredWorkers()
+	pool.startCleaning()
+	pool.watchdog = startWatchdog(opts, &pool.live)
+
+	// 加入全局注册表
+	register(pool)
+	opts.logEvent(LevelInfo, "pool_created", Field{"cap", size}, Field{"nonblocking", opts.Nonblocking})
 
 	return pool, nil
 }
@@ -168,42 +271,622 @@ func NewPool(size int, options ...Option) (*Pool, error) {
 // Submit 提交一个任务到池中执行
 func (p *Pool) Submit(task func()) error {
 	// 检查池是否已关闭
-	if p.IsClosed() {
+	if !p.isOpen() {
 		return ErrPoolClosed
 	}
 
-	// 获取一个 worker 并分配任务
-	if w := p.getWorker(); w != nil {
-		w.task <- task
+	t := p.newTask(taskItem{run: task})
+
+	return p.dispatch(t)
+}
+
+// SubmitContext 提交一个任务到池中执行，阻塞等待空闲 worker 时可以通过 ctx 取消
+// ctx 只在需要等待时生效：等待期间被取消时返回 ctx.Err()，任务不会被执行；
+// 能够立即取得 worker 时直接提交。任务开始执行后 ctx 不再影响任务。非阻塞模式下与 Submit 相同。
+func (p *Pool) SubmitContext(ctx context.Context, task func()) error {
+	// 检查池是否已关闭
+	if !p.isOpen() {
+		return ErrPoolClosed
+	}
+
+	return p.dispatchContext(ctx, time.Time{}, p.newTask(taskItem{run: task}))
+}
+
+// SubmitWithTimeout 提交一个任务到池中执行，阻塞等待空闲 worker 的时间不超过 timeout
+// 阻塞模式下池满时，Submit 会一直等待；SubmitWithTimeout 在 timeout 后放弃并返回
+// ErrTimeout，任务不会被执行，便于生产者实现背压而不必将整个池切换为非阻塞模式。
+// 非阻塞模式下行为与 Submit 相同。
+func (p *Pool) SubmitWithTimeout(task func(), timeout time.Duration) error {
+	// 检查池是否已关闭
+	if !p.isOpen() {
+		return ErrPoolClosed
+	}
+
+	return p.dispatchContext(context.Background(), time.Now().Add(timeout), p.newTask(taskItem{run: task}))
+}
+
+// SubmitTask 提交一个实现了 Task 接口的任务对象到池中执行
+// 与 Submit(task.Run) 相同，task 为 nil 时返回 ErrNilTask。
+func (p *Pool) SubmitTask(task Task) error {
+	if task == nil {
+		return ErrNilTask
+	}
+	return p.Submit(task.Run)
+}
+
+// SubmitTaskWithResult 提交一个实现了 TaskWithResult 接口的任务对象到池中执行
+// 与 SubmitWithResult(task.Run) 相同，task 为 nil 时返回 ErrNilTask。
+func (p *Pool) SubmitTaskWithResult(task TaskWithResult) (Future, error) {
+	if task == nil {
+		return nil, ErrNilTask
+	}
+	return p.SubmitWithResult(task.Run)
+}
+
+// SubmitNamed 提交一个带名称的任务到池中执行
+// 名称用于标识占用 worker 的是哪一类工作（例如 "resize"、"send-mail"），会出现在
+// 任务钩子和 PanicHandlerV2 的 TaskInfo.Name、DumpStacks 和看门狗上报的 WorkerStack.Task、
+// RecentPanics 以及 panic 和卡住 worker 的日志中；开启 WithPprofLabels 时，
+// 任务执行期间 worker goroutine 还会带上 PprofLabelTask 标签。
+// 每个名称的执行中、完成和 panic 计数通过 NamedStats 获取，Prometheus Collector 以 "task" 标签导出。
+// 名称应当取自有限的集合，不要包含请求 ID 等高基数的值。其余行为与 Submit 相同。
+// 只有 Pool 支持任务名称：PoolWithFunc 的所有调用执行同一个函数，池的名称已经说明了工作的类型。
+func (p *Pool) SubmitNamed(name string, task func()) error {
+	// 检查池是否已关闭
+	if !p.isOpen() {
+		return ErrPoolClosed
+	}
+
+	return p.dispatch(p.newTask(taskItem{run: task, name: name}))
+}
+
+// SubmitWithState 提交一个需要使用 per-worker 资源的任务到池中执行
+// 任务的参数为执行它的 worker 通过 WorkerInit 创建的值，
+// 未设置 WorkerInit 时为 nil。
+func (p *Pool) SubmitWithState(task func(state interface{})) error {
+	// 检查池是否已关闭
+	if !p.isOpen() {
+		return ErrPoolClosed
+	}
+
+	t := p.newTask(taskItem{runState: task})
+
+	return p.dispatch(t)
+}
+
+// dispatch 获取一个 worker 并将任务投递给它
+func (p *Pool) dispatch(t taskItem) error {
+	return p.dispatchContext(context.Background(), time.Time{}, t)
+}
+
+// dispatchContext 记录一个新提交的任务，获取一个 worker 并将任务投递给它，
+// 阻塞等待 worker 时可以通过 ctx 取消，deadline 不为零值时最多等待到 deadline
+func (p *Pool) dispatchContext(ctx context.Context, deadline time.Time, t taskItem) error {
+	if t.isNil() {
+		traceRejected(p.options, t.id, ErrNilTask)
+		return ErrNilTask
+	}
+	if err := p.admit(1); err != nil {
+		traceRejected(p.options, t.id, err)
+		return err
+	}
+	if err := p.acquireBudget(ctx, deadline, 1); err != nil {
+		p.settle(1)
+		traceRejected(p.options, t.id, err)
+		return err
+	}
+	if err := p.deliver(ctx, deadline, t); err != nil {
+		p.releaseBudget(1)
+		p.settle(1)
+		return err
+	}
+	return nil
+}
+
+// settle 扣减 n 个已结束或未被接受的任务，inflight 降为 0 时唤醒 Wait 和 Drain
+func (p *Pool) settle(n int64) {
+	if p.inflight.Add(-n) <= 0 {
+		p.quiet.notify()
+	}
+}
+
+// admit 记录 n 个新提交的任务，池正在排空时拒绝并返回 ErrDraining
+// 先增加计数再检查排空标记，Drain 要么看到这些任务，要么使它们被拒绝。
+func (p *Pool) admit(n int) error {
+	p.inflight.Add(int64(n))
+	if atomic.LoadInt32(&p.draining) == 1 {
+		p.settle(int64(n))
+		return ErrDraining
+	}
+	return nil
+}
+
+// acquireBudget 为 n 个已计入 inflight 的任务占用共享预算，未设置 Budget 时直接返回
+// 预算用尽时阻塞模式等待（遵守 ctx 和 deadline），非阻塞模式记录 n 次拒绝并返回 ErrPoolOverload。
+// 占用的名额由执行任务的 worker 在任务结束后归还。
+func (p *Pool) acquireBudget(ctx context.Context, deadline time.Time, n int) error {
+	b := p.options.Budget
+	if b == nil {
 		return nil
 	}
+	err := b.acquire(ctx, deadline, p.options.Nonblocking, n)
+	if err == ErrPoolOverload {
+		p.rejectMany(n)
+	}
+	return err
+}
+
+// releaseBudget 归还 n 个未能投递的任务占用的预算名额
+func (p *Pool) releaseBudget(n int) {
+	if b := p.options.Budget; b != nil {
+		b.release(n)
+	}
+}
 
-	return ErrPoolOverload
+// deliver 获取一个 worker 并将已计入 inflight 的任务投递给它
+func (p *Pool) deliver(ctx context.Context, deadline time.Time, t taskItem) error {
+	w, err := p.acquireWorker(ctx, deadline, 1)
+	if err != nil {
+		traceRejected(p.options, t.id, err)
+		return err
+	}
+
+	if w != nil {
+		p.metrics.submitted.Add(1)
+		w.task <- t
+		return nil
+	}
+
+	// 池已饱和，尝试在溢出 worker 上执行
+	if ok, err := p.spill(t); err != nil || ok {
+		traceRejected(p.options, t.id, err)
+		return err
+	}
+
+	err = p.overload(1)
+	traceRejected(p.options, t.id, err)
+	return err
+}
+
+// spill 在一个临时的溢出 worker 上执行任务
+// 溢出 worker 只执行这一个任务，同样受 panic 恢复保护，数量不超过 SpilloverLimit；
+// 达到上限或池已关闭时返回 false。
+func (p *Pool) spill(t taskItem) (bool, error) {
+	if !p.isOpen() || p.IsPaused() || !acquireSpill(&p.spilling, p.options.SpilloverLimit) {
+		return false, nil
+	}
+
+	w := p.newWorker()
+	w.spill = true
+	if err := w.init(); err != nil {
+		atomic.AddInt32(&p.spilling, -1)
+		return false, err
+	}
+	w.run()
+
+	p.metrics.submitted.Add(1)
+	p.metrics.spilled.Add(1)
+	w.task <- t
+	return true, nil
+}
+
+// acquireSpill 在 spilling 未达到 limit 时占用一个溢出名额
+func acquireSpill(spilling *int32, limit int) bool {
+	for {
+		n := atomic.LoadInt32(spilling)
+		if int(n) >= limit {
+			return false
+		}
+		if atomic.CompareAndSwapInt32(spilling, n, n+1) {
+			return true
+		}
+	}
+}
+
+// reserveWorkers 在容量允许的范围内占用至多 n 个运行名额，返回实际占用的数量
+// 检查容量和增加运行计数是同一次 CAS，并发的提交者不会同时通过检查而使
+// running 超过 capacity。capacity 为 -1 时总是占用 n 个。
+func reserveWorkers(running, capacity *int32, n int) int {
+	for {
+		r := atomic.LoadInt32(running)
+		k := n
+		if c := atomic.LoadInt32(capacity); c != -1 {
+			if free := int(c - r); free < k {
+				k = free
+			}
+		}
+		if k <= 0 {
+			return 0
+		}
+		if atomic.CompareAndSwapInt32(running, r, r+int32(k)) {
+			return k
+		}
+	}
+}
+
+// retireWorker 运行的 worker 超过容量时扣减一个运行名额，返回 true 表示调用方的 worker 应该退出
+// 检查容量和减少运行计数是同一次 CAS，缩容后并发归还的 worker 不会同时通过检查而使
+// running 低于 capacity。
+func retireWorker(running, capacity *int32) bool {
+	for {
+		r := atomic.LoadInt32(running)
+		if c := atomic.LoadInt32(capacity); c == -1 || r <= c {
+			return false
+		}
+		if atomic.CompareAndSwapInt32(running, r, r-1) {
+			return true
+		}
+	}
 }
 
 // SubmitWithResult 提交一个带返回值的任务到池中执行
 func (p *Pool) SubmitWithResult(task func() (interface{}, error)) (Future, error) {
 	// 检查池是否已关闭
-	if p.IsClosed() {
+	if !p.isOpen() {
 		return nil, ErrPoolClosed
 	}
 
-	// 创建 future 对象
+	// 创建 future 对象，worker 执行任务后将结果设置到 future 中
 	f := newFuture()
+	t := p.newTask(taskItem{call: task, future: f})
+
+	// 获取一个 worker 并分配任务
+	if err := p.dispatch(t); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// SubmitToChan 提交一个带返回值的任务，任务完成后将结果发送到 out
+// 适用于通过 select 统一处理多个任务结果的事件循环式消费者。
+// 结果由执行任务的 worker 发送，out 未被及时读取时会占用该 worker，
+// 因此 out 应当有足够的缓冲或被持续消费。
+func (p *Pool) SubmitToChan(task func() (interface{}, error), out chan<- Result) error {
+	return p.submitCall(task, func(result interface{}, err error) {
+		out <- Result{Value: result, Err: err}
+	})
+}
+
+// SubmitWait 提交一个任务并阻塞等待它执行完毕
+// Submit 在任务投递给 worker 后即返回，SubmitWait 则等到任务实际执行完成，
+// 适用于测试以及需要保证完成顺序、同时仍由池负责 panic 恢复和并发限制的调用方。
+// 任务 panic 时返回 *PanicError（池的 PanicHandler 同样会被调用）；
+// 提交失败时返回相应的错误，任务不会被执行。
+// 不要在池的任务中对同一个池调用 SubmitWait，池满时会互相等待而死锁。
+func (p *Pool) SubmitWait(task func()) error {
+	return p.submitWait(task, time.Time{})
+}
+
+// SubmitWaitWithTimeout 与 SubmitWait 相同，但等待空闲 worker 和等待任务执行完毕的总时间不超过 timeout
+// 超时时返回 ErrTimeout。已经投递给 worker 的任务不会被取消，超时后仍会执行完毕。
+func (p *Pool) SubmitWaitWithTimeout(task func(), timeout time.Duration) error {
+	return p.submitWait(task, time.Now().Add(timeout))
+}
 
-	// 包装任务，将结果设置到 future 中
-	wrappedTask := func() {
-		result, err := task()
-		f.setResult(result, err)
+// submitWait 提交任务并等待它执行完毕，deadline 为零值时不超时
+func (p *Pool) submitWait(task func(), deadline time.Time) error {
+	// 检查池是否已关闭
+	if !p.isOpen() {
+		return ErrPoolClosed
+	}
+	if task == nil {
+		return ErrNilTask
 	}
 
-	// 获取一个 worker 并分配任务
-	if w := p.getWorker(); w != nil {
-		w.task <- wrappedTask
-		return f, nil
+	f := newFuture()
+	t := p.newTask(taskItem{call: func() (interface{}, error) {
+		task()
+		return nil, nil
+	}, future: f})
+	if err := p.dispatchContext(context.Background(), deadline, t); err != nil {
+		return err
 	}
 
-	return nil, ErrPoolOverload
+	if deadline.IsZero() {
+		_, err := f.Get()
+		return err
+	}
+	_, err := f.GetWithTimeout(time.Until(deadline))
+	return err
+}
+
+// submitCall 提交一个带返回值的任务，任务完成（包括 panic）后由 worker 调用 done
+func (p *Pool) submitCall(task func() (interface{}, error), done func(result interface{}, err error)) error {
+	// 检查池是否已关闭
+	if !p.isOpen() {
+		return ErrPoolClosed
+	}
+
+	f := newFuture()
+	f.callbacks = append(f.callbacks, func() {
+		done(f.result, f.err)
+	})
+	t := p.newTask(taskItem{call: task, future: f})
+
+	return p.dispatch(t)
+}
+
+// SubmitWeighted 提交一个占用 weight 个容量槽位的任务
+// 适合在同一个池中混合执行轻量任务和占用大量内存/CPU 的重任务：
+// 任务执行期间除执行它的 worker 外，还会额外占住 weight-1 个 worker，
+// 使池中同时执行的任务总权重不超过容量。weight 为 1 时等同于 Submit。
+// weight 小于等于 0 或超过池容量时返回 ErrInvalidTaskWeight。
+// 阻塞模式下会等待直到凑齐 weight 个 worker，非阻塞模式下凑不齐时
+// 归还已获取的 worker 并返回 ErrPoolOverload。等待期间 Tune 将容量缩小到 weight 以下时
+// 同样归还已获取的 worker 并返回 ErrPoolOverload。
+func (p *Pool) SubmitWeighted(task func(), weight int) error {
+	if capacity := p.Cap(); weight <= 0 || (capacity != -1 && weight > capacity) {
+		return ErrInvalidTaskWeight
+	}
+	if weight == 1 {
+		return p.Submit(task)
+	}
+
+	// 检查池是否已关闭
+	if !p.isOpen() {
+		return ErrPoolClosed
+	}
+	if task == nil {
+		return ErrNilTask
+	}
+
+	if err := p.admit(1); err != nil {
+		return err
+	}
+	if err := p.acquireBudget(context.Background(), time.Time{}, 1); err != nil {
+		p.settle(1)
+		return err
+	}
+
+	workers, err := p.getWorkers(weight, 1)
+	if err != nil {
+		p.releaseBudget(1)
+		p.settle(1)
+		return err
+	}
+
+	// 额外占住的 worker 在任务结束（包括 panic）后归还
+	reserved := workers[1:]
+	t := p.newTask(taskItem{run: func() {
+		defer func() {
+			for _, w := range reserved {
+				if !p.putWorker(w) {
+					w.finish()
+				}
+			}
+		}()
+		task()
+	}})
+
+	p.metrics.submitted.Add(1)
+	workers[0].task <- t
+	return nil
+}
+
+// SubmitMany 一次提交一组相关的任务，任务全部被接受或全部不被接受
+// 每个任务占用一个 worker 并发执行，适合总是成组提交的小任务，调用方不必处理只提交了一部分的情况。
+// 阻塞模式下等待直到凑齐 len(tasks) 个 worker；非阻塞模式下凑不齐时归还已获取的 worker
+// 并返回 ErrPoolOverload，所有任务都不会执行。任务数超过池容量时永远凑不齐，直接返回 ErrPoolOverload，
+// 等待期间 Tune 将容量缩小到任务数以下时同样返回 ErrPoolOverload。
+// 没有任务时返回 nil，任意一个任务为 nil 时返回 ErrNilTask。整组被拒绝时每个任务都计入被拒绝的任务数。
+func (p *Pool) SubmitMany(tasks ...func()) error {
+	// 检查池是否已关闭
+	if !p.isOpen() {
+		return ErrPoolClosed
+	}
+
+	n := len(tasks)
+	if n == 0 {
+		return nil
+	}
+	if hasNilTask(tasks) {
+		return ErrNilTask
+	}
+	if capacity := p.Cap(); capacity != -1 && n > capacity {
+		p.rejectMany(n)
+		return ErrPoolOverload
+	}
+
+	if err := p.admit(n); err != nil {
+		return err
+	}
+	if err := p.acquireBudget(context.Background(), time.Time{}, n); err != nil {
+		p.settle(int64(n))
+		return err
+	}
+
+	workers, err := p.getWorkers(n, n)
+	if err != nil {
+		p.releaseBudget(n)
+		p.settle(int64(n))
+		return err
+	}
+
+	p.metrics.submitted.Add(int64(n))
+	for i, w := range workers {
+		w.task <- p.newTask(taskItem{run: tasks[i]})
+	}
+	return nil
+}
+
+// getWorkers 为 tasks 个任务获取 n 个 worker，任意一个获取失败或容量缩小到 n 以下时归还已获取的 worker
+// 凑不齐 worker 时将 tasks 个任务都记为被拒绝。
+func (p *Pool) getWorkers(n, tasks int) ([]*goWorker, error) {
+	p.weightedLock.Lock()
+	defer p.weightedLock.Unlock()
+
+	workers := make([]*goWorker, 0, n)
+	for len(workers) < n {
+		w, err := p.getWorker(n)
+		if err == nil && w == nil {
+			err = p.overload(tasks)
+		}
+		if err != nil {
+			for _, w := range workers {
+				if !p.putWorker(w) {
+					w.finish()
+				}
+			}
+			return nil, err
+		}
+		workers = append(workers, w)
+	}
+	return workers, nil
+}
+
+// SubmitAll 批量提交任务
+// 在一次加锁内取出尽可能多的空闲 worker 并预留可新建的 worker 名额，
+// 突发提交大量任务时不必为每个任务各自加锁和唤醒。无法立即分配的剩余任务
+// 按 Submit 的规则逐个提交：阻塞模式下等待空闲 worker，非阻塞模式下返回 ErrPoolOverload。
+// 设置了 Budget 时所有任务都按 Submit 逐个提交。
+// 返回成功提交的任务个数，出错时 tasks[submitted:] 未被提交。
+func (p *Pool) SubmitAll(tasks []func()) (submitted int, err error) {
+	// 检查池是否已关闭
+	if !p.isOpen() {
+		return 0, ErrPoolClosed
+	}
+	if hasNilTask(tasks) {
+		return 0, ErrNilTask
+	}
+
+	// 共享预算需要逐个任务占用名额，按 Submit 逐个提交
+	if p.options.Budget != nil {
+		for _, task := range tasks {
+			if err := p.Submit(task); err != nil {
+				return submitted, err
+			}
+			submitted++
+		}
+		return submitted, nil
+	}
+
+	if err := p.admit(len(tasks)); err != nil {
+		return 0, err
+	}
+
+	workers, err := p.acquireWorkers(len(tasks))
+	for i, w := range workers {
+		w.task <- p.newTask(taskItem{run: tasks[i]})
+	}
+	submitted = len(workers)
+	p.metrics.submitted.Add(int64(submitted))
+
+	// 剩余任务逐个提交
+	for _, task := range tasks[submitted:] {
+		if err == nil {
+			err = p.deliver(context.Background(), time.Time{}, p.newTask(taskItem{run: task}))
+		}
+		if err != nil {
+			p.settle(int64(len(tasks) - submitted))
+			return submitted, err
+		}
+		submitted++
+	}
+
+	return submitted, nil
+}
+
+// acquireWorkers 在一次加锁内取出至多 n 个空闲 worker，并预留可新建的 worker 名额
+// 取出的 worker 不经过 Signal 唤醒等待者，返回的 worker 数量可能少于 n。
+// 新建 worker 初始化失败时返回已获取的 worker 和第一个错误，池已暂停时不取出任何 worker。
+func (p *Pool) acquireWorkers(n int) ([]*goWorker, error) {
+	if p.IsPaused() {
+		return nil, nil
+	}
+
+	p.lock.Lock()
+	workers := make([]*goWorker, 0, n)
+	for len(workers) < n {
+		w := p.detach()
+		if w == nil {
+			break
+		}
+		workers = append(workers, w)
+	}
+
+	// 预留新建 worker 的名额
+	spawn := reserveWorkers(&p.running, &p.capacity, n-len(workers))
+	p.lock.Unlock()
+
+	// 初始化失败的 worker 已归还自己的名额
+	var firstErr error
+	for i := 0; i < spawn; i++ {
+		w, err := p.spawnWorker()
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		workers = append(workers, w)
+	}
+	return workers, firstErr
+}
+
+// newTask 创建一个任务，需要记录任务元数据或追踪任务时带上提交时间
+func (p *Pool) newTask(t taskItem) taskItem {
+	if t.id = p.tracer.submitted(p.options); t.id != 0 || p.trackTasks {
+		t.submitted = time.Now()
+	}
+	return t
+}
+
+// tryDispatch 在不等待的情况下投递任务，池已满时返回 false
+func (p *Pool) tryDispatch(t taskItem) (bool, error) {
+	if !p.isOpen() {
+		return false, ErrPoolClosed
+	}
+
+	if err := p.admit(1); err != nil {
+		return false, err
+	}
+	if b := p.options.Budget; b != nil && b.acquire(context.Background(), time.Time{}, true, 1) != nil {
+		p.settle(1)
+		return false, nil
+	}
+
+	w, err := p.tryGetWorker()
+	if err != nil || w == nil {
+		p.releaseBudget(1)
+		p.settle(1)
+		return false, err
+	}
+
+	p.metrics.submitted.Add(1)
+	w.task <- t
+	return true, nil
+}
+
+// reject 记录一次因过载被拒绝的提交，并调用过载回调
+func (p *Pool) reject() {
+	p.metrics.rejected.Add(1)
+	p.events.emit(p.options, Event{Type: TaskRejected, Err: ErrPoolOverload})
+
+	if p.options.OverloadHandler != nil {
+		p.options.OverloadHandler(OverloadInfo{
+			Pool:    p.options.Name,
+			Running: p.Running(),
+			Waiting: p.Waiting(),
+			Cap:     p.Cap(),
+		})
+	}
+}
+
+// rejectMany 记录 n 个被拒绝的任务，例如整组被拒绝的 SubmitMany
+func (p *Pool) rejectMany(n int) {
+	for i := 0; i < n; i++ {
+		p.reject()
+	}
+}
+
+// overload 记录 n 个因池已满被拒绝的任务并返回 ErrPoolOverload
+// 池在取得 worker 失败之后被关闭时返回 ErrPoolClosed，关闭不是过载，不计入被拒绝的任务数。
+func (p *Pool) overload(n int) error {
+	if !p.isOpen() {
+		return ErrPoolClosed
+	}
+	p.rejectMany(n)
+	return ErrPoolOverload
 }
 
 // Running 返回当前正在运行的 worker 数量
@@ -212,10 +895,19 @@ func (p *Pool) Running() int {
 }
 
 // Free 返回当前空闲的 worker 数量
+// 无限容量的池返回 -1，与 Cap 一致，表示提交总能立即获得 worker；
+// 需要空闲 worker 的实际数量时使用 IdleWorkers。
+// 读取原子计数，不获取池的锁，适合频繁采集指标。
 func (p *Pool) Free() int {
-	p.lock.Lock()
-	defer p.lock.Unlock()
-	return p.workers.len()
+	if p.Cap() == -1 {
+		return -1
+	}
+	return p.IdleWorkers()
+}
+
+// IdleWorkers 返回空闲队列中 worker 的数量，对无限容量的池同样返回实际数量
+func (p *Pool) IdleWorkers() int {
+	return int(atomic.LoadInt32(&p.free)) + p.idle.len()
 }
 
 // Cap 返回池的容量
@@ -224,8 +916,53 @@ func (p *Pool) Cap() int {
 }
 
 // Waiting 返回等待执行的任务数量
+// 包括排队等待执行的任务和阻塞等待 worker 的提交者。池目前没有任务队列，
+// 与 BlockedSubmitters 相同；加入任务队列后排队的任务也会计入。
 func (p *Pool) Waiting() int {
-	return int(atomic.LoadInt32(&p.waiting))
+	return p.BlockedSubmitters()
+}
+
+// BlockedSubmitters 返回阻塞等待 worker 的提交者数量，不包括排队的任务
+// 作为分片池的子池时，还包括选中本池、正在等待任意子池空出 worker 的提交者。
+func (p *Pool) BlockedSubmitters() int {
+	return int(p.waiting.Load()) + int(p.thieving.Load())
+}
+
+// Name 返回池的名称
+func (p *Pool) Name() string {
+	return p.options.Name
+}
+
+// Options 返回池创建时实际生效的配置的副本
+// 包含默认值，修改返回值不会影响池。
+func (p *Pool) Options() Options {
+	return p.options.clone()
+}
+
+// Events 返回池的事件 channel，外部系统可以据此响应池的行为而不必轮询
+// 第一次调用时创建 channel，之前发生的事件不会被记录；多次调用返回同一个 channel，
+// 多个消费者会分摊事件。缓冲大小由 WithEventBuffer 设置，缓冲已满时新的事件被丢弃，
+// 丢弃的数量可以通过 DroppedEvents 获取，池不会因消费者缓慢而阻塞。
+// channel 在池关闭后也不会被关闭，Reboot 后继续使用。
+func (p *Pool) Events() <-chan Event {
+	return p.events.channel(p.options.EventBuffer)
+}
+
+// DroppedEvents 返回因事件 channel 缓冲已满被丢弃的事件数量
+func (p *Pool) DroppedEvents() uint64 {
+	return p.events.dropped.Load()
+}
+
+// SetTrace 在运行时开启或关闭逐个任务的追踪日志
+// 开启后每个任务的提交、派发和完成都以 LevelDebug 记录一条带有任务 ID 和耗时的事件，
+// 用于排查"任务去哪了"一类的问题。只影响之后提交的任务。
+func (p *Pool) SetTrace(enabled bool) {
+	p.tracer.enabled.Store(enabled)
+}
+
+// Tracing 返回是否开启了追踪日志
+func (p *Pool) Tracing() bool {
+	return p.tracer.enabled.Load()
 }
 
 // IsClosed 返回池是否已关闭
@@ -233,200 +970,703 @@ func (p *Pool) IsClosed() bool {
 	return atomic.LoadInt32(&p.state) == CLOSED
 }
 
+// IsClosing 返回池是否正在关闭
+// Release、ReleaseTimeout 开始关闭到清理完成之间，以及 Drain 排空期间返回 true：
+// 此时池已经拒绝新的任务，但仍有清理或任务没有完成。完全关闭后返回 false，IsClosed 返回 true。
+func (p *Pool) IsClosing() bool {
+	return atomic.LoadInt32(&p.state) == CLOSING || (p.isOpen() && atomic.LoadInt32(&p.draining) == 1)
+}
+
+// isOpen 返回池是否处于 OPENED 状态，CLOSING 和 CLOSED 状态下都拒绝新的任务
+func (p *Pool) isOpen() bool {
+	return atomic.LoadInt32(&p.state) == OPENED
+}
+
+// Pause 暂停向 worker 分配新的任务
+// 暂停期间池不会被关闭，正在执行的任务不受影响：阻塞模式下的提交等待 Resume，
+// 非阻塞模式下的提交返回 ErrPoolOverload。已暂停时不做任何事。
+func (p *Pool) Pause() {
+	atomic.StoreInt32(&p.paused, 1)
+}
+
+// Resume 恢复分配任务，并唤醒所有在暂停期间阻塞的提交者
+// 未暂停时不做任何事。
+func (p *Pool) Resume() {
+	if !atomic.CompareAndSwapInt32(&p.paused, 1, 0) {
+		return
+	}
+
+	p.lock.Lock()
+	p.waiters.broadcast()
+	p.lock.Unlock()
+	p.thieves.broadcast()
+}
+
+// IsPaused 返回池是否已暂停
+func (p *Pool) IsPaused() bool {
+	return atomic.LoadInt32(&p.paused) == 1
+}
+
+// popIdle 从分片的空闲缓存获取一个 worker，池已暂停时返回 nil
+func (p *Pool) popIdle() *goWorker {
+	if p.IsPaused() {
+		return nil
+	}
+	return p.idle.pop()
+}
+
+// Stats 返回池的运行状态快照，包括当前的 worker 数量和累计的任务计数
+func (p *Pool) Stats() Stats {
+	s := Stats{
+		Running:  p.Running(),
+		Free:     p.Free(),
+		Idle:     p.IdleWorkers(),
+		Cap:      p.Cap(),
+		Waiting:  p.Waiting(),
+		Spilling: int(atomic.LoadInt32(&p.spilling)),
+	}
+	p.metrics.fill(&s)
+	return s
+}
+
 // Release 优雅关闭池，等待所有任务完成
 func (p *Pool) Release() {
-	// 标记池为关闭状态
-	if !atomic.CompareAndSwapInt32(&p.state, OPENED, CLOSED) {
+	// 标记池为正在关闭状态，清理完成后才标记为已关闭
+	stop := p.beginClose()
+	if stop == nil {
 		return
 	}
 
-	// 停止清理 goroutine
-	close(p.stopCleaning)
-	<-p.cleaningDone
+	// 停止清理 goroutine 和看门狗
+	stop()
 
 	p.lock.Lock()
 	// 关闭所有空闲的 worker
 	p.workers.reset()
-	p.lock.Unlock()
+	atomic.StoreInt32(&p.free, 0)
+	p.idle.reset()
 
 	// 唤醒所有等待的 goroutine
-	p.cond.Broadcast()
+	p.waiters.broadcast()
+	p.lock.Unlock()
+	p.thieves.broadcast()
+
+	p.endClose()
+	p.options.logReleased(p.outstanding(), 0, nil)
+
+	// 检查 worker 是否全部退出
+	p.checkLeaks()
 }
 
 // ReleaseTimeout 带超时的优雅关闭
+// 关闭池后最多等待 timeout，直到正在执行的任务（包括溢出 worker 上的任务）全部完成。
+// 超时时返回包装了 ErrTimeout 的错误，其中包含仍未完成的任务数量，
+// 这些任务会在后台继续执行完毕。
+// 超时发生在关闭空闲 worker 等清理完成之前时，池保持 CLOSING 状态直到后台清理结束，
+// 期间 Reboot 不做任何事；清理结束后池变为 CLOSED，Reboot 才能重启，不会与清理交错。
 func (p *Pool) ReleaseTimeout(timeout time.Duration) error {
-	// 标记池为关闭状态
-	if !atomic.CompareAndSwapInt32(&p.state, OPENED, CLOSED) {
+	// 标记池为正在关闭状态，清理完成且等待结束后才标记为已关闭
+	stop := p.beginClose()
+	if stop == nil {
 		return ErrPoolClosed
 	}
 
 	// 创建超时定时器
+	deadline := time.Now().Add(timeout)
 	timer := time.NewTimer(timeout)
 	defer timer.Stop()
 
 	// 使用 channel 等待关闭完成或超时
 	done := make(chan struct{})
 	go func() {
-		// 停止清理 goroutine
-		close(p.stopCleaning)
-		<-p.cleaningDone
+		// 停止清理 goroutine 和看门狗
+		stop()
 
 		p.lock.Lock()
 		p.workers.reset()
+		atomic.StoreInt32(&p.free, 0)
+		p.idle.reset()
+		p.waiters.broadcast()
 		p.lock.Unlock()
+		p.thieves.broadcast()
 
-		p.cond.Broadcast()
+		p.checkLeaks()
 		close(done)
 	}()
 
-	// 等待完成或超时
+	// 等待清理完成或超时
 	select {
 	case <-done:
-		return nil
 	case <-timer.C:
-		return ErrTimeout
+		// 清理完成后再标记为已关闭，避免 Reboot 与仍在进行的清理竞争
+		go func() {
+			<-done
+			p.endClose()
+		}()
+		running := p.outstanding()
+		err := timeoutError(running)
+		p.options.logReleased(running, timeout, err)
+		return p.options.nameError(err)
+	}
+
+	// 等待正在执行的任务完成
+	err := waitOutstanding(&p.quiet, deadline, p.outstanding)
+	p.endClose()
+	p.options.logReleased(p.outstanding(), timeout, err)
+	return p.options.nameError(err)
+}
+
+// beginClose 在持有锁时将池从 OPENED 切换到 CLOSING
+// 返回停止本代清理 goroutine 和看门狗的函数，池不处于 OPENED 状态时返回 nil。
+// 池在同一临界区内从注册表移除并取出清理 goroutine 和看门狗，Reboot 同样在持有锁时
+// 切换状态、替换它们并重新注册，关闭的总是切换前这一代的资源。
+func (p *Pool) beginClose() func() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if !atomic.CompareAndSwapInt32(&p.state, OPENED, CLOSING) {
+		return nil
+	}
+	unregister(p)
+
+	stopCleaning, cleaningDone, wd := p.stopCleaning, p.cleaningDone, p.watchdog
+	return func() {
+		stopCleaner(stopCleaning, cleaningDone)
+		wd.close()
+	}
+}
+
+// endClose 在持有锁时将池从 CLOSING 切换到 CLOSED，此后 Reboot 才能重启池
+func (p *Pool) endClose() {
+	p.lock.Lock()
+	atomic.StoreInt32(&p.state, CLOSED)
+	p.lock.Unlock()
+	p.events.emit(p.options, Event{Type: PoolClosed})
+}
+
+// outstanding 返回仍在执行任务的 worker 数量，包括溢出 worker
+func (p *Pool) outstanding() int {
+	return int(atomic.LoadInt32(&p.running) + atomic.LoadInt32(&p.spilling))
+}
+
+// quiesce 在 worker 启动和退出后调用，唤醒 ReleaseTimeout 和 WaitRunning 重新检查运行计数
+func (p *Pool) quiesce() {
+	p.quiet.notify()
+}
+
+// checkLeaks 启用了 LeakCheckGrace 时在后台检查关闭后是否有 worker 未退出
+func (p *Pool) checkLeaks() {
+	checkLeaks(p.options, &p.quiet, &p.live, p.outstanding, p.isOpen)
+}
+
+// logReleased 记录 Release 或 ReleaseTimeout 的结果，超时时以警告级别记录错误
+// Pool 和 PoolWithFunc 的两种关闭方式记录相同的字段，Release 的 timeout 为 0。
+func (opts *Options) logReleased(running int, timeout time.Duration, err error) {
+	if err != nil {
+		opts.logEvent(LevelWarn, "pool_release_timeout", Field{"running", running}, Field{"timeout", timeout}, Field{"error", err})
+		return
+	}
+	opts.logEvent(LevelInfo, "pool_released", Field{"running", running}, Field{"timeout", timeout})
+}
+
+// waitOutstanding 在 s 上等待 outstanding 降为 0
+// 到达 deadline 时仍有未完成的任务则返回 timeoutError。
+func waitOutstanding(s *zeroSignal, deadline time.Time, outstanding func() int) error {
+	if n, err := s.await(context.Background(), deadline, outstanding); err != nil {
+		return timeoutError(n)
 	}
+	return nil
+}
+
+// timeoutError 返回包装了 ErrTimeout 的错误，说明还有 n 个任务未完成
+func timeoutError(n int) error {
+	return fmt.Errorf("%w: %d tasks still running", ErrTimeout, n)
+}
+
+// Drain 排空并关闭池
+// 调用后池立即停止接受新的任务，排空期间的提交返回 ErrDraining；
+// 已接受的任务（包括正在阻塞等待 worker 的提交）会继续执行，
+// 全部执行完毕后关闭池并返回 nil。ctx 在此之前被取消时返回 ctx.Err()，
+// 池保持排空状态，可以再次调用 Drain 继续等待，或调用 Release 直接关闭。
+// 池已关闭时返回 ErrPoolClosed。
+func (p *Pool) Drain(ctx context.Context) error {
+	if !p.isOpen() {
+		return ErrPoolClosed
+	}
+
+	atomic.StoreInt32(&p.draining, 1)
+	if err := waitInflight(ctx, &p.quiet, &p.inflight); err != nil {
+		return err
+	}
+
+	p.Release()
+	return nil
+}
+
+// waitInflight 在 s 上等待 inflight 降为 0，ctx 被取消时返回 ctx.Err()
+func waitInflight(ctx context.Context, s *zeroSignal, inflight *atomic.Int64) error {
+	_, err := s.await(ctx, time.Time{}, func() int {
+		return int(inflight.Load())
+	})
+	return err
+}
+
+// Wait 阻塞直到池中没有正在执行和等待 worker 的任务
+// 适合批量作业在提交完所有任务后等待池空闲，不必为每次提交维护 WaitGroup。
+// 等待期间仍可以提交新的任务，它们同样会被等待。
+func (p *Pool) Wait() {
+	_ = waitInflight(context.Background(), &p.quiet, &p.inflight)
+}
+
+// WaitWithTimeout 与 Wait 相同，但最多等待 timeout
+// 超时时返回包装了 ErrTimeout 的错误，错误信息中包含未完成的任务数量。
+func (p *Pool) WaitWithTimeout(timeout time.Duration) error {
+	return waitOutstanding(&p.quiet, time.Now().Add(timeout), func() int {
+		return int(p.inflight.Load())
+	})
+}
+
+// WaitRunning 阻塞直到池中运行的 worker 数量变为 n，不包括溢出 worker
+// worker 的创建和回收是异步的，适合在 Tune、PurgeNow 或过期回收之后等待池到达预期的规模。
+// ctx 被取消时返回 ctx.Err()。
+func (p *Pool) WaitRunning(ctx context.Context, n int) error {
+	_, err := p.quiet.await(ctx, time.Time{}, func() int {
+		if p.Running() != n {
+			return 1
+		}
+		return 0
+	})
+	return err
 }
 
 // Reboot 重启已关闭的池
+// 只对完全关闭（CLOSED）的池生效，池正在关闭（IsClosing）时不做任何事，
+// 避免与仍在进行的清理竞争。
 func (p *Pool) Reboot() {
-	if atomic.CompareAndSwapInt32(&p.state, CLOSED, OPENED) {
-		// 重新创建清理相关的 channel
-		p.stopCleaning = make(chan struct{})
-		p.cleaningDone = make(chan struct{})
-		// 重启清理 goroutine
-		go p.cleanExpiredWorkers()
+	// 状态切换、替换清理 goroutine 和看门狗以及重新注册在同一临界区内完成，
+	// 不会与并发的 Release 交错
+	p.lock.Lock()
+	if !atomic.CompareAndSwapInt32(&p.state, CLOSED, OPENED) {
+		p.lock.Unlock()
+		return
 	}
+
+	atomic.StoreInt32(&p.paused, 0)
+	atomic.StoreInt32(&p.draining, 0)
+	// 重启清理 goroutine 和看门狗
+	p.startCleaning()
+	p.watchdog = startWatchdog(p.options, &p.live)
+	register(p)
+	p.lock.Unlock()
+
+	p.options.logEvent(LevelInfo, "pool_rebooted", Field{"cap", p.Cap()})
+	p.events.emit(p.options, Event{Type: PoolRebooted, Cap: p.Cap()})
 }
 
-// getWorker 获取一个可用的 worker
-// 优化：最小化锁持有时间，使用 atomic 操作避免不必要的锁
-func (p *Pool) getWorker() *goWorker {
-	var w *goWorker
+// Tune 调整池的容量
+// 对无限容量的池、size 小于等于 0、超过 math.MaxInt32 或与当前容量相同时不做任何事。
+// 缩容时不会打断正在执行的任务，多出的 worker 在执行完当前任务后退出，
+// 正在等待且需要的 worker 数量超过新容量的 SubmitWeighted 和 SubmitMany 返回 ErrPoolOverload。
+// 扩容后新的提交可以立即创建 worker，已阻塞的提交者在有 worker 归还时被唤醒。
+func (p *Pool) Tune(size int) {
+	capacity := p.Cap()
+	if capacity == -1 || size <= 0 || size > maxPoolSize || size == capacity {
+		return
+	}
 
+	var lost error
 	p.lock.Lock()
+	atomic.StoreInt32(&p.capacity, int32(size))
+
+	// 循环队列的大小固定，扩容时换成更大的队列，避免归还的 worker 放不下
+	if q, ok := p.workers.(*loopQueue); ok && size > q.size {
+		grown := newWorkerLoopQueue(size)
+		for w := q.detach(); w != nil; w = q.detach() {
+			if err := grown.insert(w); err != nil {
+				// 放不下的 worker 直接结束，不能让它脱离队列后一直等待任务
+				w.finish()
+				atomic.AddInt32(&p.free, -1)
+				lost = err
+			}
+		}
+		p.workers = grown
+	}
 
-	// 尝试从队列中获取空闲 worker
-	w = p.workers.detach()
+	// 扩容后一次唤醒所有等待者，使它们在新增的容量上创建 worker；
+	// 缩容后同样唤醒，使需要的 worker 数量超过新容量的 SubmitWeighted 和 SubmitMany 返回
+	p.waiters.broadcast()
+	p.lock.Unlock()
+	if size > capacity {
+		p.thieves.broadcast()
+	}
 
-	if w != nil {
-		// 找到空闲 worker，立即释放锁以减少锁持有时间
-		p.lock.Unlock()
-		return w
+	if lost != nil {
+		p.options.reportError(fmt.Errorf("%w: move idle worker on tune: %w", ErrWorkerQueue, lost))
 	}
 
-	// 检查是否可以创建新的 worker（使用 atomic 读取避免额外的锁）
-	capacity := atomic.LoadInt32(&p.capacity)
-	running := atomic.LoadInt32(&p.running)
+	p.options.logEvent(LevelInfo, "pool_tuned", Field{"old_cap", capacity}, Field{"new_cap", size})
+	p.events.emit(p.options, Event{Type: PoolTuned, OldCap: capacity, Cap: size})
+}
 
-	if capacity == -1 || running < capacity {
-		// 可以创建新 worker，先释放锁
-		p.lock.Unlock()
+// SubscribeStats 订阅池的状态快照，每隔 interval 推送一次
+// interval 小于等于 0 时使用 DefaultStatsInterval。
+// 消费者处理不及时时只保留最新的快照。不再需要时必须调用返回的
+// unsubscribe 函数，否则推送 goroutine 会一直存在。
+func (p *Pool) SubscribeStats(interval time.Duration) (<-chan Stats, func()) {
+	return subscribeStats(interval, p.Stats)
+}
 
-		// 从对象池获取 worker 对象以复用
-		w = p.workerPool.Get().(*goWorker)
+// NamedStats 返回由 SubmitNamed 提交的任务按名称的统计，键为任务名称
+// 没有提交过带名称的任务时返回空 map。
+func (p *Pool) NamedStats() map[string]NamedStats {
+	return p.named.snapshot()
+}
 
-		// 重置 worker 状态
-		atomic.StoreInt32(&w.recycled, 0)
-		w.lastUsed = time.Now()
+// RecentPanics 返回最近发生的 panic 记录，按时间从旧到新排列
+func (p *Pool) RecentPanics() []PanicRecord {
+	return p.panics.snapshot()
+}
 
-		// 增加运行计数
-		atomic.AddInt32(&p.running, 1)
+// Workers 返回当前空闲 worker 的存活时长和空闲时长
+func (p *Pool) Workers() []WorkerInfo {
+	p.lock.Lock()
+	// 持有锁之后再取当前时间，空闲队列中 worker 的最后使用时间都不会晚于 now
+	now := p.options.clock().Now()
+	infos := make([]WorkerInfo, 0, p.workers.len()+p.idle.len())
+	collect := func(w *goWorker) {
+		infos = append(infos, newWorkerInfo(w.id, w.created, w.idleSince(), now))
+	}
+	p.workers.each(collect)
+	p.idle.each(collect)
+	p.lock.Unlock()
 
-		// 启动 worker
-		w.run()
+	return infos
+}
 
-		return w
+// DumpStacks 返回所有正在执行任务的 worker 的 goroutine 栈
+// 用于在生产环境中定位挂起的任务，而不必导出全部 goroutine。
+// 获取栈需要短暂地暂停所有 goroutine，不宜频繁调用。
+// 需要启用 WithStackDumps、WithWatchdog 或 WithLeakCheck，否则返回 nil。
+func (p *Pool) DumpStacks() []WorkerStack {
+	now := time.Now().UnixNano()
+	return dumpStacks(p.live.busy(now, 0), now)
+}
+
+// PurgeNow 立即回收所有空闲的 worker，返回被回收的 worker 数量
+// 不必等待过期清理 goroutine 的下一次扫描，适合在流量高峰过后或
+// 对 GC 敏感的阶段之前主动释放资源。正在执行任务的 worker 不受影响。
+// 运行计数由 worker goroutine 退出时自行扣减。
+func (p *Pool) PurgeNow() int {
+	if !p.isOpen() {
+		return 0
 	}
 
-	// 池已满
-	if p.options.Nonblocking {
-		// 非阻塞模式，直接返回 nil
-		p.lock.Unlock()
-		return nil
+	p.lock.Lock()
+	n := p.workers.len() + p.idle.len()
+	p.workers.reset()
+	atomic.StoreInt32(&p.free, 0)
+	p.idle.reset()
+	p.lock.Unlock()
+
+	if n > 0 {
+		p.options.logEvent(LevelInfo, "workers_purged", Field{"count", n})
+	}
+
+	return n
+}
+
+// getWorker 为需要同时占用 need 个 worker 的提交获取一个可用的 worker
+// 池已关闭或创建新 worker 时 WorkerInit 失败时返回错误
+func (p *Pool) getWorker(need int) (*goWorker, error) {
+	return p.acquireWorker(context.Background(), time.Time{}, need)
+}
+
+// acquireWorker 获取一个可用的 worker，阻塞等待时可以通过 ctx 取消，
+// deadline 不为零值时最多等待到 deadline，超时返回 ErrTimeout
+// 优化：最小化锁持有时间，使用 atomic 操作避免不必要的锁
+// need 为调用方需要同时占用的 worker 数量，缩容后容量小于 need 时永远凑不齐，按池已满处理。
+// 非阻塞模式下池已满或等待的提交数已达 MaxBlockingTasks 时返回 nil；池已关闭时返回 ErrPoolClosed，
+// 创建新 worker 时 WorkerInit 失败或 ctx 被取消时返回错误。
+func (p *Pool) acquireWorker(ctx context.Context, deadline time.Time, need int) (*goWorker, error) {
+	// 启用分片锁时先从分片的空闲缓存获取，不获取池的锁
+	if w := p.popIdle(); w != nil {
+		return w, nil
+	}
+
+	var wt *waiter
+
+	p.lock.Lock()
+	for {
+		// 池已暂停时不分配 worker，按池已满处理
+		if !p.IsPaused() {
+			// 尝试从队列中获取空闲 worker
+			if w := p.detach(); w != nil {
+				// 找到空闲 worker，立即释放锁以减少锁持有时间
+				p.lock.Unlock()
+				return w, nil
+			}
+
+			// 容量允许时占用一个运行名额并创建新的 worker
+			// 检查和占用是同一次 CAS，并发的提交者不会使运行的 worker 超过容量
+			if reserveWorkers(&p.running, &p.capacity, 1) == 1 {
+				p.lock.Unlock()
+				return p.spawnWorker()
+			}
+		}
+
+		// 池已满或已暂停，先检查池是否已关闭（包括被唤醒后）
+		if !p.isOpen() {
+			p.lock.Unlock()
+			return nil, ErrPoolClosed
+		}
+
+		if p.options.Nonblocking {
+			// 非阻塞模式，直接返回 nil
+			p.lock.Unlock()
+			return nil, nil
+		}
+
+		// 容量已缩小到 need 以下，等待也凑不齐，直接返回 nil（包括被 Tune 唤醒后）
+		if capacity := atomic.LoadInt32(&p.capacity); capacity != -1 && need > int(capacity) {
+			p.lock.Unlock()
+			return nil, nil
+		}
+
+		// 等待的提交数已达 MaxBlockingTasks 上限时不再等待，按池已满处理
+		// 只在开始等待前检查，被唤醒后重试的提交不会因为之后到达的提交被拒绝
+		if wt == nil && p.options.blockingFull(p.waiting.Load()) {
+			p.lock.Unlock()
+			return nil, nil
+		}
+
+		// 阻塞模式，等待 worker 可用后重试
+		// 增加等待计数后再检查一次分片缓存，避免错过在此之前放入缓存的 worker
+		p.waiting.Add(1)
+		if w := p.popIdle(); w != nil {
+			p.waiting.Add(-1)
+			p.lock.Unlock()
+			return w, nil
+		}
+		if wt == nil {
+			wt = newWaiter()
+		}
+		err := p.waiters.wait(ctx, wt, p.lock, deadline)
+		p.waiting.Add(-1)
+		if err != nil {
+			p.lock.Unlock()
+			return nil, err
+		}
+	}
+}
+
+// tryGetWorker 不等待地获取一个可用的 worker，池已满时返回 nil
+// 用于分片池在子池之间窃取空闲容量，不受 Nonblocking 配置影响。
+func (p *Pool) tryGetWorker() (*goWorker, error) {
+	// 池已暂停时不分配 worker
+	if p.IsPaused() {
+		return nil, nil
 	}
 
-	// 阻塞模式，等待 worker 可用
-	atomic.AddInt32(&p.waiting, 1)
-	p.cond.Wait()
-	atomic.AddInt32(&p.waiting, -1)
+	if w := p.idle.pop(); w != nil {
+		return w, nil
+	}
 
-	// 被唤醒后，检查池是否已关闭
-	if atomic.LoadInt32(&p.state) == CLOSED {
+	p.lock.Lock()
+	if w := p.detach(); w != nil {
 		p.lock.Unlock()
-		return nil
+		return w, nil
 	}
 
-	// 再次尝试获取 worker
-	w = p.workers.detach()
 	p.lock.Unlock()
 
+	if reserveWorkers(&p.running, &p.capacity, 1) == 1 {
+		return p.spawnWorker()
+	}
+	return nil, nil
+}
+
+// spawnWorker 从对象池获取一个 worker、初始化并启动它
+// 调用方负责事先增加运行计数，初始化失败时归还占用的容量
+func (p *Pool) spawnWorker() (*goWorker, error) {
+	w := p.newWorker()
+
+	// 初始化 per-worker 资源，失败时归还占用的容量
+	if err := w.init(); err != nil {
+		atomic.AddInt32(&p.running, -1)
+		p.signal()
+		p.quiesce()
+		return nil, err
+	}
+
+	// 启动 worker
+	w.run()
+
+	return w, nil
+}
+
+// newWorker 从对象池获取 worker 对象以复用，并重置其状态
+func (p *Pool) newWorker() *goWorker {
+	w := p.workerPool.Get().(*goWorker)
+
+	atomic.StoreInt32(&w.recycled, 0)
+	atomic.StoreInt32(&w.expired, 0)
+	w.spill = false
+	w.retired = false
+	w.id = int(atomic.AddInt64(&p.workerSeq, 1))
+	w.created = p.options.clock().Now()
+	w.lastUsed.Store(w.created.UnixNano())
+
 	return w
 }
 
+// detach 从空闲队列或分片的空闲缓存中取出一个 worker，调用方必须持有 p.lock
+func (p *Pool) detach() *goWorker {
+	if w := p.workers.detach(); w != nil {
+		atomic.AddInt32(&p.free, -1)
+		return w
+	}
+	return p.idle.pop()
+}
+
 // putWorker 将 worker 放回池中
 // 优化：在锁外更新时间戳，减少锁持有时间
 func (p *Pool) putWorker(worker *goWorker) bool {
 	// 使用 atomic 检查池状态，避免不必要的锁
-	if atomic.LoadInt32(&p.state) == CLOSED {
+	if !p.isOpen() {
+		return false
+	}
+
+	// 缩容后运行的 worker 超过容量时，让多出的 worker 退出，运行计数在此扣减
+	if retireWorker(&p.running, &p.capacity) {
+		worker.retired = true
 		return false
 	}
 
 	// 更新 worker 的最后使用时间（在锁外执行）
-	worker.lastUsed = time.Now()
+	worker.updateLastUsed()
+
+	// 启用分片锁且没有等待者时放入分片的空闲缓存，不获取池的锁
+	if p.idle.enabled() && p.waiting.Load() == 0 {
+		p.idle.push(worker)
+		p.afterIdlePush()
+		return true
+	}
 
 	p.lock.Lock()
 
-	// 将 worker 放回队列
+	// 持有锁后再检查一次：Release 先标记关闭再加锁清空队列，
+	// 在它之后放回的 worker 不会再被结束，只能让它自己退出
+	if !p.isOpen() {
+		p.lock.Unlock()
+		return false
+	}
+
+	// 将 worker 放回队列，放不回时让 worker 退出
 	if err := p.workers.insert(worker); err != nil {
 		p.lock.Unlock()
+		p.options.reportError(fmt.Errorf("%w: insert idle worker: %w", ErrWorkerQueue, err))
 		return false
 	}
+	atomic.AddInt32(&p.free, 1)
 
 	// 只在有等待的 goroutine 时才唤醒
 	// 优化：减少不必要的 Signal 调用
-	if atomic.LoadInt32(&p.waiting) > 0 {
-		p.cond.Signal()
+	signaled := false
+	if p.waiting.Load() > 0 {
+		signaled = p.waiters.signal()
 	}
 	p.lock.Unlock()
 
+	// 没有本池的等待者时，空闲的 worker 交给其他子池积压的提交
+	if !signaled {
+		p.thieves.notify()
+	}
+
 	return true
 }
 
+// signal 唤醒最早开始等待 worker 的一个提交者
+// 用于 worker 退出或归还容量后，等待者可以在空出的容量上创建新的 worker。
+func (p *Pool) signal() {
+	p.lock.Lock()
+	signaled := p.waiters.signal()
+	p.lock.Unlock()
+	if !signaled {
+		p.thieves.notify()
+	}
+}
+
+// afterIdlePush 处理放入分片缓存期间发生的等待和关闭
+// 等待者在增加等待计数后会再检查一次缓存，这里只需唤醒此后进入等待的提交者；
+// 池在放入期间被关闭时，由这里结束缓存中的 worker。
+func (p *Pool) afterIdlePush() {
+	if p.waiting.Load() > 0 {
+		p.signal()
+	} else {
+		p.thieves.notify()
+	}
+	if !p.isOpen() {
+		p.lock.Lock()
+		p.idle.reset()
+		p.lock.Unlock()
+	}
+}
+
+// startCleaning 创建清理相关的 channel 并启动清理 goroutine
+// 如果禁用了清理（DisablePurge），则不创建任何资源
+func (p *Pool) startCleaning() {
+	if p.options.DisablePurge {
+		return
+	}
+
+	p.stopCleaning = make(chan struct{})
+	p.cleaningDone = make(chan struct{})
+
+	// 在启动 goroutine 前创建 ticker，池创建或重启返回时扫描周期已从时钟开始计算
+	tick, stopTicker := p.options.clock().NewTicker(p.options.cleanInterval())
+	go p.cleanExpiredWorkers(tick, stopTicker, p.stopCleaning, p.cleaningDone)
+}
+
+// stopCleaner 停止一代清理 goroutine 并等待其退出，禁用了清理时 stop 为 nil，不做任何事
+func stopCleaner(stop, done chan struct{}) {
+	if stop == nil {
+		return
+	}
+
+	close(stop)
+	<-done
+}
+
 // cleanExpiredWorkers 定期清理过期的 worker
-func (p *Pool) cleanExpiredWorkers() {
-	ticker := time.NewTicker(p.options.ExpiryDuration)
+// tick 和 stopTicker 是本代的扫描周期，stop 和 done 是本代清理 goroutine 的 channel，
+// 由参数传入而不是读取池的字段，Reboot 替换字段时不影响仍在退出的上一代。
+func (p *Pool) cleanExpiredWorkers(tick <-chan time.Time, stopTicker func(), stop <-chan struct{}, done chan<- struct{}) {
 	defer func() {
-		ticker.Stop()
-		close(p.cleaningDone)
+		stopTicker()
+		close(done)
 	}()
 
 	for {
 		select {
-		case <-ticker.C:
+		case <-tick:
 			// 使用 atomic 检查池状态，避免不必要的锁
-			if atomic.LoadInt32(&p.state) == CLOSED {
+			if !p.isOpen() {
 				return
 			}
 
+			// 过期的 worker 只收到退出信号，运行计数由 worker goroutine 退出时自行扣减
+			now := p.options.clock().Now()
 			p.lock.Lock()
-			expiredWorkers := p.workers.refresh(p.options.ExpiryDuration)
+			expiredWorkers := p.workers.refresh(now, p.options.ExpiryDuration)
+			atomic.AddInt32(&p.free, -int32(len(expiredWorkers)))
+			expiredWorkers = append(expiredWorkers, p.idle.refresh(now, p.options.ExpiryDuration)...)
 			p.lock.Unlock()
 
 			// 记录日志（在锁外执行，减少锁持有时间）
-			if len(expiredWorkers) > 0 && p.options.Logger != nil {
-				for _, idx := range expiredWorkers {
-					p.options.Logger.Printf("worker at index %d expired and will be recycled", idx)
-				}
-			}
-
-			// 减少运行计数（过期的worker已经从队列中移除）
-			n := int32(len(expiredWorkers))
-			if n > 0 {
-				atomic.AddInt32(&p.running, -n)
+			for _, w := range expiredWorkers {
+				p.options.logEvent(LevelDebug, "worker_expired", Field{"worker_id", w.id}, Field{"idle_for", w.idleFor})
 			}
 
-		case <-p.stopCleaning:
+		case <-stop:
 			return
 		}
 	}
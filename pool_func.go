@@ -1,6 +1,9 @@
 package laborer
 
 import (
+	"context"
+	"fmt"
+	"runtime/debug"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -12,13 +15,35 @@ type goWorkerWithFunc struct {
 	pool *PoolWithFunc
 
 	// 参数 channel
-	args chan interface{}
+	args chan invocation
 
-	// 最后使用时间（用于超时回收）
-	lastUsed time.Time
+	// info 当前任务的元数据，仅在需要记录任务元数据时赋值
+	info TaskInfo
+
+	// infoEnded 当前任务已经调用过 endTask，OnTaskComplete 发生 panic 时 recover 不会再次调用
+	infoEnded bool
+
+	// workerState worker 的编号和忙碌状态
+	workerState
+
+	// 创建时间
+	created time.Time
+
+	// 最后使用时间（UnixNano，用于超时回收）
+	// 归还时在锁外写入，清理 goroutine 和 Workers 在锁内读取，使用 atomic 避免数据竞争
+	lastUsed atomic.Int64
 
 	// 回收标志
 	recycled int32
+
+	// 过期标志，由清理 goroutine 在回收空闲超时的 worker 时设置
+	expired int32
+
+	// spill 是否为池饱和时创建的溢出 worker，只执行一个调用且不计入运行计数
+	spill bool
+
+	// retired 缩容时由 putWorker 扣减了运行计数，退出时不再扣减
+	retired bool
 }
 
 // PoolWithFunc 函数池，用于执行相同类型的任务
@@ -31,26 +56,56 @@ type PoolWithFunc struct {
 	// running 当前运行的 worker 数量
 	running int32
 
-	// state 池的状态：OPENED 或 CLOSED
+	// state 池的状态：OPENED、CLOSING 或 CLOSED
 	state int32
 
 	// lock 保护 workers 队列的锁
 	lock sync.Locker
 
-	// cond 条件变量，用于阻塞模式下的等待
-	cond *sync.Cond
+	// waiters 阻塞模式下等待 worker 的提交者，按 FIFO 顺序唤醒
+	waiters waitQueue
 
 	// workers worker 队列，存储空闲的 worker
 	workers workerQueueWithFunc
 
+	// free workers 队列中的 worker 数量，在插入和取出时更新，
+	// 使 Free 不必加锁读取队列长度
+	free int32
+
+	// idle 分片的空闲 worker 缓存，仅在启用 WithShardedLocking 时使用
+	idle idleShards[*goWorkerWithFunc]
+
 	// poolFunc 池中所有 worker 执行的固定函数
 	poolFunc func(interface{})
 
 	// options 配置选项
 	options *Options
 
+	// paused 池是否已暂停，暂停时不向 worker 分配新的调用
+	paused int32
+
+	// draining 池是否正在排空，排空时拒绝新的提交
+	draining int32
+
+	// inflight 已接受但尚未执行完毕的调用数量，包括阻塞等待 worker 的提交
+	// 和本池放入任务队列的调用；队列中其他来源的参数在取出时才计入
+	inflight atomic.Int64
+
+	// queued 本池放入任务队列、尚未取出的调用数量，用于区分取出的参数是否已计入 inflight
+	queued atomic.Int64
+
 	// waiting 等待执行的任务数量
-	waiting int32
+	// 使用 int64，排队的任务数量很大时也不会溢出
+	waiting atomic.Int64
+
+	// thieves 启用工作窃取的分片池中所有子池共享的窃取等待队列，其他情况下为 nil
+	thieves *thieves
+
+	// quiet inflight 降为 0 或运行计数变化的通知，Wait、Drain、ReleaseTimeout 和 WaitRunning 在其上等待
+	quiet zeroSignal
+
+	// thieving 以本池为选中子池、等待任意子池空出 worker 的提交者数量
+	thieving atomic.Int32
 
 	// stopCleaning 用于停止清理 goroutine 的 channel
 	stopCleaning chan struct{}
@@ -60,6 +115,73 @@ type PoolWithFunc struct {
 
 	// workerPool 用于复用 worker 对象，减少 GC 压力
 	workerPool sync.Pool
+
+	// metrics 累计任务计数器
+	metrics poolMetrics
+
+	// workerSeq 用于分配 worker ID 的递增序号
+	workerSeq int64
+
+	// live 所有存活的 worker，用于 DumpStacks 和看门狗
+	live workerSet
+
+	// watchdog 卡住 worker 的看门狗，未启用时为 nil
+	watchdog *watchdog
+
+	// panics 最近的 panic 记录
+	panics panicLog
+
+	// trackTasks 是否需要记录任务的时间元数据
+	trackTasks bool
+
+	// trackWorkers 是否需要记录 worker 的 goroutine 和忙碌状态
+	trackWorkers bool
+
+	// tracer 逐个任务的追踪日志，由 SetTrace 开关
+	tracer tracer
+
+	// events Events 返回的事件 channel
+	events eventHub
+
+	// spilling 当前正在运行的溢出 worker 数量，不计入 running
+	spilling int32
+
+	// ctx 传给固定函数的上下文，在池关闭时取消
+	// 仅由 NewPoolWithContextFunc 创建的池设置
+	ctx atomic.Pointer[poolContext]
+
+	// consecutivePanics 固定函数连续 panic 的次数，成功执行一次后清零
+	consecutivePanics atomic.Int32
+
+	// quarantinedUntil 隔离结束的时间（UnixNano），0 表示未隔离
+	quarantinedUntil atomic.Int64
+}
+
+// poolContext 保存池的上下文及其取消函数
+type poolContext struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// invocation 表示投递给函数池 worker 的一次调用
+type invocation struct {
+	// args 传给固定函数的参数
+	args interface{}
+
+	// stop 为 true 表示 worker 应该退出
+	stop bool
+
+	// submitted 提交时间，仅在需要记录任务元数据或追踪调用时赋值
+	submitted time.Time
+
+	// id 追踪日志中的调用 ID，未开启追踪时为 0
+	id uint64
+
+	// queued 是否从任务队列取出，执行结束后需要确认
+	queued bool
+
+	// budgeted 提交时是否已占用共享预算名额
+	budgeted bool
 }
 
 // PoolWithFuncInterface 定义函数池的接口
@@ -76,6 +198,9 @@ type PoolWithFuncInterface interface {
 	// Reboot 重启已关闭的池
 	Reboot()
 
+	// PurgeNow 立即回收所有空闲的 worker
+	PurgeNow() int
+
 	// Running 返回正在运行的 worker 数量
 	Running() int
 
@@ -90,15 +215,37 @@ type PoolWithFuncInterface interface {
 
 	// IsClosed 返回池是否已关闭
 	IsClosed() bool
+
+	// Stats 返回池的运行状态快照
+	Stats() Stats
 }
 
 // NewPoolWithFunc 创建一个新的函数池
-// size: 池的容量，-1 表示无限容量
+// size: 池的容量，-1 表示无限容量，0 表示按 CPU 数量换算（见 WithSizeMultiplier）
 // pf: 池中所有 worker 执行的固定函数
 // options: 配置选项
 func NewPoolWithFunc(size int, pf func(interface{}), options ...Option) (*PoolWithFunc, error) {
+	pool, err := newPoolWithFunc(size, pf, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	// 队列中已有的参数（例如上次运行留下的持久化任务）开始执行，取出时计入 inflight
+	pool.drainQueue()
+
+	return pool, nil
+}
+
+// newPoolWithFunc 创建函数池，不执行任务队列中已有的参数
+// 调用方完成其余的初始化（例如创建池的上下文）之后再调用 drainQueue，
+// 避免 worker 在池初始化完成之前开始执行。
+func newPoolWithFunc(size int, pf func(interface{}), options ...Option) (*PoolWithFunc, error) {
+	// 创建配置选项
+	opts := NewOptions(options...)
+
 	// 验证容量参数
-	if size == 0 {
+	size = opts.poolSize(size)
+	if !validPoolSize(size) {
 		return nil, ErrInvalidPoolSize
 	}
 
@@ -107,71 +254,405 @@ func NewPoolWithFunc(size int, pf func(interface{}), options ...Option) (*PoolWi
 		return nil, ErrInvalidPoolFunc
 	}
 
-	// 创建配置选项
-	opts := NewOptions(options...)
+	// 验证组合后的配置选项
+	if err := opts.validate(size); err != nil {
+		return nil, err
+	}
 
-	// 验证过期时间
-	if opts.ExpiryDuration < 0 {
-		return nil, ErrInvalidPoolExpiry
+	// 固定函数无法接收 per-worker 资源，WorkerInit 和 WorkerTeardown 仅对 Pool 生效
+	if opts.WorkerInit != nil || opts.WorkerTeardown != nil {
+		return nil, invalidOption("WorkerInit and WorkerTeardown are only supported by Pool")
 	}
 
 	// 创建池实例
 	pool := &PoolWithFunc{
-		capacity:     int32(size),
-		poolFunc:     pf,
-		options:      opts,
-		stopCleaning: make(chan struct{}),
-		cleaningDone: make(chan struct{}),
+		capacity: int32(size),
+		poolFunc: pf,
+		options:  opts,
 	}
+	pool.metrics.enableLatency(opts.LatencyBuckets)
+	pool.trackTasks = trackTasks(opts)
+	pool.trackWorkers = trackWorkers(opts)
+	pool.tracer.enabled.Store(opts.Trace)
 
 	// 初始化锁和条件变量
-	pool.lock = new(sync.Mutex)
-	pool.cond = sync.NewCond(pool.lock)
+	if opts.SpinLock {
+		pool.lock = newSpinLock()
+	} else {
+		pool.lock = new(sync.Mutex)
+	}
 
 	// 初始化 worker 对象池，用于复用 worker 对象
 	// 优化：使用带缓冲的 channel 减少阻塞
 	pool.workerPool.New = func() interface{} {
 		return &goWorkerWithFunc{
 			pool: pool,
-			args: make(chan interface{}, workerChanCap),
+			args: make(chan invocation, workerChanCap),
 		}
 	}
 
-	// 根据容量选择合适的 worker 队列实现
-	if size == -1 {
-		// 无限容量，使用栈
-		pool.workers = newWorkerStackWithFunc(0)
-	} else if size < queueSizeThreshold {
-		// 小容量，使用栈
-		if opts.PreAlloc {
-			pool.workers = newWorkerStackWithFunc(size)
-		} else {
-			pool.workers = newWorkerStackWithFunc(0)
+	// 根据配置和容量选择 worker 队列实现
+	// 默认小容量使用栈（LIFO），大容量使用循环队列（FIFO）
+	if opts.WorkerQueueFactory != nil {
+		q := opts.WorkerQueueFactory(size)
+		if q == nil {
+			return nil, invalidOption("worker queue factory returned nil")
 		}
-	} else {
-		// 大容量，使用循环队列
+		pool.workers = customQueue[*goWorkerWithFunc]{q: q, opts: opts}
+	} else if opts.queueType(size) == LoopQueue {
 		pool.workers = newWorkerLoopQueueWithFunc(size)
+	} else if opts.PreAlloc {
+		pool.workers = newWorkerStackWithFunc(size)
+	} else {
+		pool.workers = newWorkerStackWithFunc(0)
+	}
+	if opts.ShardedLocking {
+		pool.idle.init()
 	}
 
 	// 启动定期清理过期 worker 的 goroutine
-	go pool.cleanExpiredWorkers()
+	pool.startCleaning()
+	pool.watchdog = startWatchdog(opts, &pool.live)
+
+	// 加入全局注册表
+	register(pool)
+	opts.logEvent(LevelInfo, "pool_created", Field{"cap", size}, Field{"nonblocking", opts.Nonblocking})
 
 	return pool, nil
 }
 
+// NewPoolWithContextFunc 创建一个新的函数池，固定函数额外接收池的上下文
+// 上下文在 Release / ReleaseTimeout 时被取消，Reboot 后使用新的上下文，
+// 使长时间运行的处理函数能够在关闭时及时退出。
+// size: 池的容量，-1 表示无限容量，0 表示按 CPU 数量换算（见 WithSizeMultiplier）
+// pf: 池中所有 worker 执行的固定函数
+// options: 配置选项
+func NewPoolWithContextFunc(size int, pf func(ctx context.Context, args interface{}), options ...Option) (*PoolWithFunc, error) {
+	// 验证函数参数
+	if pf == nil {
+		return nil, ErrInvalidPoolFunc
+	}
+
+	var pool *PoolWithFunc
+	pool, err := newPoolWithFunc(size, func(args interface{}) {
+		pf(pool.ctx.Load().ctx, args)
+	}, options...)
+	if err != nil {
+		return nil, err
+	}
+	pool.startContext()
+
+	// 上下文创建之后再执行队列中已有的参数
+	pool.drainQueue()
+
+	return pool, nil
+}
+
+// startContext 为池创建新的上下文
+func (p *PoolWithFunc) startContext() {
+	ctx, cancel := context.WithCancel(context.Background())
+	p.ctx.Store(&poolContext{ctx: ctx, cancel: cancel})
+}
+
+// cancelContext 取消池的上下文，未使用上下文的池不做任何事
+func (p *PoolWithFunc) cancelContext() {
+	if pc := p.ctx.Load(); pc != nil {
+		pc.cancel()
+	}
+}
+
 // Invoke 提交参数到固定函数执行
 func (p *PoolWithFunc) Invoke(args interface{}) error {
 	// 检查池是否已关闭
-	if p.IsClosed() {
+	if !p.isOpen() {
 		return ErrPoolClosed
 	}
+	if p.quarantined() {
+		return ErrPoolQuarantined
+	}
+
+	return p.dispatch(context.Background(), time.Time{}, p.invocation(args))
+}
 
-	// 获取一个 worker 并分配参数
-	if w := p.getWorker(); w != nil {
-		w.args <- args
+// InvokeWithTimeout 提交参数到固定函数执行，阻塞等待空闲 worker 的时间不超过 timeout
+// 阻塞模式下池满时，Invoke 会一直等待；InvokeWithTimeout 在 timeout 后放弃并返回
+// ErrTimeout，便于生产者实现背压而不必将整个池切换为非阻塞模式。
+// 非阻塞模式下行为与 Invoke 相同。
+func (p *PoolWithFunc) InvokeWithTimeout(args interface{}, timeout time.Duration) error {
+	// 检查池是否已关闭
+	if !p.isOpen() {
+		return ErrPoolClosed
+	}
+	if p.quarantined() {
+		return ErrPoolQuarantined
+	}
+
+	return p.dispatch(context.Background(), time.Now().Add(timeout), p.invocation(args))
+}
+
+// InvokeContext 提交参数到固定函数执行，阻塞等待空闲 worker 时可以通过 ctx 取消
+// ctx 只在需要等待时生效：等待期间被取消时返回 ctx.Err()，参数不会被处理；
+// 能够立即取得 worker 时直接提交。开始执行后 ctx 不再影响执行。非阻塞模式下行为与 Invoke 相同。
+func (p *PoolWithFunc) InvokeContext(ctx context.Context, args interface{}) error {
+	// 检查池是否已关闭
+	if !p.isOpen() {
+		return ErrPoolClosed
+	}
+	if p.quarantined() {
+		return ErrPoolQuarantined
+	}
+
+	return p.dispatch(ctx, time.Time{}, p.invocation(args))
+}
+
+// dispatch 记录一个新提交的调用，获取一个 worker 并将调用投递给它
+func (p *PoolWithFunc) dispatch(ctx context.Context, deadline time.Time, inv invocation) error {
+	if err := p.admit(1); err != nil {
+		traceRejected(p.options, inv.id, err)
+		return err
+	}
+	if err := p.acquireBudget(ctx, deadline); err != nil {
+		p.settle(1)
+		traceRejected(p.options, inv.id, err)
+		return err
+	}
+	inv.budgeted = p.options.Budget != nil
+	if err := p.deliver(ctx, deadline, inv); err != nil {
+		p.releaseBudget(inv)
+		p.settle(1)
+		return err
+	}
+	return nil
+}
+
+// acquireBudget 为一个已计入 inflight 的调用占用共享预算，未设置 Budget 时直接返回
+// 预算用尽时阻塞模式等待（遵守 ctx 和 deadline），非阻塞模式记录一次拒绝并返回 ErrPoolOverload。
+func (p *PoolWithFunc) acquireBudget(ctx context.Context, deadline time.Time) error {
+	b := p.options.Budget
+	if b == nil {
 		return nil
 	}
+	err := b.acquire(ctx, deadline, p.options.Nonblocking, 1)
+	if err == ErrPoolOverload {
+		p.reject()
+	}
+	return err
+}
+
+// releaseBudget 归还调用在提交时占用的预算名额
+func (p *PoolWithFunc) releaseBudget(inv invocation) {
+	if inv.budgeted {
+		p.options.Budget.release(1)
+	}
+}
 
+// deliver 获取一个 worker 并将已计入 inflight 的调用投递给它
+// 设置了任务队列时先尝试放入队列；池已饱和时尝试在溢出 worker 上执行，
+// 仍无法执行时返回 ErrPoolOverload。
+func (p *PoolWithFunc) deliver(ctx context.Context, deadline time.Time, inv invocation) error {
+	if p.options.TaskQueue != nil {
+		if ok, err := p.enqueue(inv); ok || err != nil {
+			return err
+		}
+	}
+
+	w, err := p.acquireWorker(ctx, deadline)
+	if err != nil {
+		if err == ErrPoolOverload {
+			// 池已饱和，尝试在溢出 worker 上执行
+			if p.spill(inv) {
+				return nil
+			}
+			err = p.overload()
+		}
+		traceRejected(p.options, inv.id, err)
+		return err
+	}
+
+	p.metrics.submitted.Add(1)
+	w.args <- inv
+	return nil
+}
+
+// settle 扣减 n 个已结束或未被接受的调用，inflight 降为 0 时唤醒 Wait 和 Drain
+func (p *PoolWithFunc) settle(n int64) {
+	if p.inflight.Add(-n) <= 0 {
+		p.quiet.notify()
+	}
+}
+
+// admit 记录 n 个新提交的调用，池正在排空时拒绝并返回 ErrDraining
+// 先增加计数再检查排空标记，Drain 要么看到这些调用，要么使它们被拒绝。
+func (p *PoolWithFunc) admit(n int) error {
+	p.inflight.Add(int64(n))
+	if atomic.LoadInt32(&p.draining) == 1 {
+		p.settle(int64(n))
+		return ErrDraining
+	}
+	return nil
+}
+
+// InvokeBatch 批量提交参数到固定函数执行
+// 在一次加锁内取出尽可能多的空闲 worker 并预留可新建的 worker 名额，
+// 减少高吞吐写入时每个参数的加锁开销。无法立即分配的剩余参数按 Invoke
+// 的规则逐个提交：阻塞模式下等待空闲 worker，非阻塞模式下返回 ErrPoolOverload。
+// 设置了 Budget 时所有参数都按 Invoke 逐个提交。
+// 返回成功提交的参数个数，出错时 args[submitted:] 未被提交。
+func (p *PoolWithFunc) InvokeBatch(args []interface{}) (submitted int, err error) {
+	// 检查池是否已关闭
+	if !p.isOpen() {
+		return 0, ErrPoolClosed
+	}
+	if p.quarantined() {
+		return 0, ErrPoolQuarantined
+	}
+
+	// 共享预算需要逐个参数占用名额，按 Invoke 逐个提交
+	if p.options.Budget != nil {
+		for _, arg := range args {
+			if err := p.dispatch(context.Background(), time.Time{}, p.invocation(arg)); err != nil {
+				return submitted, err
+			}
+			submitted++
+		}
+		return submitted, nil
+	}
+
+	if err := p.admit(len(args)); err != nil {
+		return 0, err
+	}
+
+	workers := p.acquireWorkers(len(args))
+	for i, w := range workers {
+		w.args <- p.invocation(args[i])
+	}
+	submitted = len(workers)
+	p.metrics.submitted.Add(int64(submitted))
+
+	// 剩余参数逐个提交
+	for _, arg := range args[submitted:] {
+		if err := p.deliver(context.Background(), time.Time{}, p.invocation(arg)); err != nil {
+			p.settle(int64(len(args) - submitted))
+			return submitted, err
+		}
+		submitted++
+	}
+
+	return submitted, nil
+}
+
+// acquireWorkers 在一次加锁内取出至多 n 个空闲 worker，并预留可新建的 worker 名额
+// 取出的 worker 不经过 Signal 唤醒等待者，返回的 worker 数量可能少于 n，池已暂停时不取出任何 worker。
+func (p *PoolWithFunc) acquireWorkers(n int) []*goWorkerWithFunc {
+	if p.IsPaused() {
+		return nil
+	}
+
+	p.lock.Lock()
+	workers := make([]*goWorkerWithFunc, 0, n)
+	for len(workers) < n {
+		w := p.detach()
+		if w == nil {
+			break
+		}
+		workers = append(workers, w)
+	}
+
+	// 预留新建 worker 的名额
+	spawn := reserveWorkers(&p.running, &p.capacity, n-len(workers))
+	p.lock.Unlock()
+
+	for i := 0; i < spawn; i++ {
+		workers = append(workers, p.spawnWorker())
+	}
+	return workers
+}
+
+// spill 在一个临时的溢出 worker 上执行调用
+// 溢出 worker 只执行这一次调用，同样受 panic 恢复保护，数量不超过 SpilloverLimit；
+// 达到上限或池已关闭时返回 false。
+func (p *PoolWithFunc) spill(inv invocation) bool {
+	if !p.isOpen() || p.IsPaused() || !acquireSpill(&p.spilling, p.options.SpilloverLimit) {
+		return false
+	}
+
+	w := p.newWorker()
+	w.spill = true
+	w.run()
+
+	p.metrics.submitted.Add(1)
+	p.metrics.spilled.Add(1)
+	w.args <- inv
+	return true
+}
+
+// invocation 创建一次调用，需要记录任务元数据或追踪调用时带上提交时间
+func (p *PoolWithFunc) invocation(args interface{}) invocation {
+	inv := invocation{args: args, id: p.tracer.submitted(p.options)}
+	if inv.id != 0 || p.trackTasks {
+		inv.submitted = time.Now()
+	}
+	return inv
+}
+
+// quarantined 检查池是否处于 panic 隔离状态，处于隔离时计为一次拒绝
+func (p *PoolWithFunc) quarantined() bool {
+	until := p.quarantinedUntil.Load()
+	if until == 0 || time.Now().UnixNano() >= until {
+		return false
+	}
+	p.metrics.rejected.Add(1)
+	p.events.emit(p.options, Event{Type: TaskRejected, Err: ErrPoolQuarantined})
+	return true
+}
+
+// recordPanic 记录一次固定函数 panic，连续次数达到阈值时进入隔离状态
+func (p *PoolWithFunc) recordPanic() {
+	threshold := p.options.QuarantineThreshold
+	if threshold <= 0 {
+		return
+	}
+	if int(p.consecutivePanics.Add(1)) != threshold {
+		return
+	}
+	p.consecutivePanics.Store(0)
+
+	until := time.Now().Add(p.options.QuarantineCooldown)
+	p.quarantinedUntil.Store(until.UnixNano())
+
+	if p.options.OnQuarantine != nil {
+		p.options.OnQuarantine(QuarantineInfo{
+			Pool:   p.options.Name,
+			Panics: threshold,
+			Until:  until,
+		})
+	}
+	p.options.logEvent(LevelWarn, "pool_quarantined", Field{"panics", threshold}, Field{"until", until})
+	p.events.emit(p.options, Event{Type: PoolQuarantined})
+}
+
+// reject 记录一次因过载被拒绝的提交，并调用过载回调
+func (p *PoolWithFunc) reject() {
+	p.metrics.rejected.Add(1)
+	p.events.emit(p.options, Event{Type: TaskRejected, Err: ErrPoolOverload})
+
+	if p.options.OverloadHandler != nil {
+		p.options.OverloadHandler(OverloadInfo{
+			Pool:    p.options.Name,
+			Running: p.Running(),
+			Waiting: p.Waiting(),
+			Cap:     p.Cap(),
+		})
+	}
+}
+
+// overload 记录一次因池已满被拒绝的调用并返回 ErrPoolOverload
+// 池在取得 worker 失败之后被关闭时返回 ErrPoolClosed，关闭不是过载，不计入被拒绝的调用数。
+func (p *PoolWithFunc) overload() error {
+	if !p.isOpen() {
+		return ErrPoolClosed
+	}
+	p.reject()
 	return ErrPoolOverload
 }
 
@@ -181,10 +662,19 @@ func (p *PoolWithFunc) Running() int {
 }
 
 // Free 返回当前空闲的 worker 数量
+// 无限容量的池返回 -1，与 Cap 一致，表示提交总能立即获得 worker；
+// 需要空闲 worker 的实际数量时使用 IdleWorkers。
+// 读取原子计数，不获取池的锁，适合频繁采集指标。
 func (p *PoolWithFunc) Free() int {
-	p.lock.Lock()
-	defer p.lock.Unlock()
-	return p.workers.len()
+	if p.Cap() == -1 {
+		return -1
+	}
+	return p.IdleWorkers()
+}
+
+// IdleWorkers 返回空闲队列中 worker 的数量，对无限容量的池同样返回实际数量
+func (p *PoolWithFunc) IdleWorkers() int {
+	return int(atomic.LoadInt32(&p.free)) + p.idle.len()
 }
 
 // Cap 返回池的容量
@@ -193,8 +683,56 @@ func (p *PoolWithFunc) Cap() int {
 }
 
 // Waiting 返回等待执行的任务数量
+// 包括任务队列中的参数和阻塞等待 worker 的提交者。
 func (p *PoolWithFunc) Waiting() int {
-	return int(atomic.LoadInt32(&p.waiting))
+	n := p.BlockedSubmitters()
+	if q := p.options.TaskQueue; q != nil {
+		n += q.Len()
+	}
+	return n
+}
+
+// BlockedSubmitters 返回阻塞等待 worker 的提交者数量，不包括排队的任务
+// 作为分片池的子池时，还包括选中本池、正在等待任意子池空出 worker 的提交者。
+func (p *PoolWithFunc) BlockedSubmitters() int {
+	return int(p.waiting.Load()) + int(p.thieving.Load())
+}
+
+// Name 返回池的名称
+func (p *PoolWithFunc) Name() string {
+	return p.options.Name
+}
+
+// Options 返回池创建时实际生效的配置的副本
+// 包含默认值，修改返回值不会影响池。
+func (p *PoolWithFunc) Options() Options {
+	return p.options.clone()
+}
+
+// Events 返回池的事件 channel，外部系统可以据此响应池的行为而不必轮询
+// 第一次调用时创建 channel，之前发生的事件不会被记录；多次调用返回同一个 channel，
+// 多个消费者会分摊事件。缓冲大小由 WithEventBuffer 设置，缓冲已满时新的事件被丢弃，
+// 丢弃的数量可以通过 DroppedEvents 获取，池不会因消费者缓慢而阻塞。
+// channel 在池关闭后也不会被关闭，Reboot 后继续使用。
+func (p *PoolWithFunc) Events() <-chan Event {
+	return p.events.channel(p.options.EventBuffer)
+}
+
+// DroppedEvents 返回因事件 channel 缓冲已满被丢弃的事件数量
+func (p *PoolWithFunc) DroppedEvents() uint64 {
+	return p.events.dropped.Load()
+}
+
+// SetTrace 在运行时开启或关闭逐个调用的追踪日志
+// 开启后每个调用的提交、派发和完成都以 LevelDebug 记录一条带有调用 ID 和耗时的事件，
+// 用于排查"调用去哪了"一类的问题。只影响之后提交的调用。
+func (p *PoolWithFunc) SetTrace(enabled bool) {
+	p.tracer.enabled.Store(enabled)
+}
+
+// Tracing 返回是否开启了追踪日志
+func (p *PoolWithFunc) Tracing() bool {
+	return p.tracer.enabled.Load()
 }
 
 // IsClosed 返回池是否已关闭
@@ -202,200 +740,677 @@ func (p *PoolWithFunc) IsClosed() bool {
 	return atomic.LoadInt32(&p.state) == CLOSED
 }
 
+// IsClosing 返回池是否正在关闭
+// Release、ReleaseTimeout 开始关闭到清理完成之间，以及 Drain 排空期间返回 true：
+// 此时池已经拒绝新的任务，但仍有清理或任务没有完成。完全关闭后返回 false，IsClosed 返回 true。
+func (p *PoolWithFunc) IsClosing() bool {
+	return atomic.LoadInt32(&p.state) == CLOSING || (p.isOpen() && atomic.LoadInt32(&p.draining) == 1)
+}
+
+// isOpen 返回池是否处于 OPENED 状态，CLOSING 和 CLOSED 状态下都拒绝新的任务
+func (p *PoolWithFunc) isOpen() bool {
+	return atomic.LoadInt32(&p.state) == OPENED
+}
+
+// Pause 暂停向 worker 分配新的调用
+// 暂停期间池不会被关闭，正在执行的调用不受影响：阻塞模式下的提交等待 Resume，
+// 非阻塞模式下的提交返回 ErrPoolOverload。已暂停时不做任何事。
+func (p *PoolWithFunc) Pause() {
+	atomic.StoreInt32(&p.paused, 1)
+}
+
+// Resume 恢复分配调用，并唤醒所有在暂停期间阻塞的提交者
+// 未暂停时不做任何事。
+func (p *PoolWithFunc) Resume() {
+	if !atomic.CompareAndSwapInt32(&p.paused, 1, 0) {
+		return
+	}
+
+	p.lock.Lock()
+	p.waiters.broadcast()
+	p.lock.Unlock()
+	p.thieves.broadcast()
+	p.drainQueue()
+}
+
+// IsPaused 返回池是否已暂停
+func (p *PoolWithFunc) IsPaused() bool {
+	return atomic.LoadInt32(&p.paused) == 1
+}
+
+// popIdle 从分片的空闲缓存获取一个 worker，池已暂停时返回 nil
+func (p *PoolWithFunc) popIdle() *goWorkerWithFunc {
+	if p.IsPaused() {
+		return nil
+	}
+	return p.idle.pop()
+}
+
+// Stats 返回池的运行状态快照，包括当前的 worker 数量和累计的任务计数
+func (p *PoolWithFunc) Stats() Stats {
+	s := Stats{
+		Running:  p.Running(),
+		Free:     p.Free(),
+		Idle:     p.IdleWorkers(),
+		Cap:      p.Cap(),
+		Waiting:  p.Waiting(),
+		Spilling: int(atomic.LoadInt32(&p.spilling)),
+	}
+	p.metrics.fill(&s)
+	return s
+}
+
 // Release 优雅关闭池，等待所有任务完成
 func (p *PoolWithFunc) Release() {
-	// 标记池为关闭状态
-	if !atomic.CompareAndSwapInt32(&p.state, OPENED, CLOSED) {
+	// 标记池为正在关闭状态，清理完成后才标记为已关闭
+	stop := p.beginClose()
+	if stop == nil {
 		return
 	}
+	p.cancelContext()
 
-	// 停止清理 goroutine
-	close(p.stopCleaning)
-	<-p.cleaningDone
+	// 停止清理 goroutine 和看门狗
+	stop()
 
 	p.lock.Lock()
 	// 关闭所有空闲的 worker
 	p.workers.reset()
-	p.lock.Unlock()
+	atomic.StoreInt32(&p.free, 0)
+	p.idle.reset()
 
 	// 唤醒所有等待的 goroutine
-	p.cond.Broadcast()
+	p.waiters.broadcast()
+	p.lock.Unlock()
+	p.thieves.broadcast()
+
+	p.endClose()
+	p.options.logReleased(p.outstanding(), 0, nil)
+
+	// 检查 worker 是否全部退出
+	p.checkLeaks()
 }
 
 // ReleaseTimeout 带超时的优雅关闭
+// 关闭池后最多等待 timeout，直到正在执行的任务（包括溢出 worker 上的任务）全部完成。
+// 超时时返回包装了 ErrTimeout 的错误，其中包含仍未完成的任务数量，
+// 这些任务会在后台继续执行完毕。
+// 超时发生在关闭空闲 worker 等清理完成之前时，池保持 CLOSING 状态直到后台清理结束，
+// 期间 Reboot 不做任何事；清理结束后池变为 CLOSED，Reboot 才能重启，不会与清理交错。
 func (p *PoolWithFunc) ReleaseTimeout(timeout time.Duration) error {
-	// 标记池为关闭状态
-	if !atomic.CompareAndSwapInt32(&p.state, OPENED, CLOSED) {
+	// 标记池为正在关闭状态，清理完成且等待结束后才标记为已关闭
+	stop := p.beginClose()
+	if stop == nil {
 		return ErrPoolClosed
 	}
+	p.cancelContext()
 
 	// 创建超时定时器
+	deadline := time.Now().Add(timeout)
 	timer := time.NewTimer(timeout)
 	defer timer.Stop()
 
 	// 使用 channel 等待关闭完成或超时
 	done := make(chan struct{})
 	go func() {
-		// 停止清理 goroutine
-		close(p.stopCleaning)
-		<-p.cleaningDone
+		// 停止清理 goroutine 和看门狗
+		stop()
 
 		p.lock.Lock()
 		p.workers.reset()
+		atomic.StoreInt32(&p.free, 0)
+		p.idle.reset()
+		p.waiters.broadcast()
 		p.lock.Unlock()
+		p.thieves.broadcast()
 
-		p.cond.Broadcast()
+		p.checkLeaks()
 		close(done)
 	}()
 
-	// 等待完成或超时
+	// 等待清理完成或超时
 	select {
 	case <-done:
-		return nil
 	case <-timer.C:
-		return ErrTimeout
+		// 清理完成后再标记为已关闭，避免 Reboot 与仍在进行的清理竞争
+		go func() {
+			<-done
+			p.endClose()
+		}()
+		running := p.outstanding()
+		err := timeoutError(running)
+		p.options.logReleased(running, timeout, err)
+		return p.options.nameError(err)
+	}
+
+	// 等待正在执行的任务完成
+	err := waitOutstanding(&p.quiet, deadline, p.outstanding)
+	p.endClose()
+	p.options.logReleased(p.outstanding(), timeout, err)
+	return p.options.nameError(err)
+}
+
+// beginClose 在持有锁时将池从 OPENED 切换到 CLOSING
+// 返回停止本代清理 goroutine 和看门狗的函数，池不处于 OPENED 状态时返回 nil。
+// 池在同一临界区内从注册表移除并取出清理 goroutine 和看门狗，Reboot 同样在持有锁时
+// 切换状态、替换它们并重新注册，关闭的总是切换前这一代的资源。
+func (p *PoolWithFunc) beginClose() func() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if !atomic.CompareAndSwapInt32(&p.state, OPENED, CLOSING) {
+		return nil
+	}
+	unregister(p)
+
+	stopCleaning, cleaningDone, wd := p.stopCleaning, p.cleaningDone, p.watchdog
+	return func() {
+		stopCleaner(stopCleaning, cleaningDone)
+		wd.close()
+	}
+}
+
+// endClose 在持有锁时将池从 CLOSING 切换到 CLOSED，此后 Reboot 才能重启池
+func (p *PoolWithFunc) endClose() {
+	p.lock.Lock()
+	atomic.StoreInt32(&p.state, CLOSED)
+	p.lock.Unlock()
+	p.events.emit(p.options, Event{Type: PoolClosed})
+}
+
+// outstanding 返回仍在执行任务的 worker 数量，包括溢出 worker
+func (p *PoolWithFunc) outstanding() int {
+	return int(atomic.LoadInt32(&p.running) + atomic.LoadInt32(&p.spilling))
+}
+
+// quiesce 在 worker 启动和退出后调用，唤醒 ReleaseTimeout 和 WaitRunning 重新检查运行计数
+func (p *PoolWithFunc) quiesce() {
+	p.quiet.notify()
+}
+
+// checkLeaks 启用了 LeakCheckGrace 时在后台检查关闭后是否有 worker 未退出
+func (p *PoolWithFunc) checkLeaks() {
+	checkLeaks(p.options, &p.quiet, &p.live, p.outstanding, p.isOpen)
+}
+
+// Drain 排空并关闭池
+// 调用后池立即停止接受新的调用，排空期间的提交返回 ErrDraining；
+// 已接受的调用（包括正在阻塞等待 worker 的提交）会继续执行，
+// 全部执行完毕后关闭池并返回 nil。ctx 在此之前被取消时返回 ctx.Err()，池保持排空状态。
+// 池已关闭时返回 ErrPoolClosed。
+func (p *PoolWithFunc) Drain(ctx context.Context) error {
+	if !p.isOpen() {
+		return ErrPoolClosed
+	}
+
+	atomic.StoreInt32(&p.draining, 1)
+	if err := waitInflight(ctx, &p.quiet, &p.inflight); err != nil {
+		return err
 	}
+
+	p.Release()
+	return nil
+}
+
+// Tune 调整池的容量
+// 对无限容量的池、size 小于等于 0、超过 math.MaxInt32 或与当前容量相同时不做任何事。
+// 缩容时不会打断正在执行的任务，多出的 worker 在执行完当前任务后退出。
+// 扩容时唤醒所有阻塞等待的调用方，让它们使用新增的容量。
+func (p *PoolWithFunc) Tune(size int) {
+	capacity := p.Cap()
+	if capacity == -1 || size <= 0 || size > maxPoolSize || size == capacity {
+		return
+	}
+
+	var lost error
+	p.lock.Lock()
+	atomic.StoreInt32(&p.capacity, int32(size))
+
+	// 循环队列的大小固定，扩容时换成更大的队列，避免归还的 worker 放不下
+	if q, ok := p.workers.(*loopQueueWithFunc); ok && size > q.size {
+		grown := newWorkerLoopQueueWithFunc(size)
+		for w := q.detach(); w != nil; w = q.detach() {
+			if err := grown.insert(w); err != nil {
+				// 放不下的 worker 直接结束，不能让它脱离队列后一直等待任务
+				w.finish()
+				atomic.AddInt32(&p.free, -1)
+				lost = err
+			}
+		}
+		p.workers = grown
+	}
+
+	// 扩容后一次唤醒所有等待者，使它们在新增的容量上创建 worker
+	if size > capacity {
+		p.waiters.broadcast()
+	}
+	p.lock.Unlock()
+	if size > capacity {
+		p.thieves.broadcast()
+	}
+
+	if lost != nil {
+		p.options.reportError(fmt.Errorf("%w: move idle worker on tune: %w", ErrWorkerQueue, lost))
+	}
+
+	p.options.logEvent(LevelInfo, "pool_tuned", Field{"old_cap", capacity}, Field{"new_cap", size})
+	p.events.emit(p.options, Event{Type: PoolTuned, OldCap: capacity, Cap: size})
+	if size > capacity {
+		p.drainQueue()
+	}
+}
+
+// Wait 阻塞直到池中没有正在执行和等待 worker 的调用
+// 适合批量作业在提交完所有调用后等待池空闲，不必为每次提交维护 WaitGroup。
+// 等待期间仍可以提交新的调用，它们同样会被等待。
+func (p *PoolWithFunc) Wait() {
+	_ = waitInflight(context.Background(), &p.quiet, &p.inflight)
+}
+
+// WaitWithTimeout 与 Wait 相同，但最多等待 timeout
+// 超时时返回包装了 ErrTimeout 的错误，错误信息中包含未完成的调用数量。
+func (p *PoolWithFunc) WaitWithTimeout(timeout time.Duration) error {
+	return waitOutstanding(&p.quiet, time.Now().Add(timeout), func() int {
+		return int(p.inflight.Load())
+	})
+}
+
+// WaitRunning 阻塞直到池中运行的 worker 数量变为 n，不包括溢出 worker
+// worker 的创建和回收是异步的，适合在 Tune、PurgeNow 或过期回收之后等待池到达预期的规模。
+// ctx 被取消时返回 ctx.Err()。
+func (p *PoolWithFunc) WaitRunning(ctx context.Context, n int) error {
+	_, err := p.quiet.await(ctx, time.Time{}, func() int {
+		if p.Running() != n {
+			return 1
+		}
+		return 0
+	})
+	return err
 }
 
 // Reboot 重启已关闭的池
+// 只对完全关闭（CLOSED）的池生效，池正在关闭（IsClosing）时不做任何事，
+// 避免与仍在进行的清理竞争。
 func (p *PoolWithFunc) Reboot() {
-	if atomic.CompareAndSwapInt32(&p.state, CLOSED, OPENED) {
-		// 重新创建清理相关的 channel
-		p.stopCleaning = make(chan struct{})
-		p.cleaningDone = make(chan struct{})
-		// 重启清理 goroutine
-		go p.cleanExpiredWorkers()
+	// 状态切换、替换清理 goroutine 和看门狗以及重新注册在同一临界区内完成，
+	// 不会与并发的 Release 交错
+	p.lock.Lock()
+	if !atomic.CompareAndSwapInt32(&p.state, CLOSED, OPENED) {
+		p.lock.Unlock()
+		return
 	}
+
+	atomic.StoreInt32(&p.paused, 0)
+	atomic.StoreInt32(&p.draining, 0)
+	// 为使用上下文的池创建新的上下文
+	if p.ctx.Load() != nil {
+		p.startContext()
+	}
+
+	// 重启清理 goroutine 和看门狗
+	p.startCleaning()
+	p.watchdog = startWatchdog(p.options, &p.live)
+	register(p)
+	p.lock.Unlock()
+
+	p.options.logEvent(LevelInfo, "pool_rebooted", Field{"cap", p.Cap()})
+	p.events.emit(p.options, Event{Type: PoolRebooted, Cap: p.Cap()})
+	p.drainQueue()
 }
 
-// getWorker 获取一个可用的 worker
-// 优化：最小化锁持有时间，使用 atomic 操作避免不必要的锁
-func (p *PoolWithFunc) getWorker() *goWorkerWithFunc {
-	var w *goWorkerWithFunc
+// SubscribeStats 订阅池的状态快照，每隔 interval 推送一次
+// interval 小于等于 0 时使用 DefaultStatsInterval。
+// 消费者处理不及时时只保留最新的快照。不再需要时必须调用返回的
+// unsubscribe 函数，否则推送 goroutine 会一直存在。
+func (p *PoolWithFunc) SubscribeStats(interval time.Duration) (<-chan Stats, func()) {
+	return subscribeStats(interval, p.Stats)
+}
 
+// RecentPanics 返回最近发生的 panic 记录，按时间从旧到新排列
+func (p *PoolWithFunc) RecentPanics() []PanicRecord {
+	return p.panics.snapshot()
+}
+
+// Workers 返回当前空闲 worker 的存活时长和空闲时长
+func (p *PoolWithFunc) Workers() []WorkerInfo {
 	p.lock.Lock()
+	// 持有锁之后再取当前时间，空闲队列中 worker 的最后使用时间都不会晚于 now
+	now := p.options.clock().Now()
+	infos := make([]WorkerInfo, 0, p.workers.len()+p.idle.len())
+	collect := func(w *goWorkerWithFunc) {
+		infos = append(infos, newWorkerInfo(w.id, w.created, w.idleSince(), now))
+	}
+	p.workers.each(collect)
+	p.idle.each(collect)
+	p.lock.Unlock()
 
-	// 尝试从队列中获取空闲 worker
-	w = p.workers.detach()
+	return infos
+}
 
-	if w != nil {
-		// 找到空闲 worker，立即释放锁以减少锁持有时间
-		p.lock.Unlock()
-		return w
+// DumpStacks 返回所有正在执行任务的 worker 的 goroutine 栈
+// 用于在生产环境中定位挂起的任务，而不必导出全部 goroutine。
+// 获取栈需要短暂地暂停所有 goroutine，不宜频繁调用。
+// 需要启用 WithStackDumps、WithWatchdog 或 WithLeakCheck，否则返回 nil。
+func (p *PoolWithFunc) DumpStacks() []WorkerStack {
+	now := time.Now().UnixNano()
+	return dumpStacks(p.live.busy(now, 0), now)
+}
+
+// PurgeNow 立即回收所有空闲的 worker，返回被回收的 worker 数量
+// 不必等待过期清理 goroutine 的下一次扫描，适合在流量高峰过后或
+// 对 GC 敏感的阶段之前主动释放资源。正在执行任务的 worker 不受影响。
+// 运行计数由 worker goroutine 退出时自行扣减。
+func (p *PoolWithFunc) PurgeNow() int {
+	if !p.isOpen() {
+		return 0
 	}
 
-	// 检查是否可以创建新的 worker（使用 atomic 读取避免额外的锁）
-	capacity := atomic.LoadInt32(&p.capacity)
-	running := atomic.LoadInt32(&p.running)
+	p.lock.Lock()
+	n := p.workers.len() + p.idle.len()
+	p.workers.reset()
+	atomic.StoreInt32(&p.free, 0)
+	p.idle.reset()
+	p.lock.Unlock()
 
-	if capacity == -1 || running < capacity {
-		// 可以创建新 worker，先释放锁
-		p.lock.Unlock()
+	if n > 0 {
+		p.options.logEvent(LevelInfo, "workers_purged", Field{"count", n})
+	}
 
-		// 从对象池获取 worker 对象以复用
-		w = p.workerPool.Get().(*goWorkerWithFunc)
+	return n
+}
 
-		// 重置 worker 状态
-		atomic.StoreInt32(&w.recycled, 0)
-		w.lastUsed = time.Now()
+// acquireWorker 获取一个可用的 worker
+// 阻塞模式下池满时等待，直到有 worker 可用、ctx 被取消或到达 deadline；deadline 为零值时不超时。
+// 等待的提交数已达 MaxBlockingTasks 时不等待，返回 ErrPoolOverload。
+// 优化：最小化锁持有时间，使用 atomic 操作避免不必要的锁
+func (p *PoolWithFunc) acquireWorker(ctx context.Context, deadline time.Time) (*goWorkerWithFunc, error) {
+	// 启用分片锁时先从分片的空闲缓存获取，不获取池的锁
+	if w := p.popIdle(); w != nil {
+		return w, nil
+	}
 
-		// 增加运行计数
-		atomic.AddInt32(&p.running, 1)
+	var wt *waiter
 
-		// 启动 worker
-		w.run()
+	p.lock.Lock()
+	for {
+		// 池已暂停时不分配 worker，按池已满处理
+		if !p.IsPaused() {
+			// 尝试从队列中获取空闲 worker
+			if w := p.detach(); w != nil {
+				// 找到空闲 worker，立即释放锁以减少锁持有时间
+				p.lock.Unlock()
+				return w, nil
+			}
 
-		return w
+			// 容量允许时占用一个运行名额并创建新的 worker
+			// 检查和占用是同一次 CAS，并发的提交者不会使运行的 worker 超过容量
+			if reserveWorkers(&p.running, &p.capacity, 1) == 1 {
+				p.lock.Unlock()
+				return p.spawnWorker(), nil
+			}
+		}
+
+		// 池已满或已暂停，先检查池是否已关闭（包括被唤醒后）
+		if !p.isOpen() {
+			p.lock.Unlock()
+			return nil, ErrPoolClosed
+		}
+
+		// 非阻塞模式直接返回
+		if p.options.Nonblocking {
+			p.lock.Unlock()
+			return nil, ErrPoolOverload
+		}
+
+		// 等待的提交数已达 MaxBlockingTasks 上限时不再等待，按池已满处理
+		// 只在开始等待前检查，被唤醒后重试的提交不会因为之后到达的提交被拒绝
+		if wt == nil && p.options.blockingFull(p.waiting.Load()) {
+			p.lock.Unlock()
+			return nil, ErrPoolOverload
+		}
+
+		// 阻塞模式，等待 worker 可用后重试
+		// 增加等待计数后再检查一次分片缓存，避免错过在此之前放入缓存的 worker
+		p.waiting.Add(1)
+		if w := p.popIdle(); w != nil {
+			p.waiting.Add(-1)
+			p.lock.Unlock()
+			return w, nil
+		}
+		if wt == nil {
+			wt = newWaiter()
+		}
+		err := p.waiters.wait(ctx, wt, p.lock, deadline)
+		p.waiting.Add(-1)
+		if err != nil {
+			p.lock.Unlock()
+			return nil, err
+		}
 	}
+}
 
-	// 池已满
-	if p.options.Nonblocking {
-		// 非阻塞模式，直接返回 nil
-		p.lock.Unlock()
+// tryGetWorker 不等待地获取一个可用的 worker，池已满时返回 nil
+// 用于分片池在子池之间窃取空闲容量，不受 Nonblocking 配置影响。
+func (p *PoolWithFunc) tryGetWorker() *goWorkerWithFunc {
+	// 池已暂停时不分配 worker
+	if p.IsPaused() {
 		return nil
 	}
 
-	// 阻塞模式，等待 worker 可用
-	atomic.AddInt32(&p.waiting, 1)
-	p.cond.Wait()
-	atomic.AddInt32(&p.waiting, -1)
+	if w := p.idle.pop(); w != nil {
+		return w
+	}
 
-	// 被唤醒后，检查池是否已关闭
-	if atomic.LoadInt32(&p.state) == CLOSED {
+	p.lock.Lock()
+	if w := p.detach(); w != nil {
 		p.lock.Unlock()
-		return nil
+		return w
 	}
 
-	// 再次尝试获取 worker
-	w = p.workers.detach()
 	p.lock.Unlock()
 
+	if reserveWorkers(&p.running, &p.capacity, 1) == 1 {
+		return p.spawnWorker()
+	}
+	return nil
+}
+
+// tryInvoke 在不等待的情况下提交参数，池已满时返回 false
+func (p *PoolWithFunc) tryInvoke(args interface{}) (bool, error) {
+	if !p.isOpen() {
+		return false, ErrPoolClosed
+	}
+	if p.quarantined() {
+		return false, ErrPoolQuarantined
+	}
+
+	if err := p.admit(1); err != nil {
+		return false, err
+	}
+	inv := p.invocation(args)
+	if b := p.options.Budget; b != nil {
+		if b.acquire(context.Background(), time.Time{}, true, 1) != nil {
+			p.settle(1)
+			return false, nil
+		}
+		inv.budgeted = true
+	}
+
+	w := p.tryGetWorker()
+	if w == nil {
+		p.releaseBudget(inv)
+		p.settle(1)
+		return false, nil
+	}
+
+	p.metrics.submitted.Add(1)
+	w.args <- inv
+	return true, nil
+}
+
+// spawnWorker 从对象池获取一个 worker 并启动它
+// 调用方负责事先增加运行计数
+func (p *PoolWithFunc) spawnWorker() *goWorkerWithFunc {
+	w := p.newWorker()
+
+	// 启动 worker
+	w.run()
+
 	return w
 }
 
+// newWorker 从对象池获取 worker 对象以复用，并重置其状态
+func (p *PoolWithFunc) newWorker() *goWorkerWithFunc {
+	w := p.workerPool.Get().(*goWorkerWithFunc)
+
+	atomic.StoreInt32(&w.recycled, 0)
+	atomic.StoreInt32(&w.expired, 0)
+	w.spill = false
+	w.retired = false
+	w.id = int(atomic.AddInt64(&p.workerSeq, 1))
+	w.created = p.options.clock().Now()
+	w.lastUsed.Store(w.created.UnixNano())
+
+	return w
+}
+
+// detach 从空闲队列或分片的空闲缓存中取出一个 worker，调用方必须持有 p.lock
+func (p *PoolWithFunc) detach() *goWorkerWithFunc {
+	if w := p.workers.detach(); w != nil {
+		atomic.AddInt32(&p.free, -1)
+		return w
+	}
+	return p.idle.pop()
+}
+
 // putWorker 将 worker 放回池中
 // 优化：在锁外更新时间戳，减少锁持有时间
 func (p *PoolWithFunc) putWorker(worker *goWorkerWithFunc) bool {
 	// 使用 atomic 检查池状态，避免不必要的锁
-	if atomic.LoadInt32(&p.state) == CLOSED {
+	if !p.isOpen() {
+		return false
+	}
+
+	// 缩容后运行的 worker 超过容量时，让多出的 worker 退出，运行计数在此扣减
+	if retireWorker(&p.running, &p.capacity) {
+		worker.retired = true
 		return false
 	}
 
 	// 更新 worker 的最后使用时间（在锁外执行）
-	worker.lastUsed = time.Now()
+	worker.updateLastUsed()
+
+	// 启用分片锁且没有等待者时放入分片的空闲缓存，不获取池的锁
+	if p.idle.enabled() && p.waiting.Load() == 0 {
+		p.idle.push(worker)
+		p.afterIdlePush()
+		return true
+	}
 
 	p.lock.Lock()
 
-	// 将 worker 放回队列
+	// 持有锁后再检查一次：Release 先标记关闭再加锁清空队列，
+	// 在它之后放回的 worker 不会再被结束，只能让它自己退出
+	if !p.isOpen() {
+		p.lock.Unlock()
+		return false
+	}
+
+	// 将 worker 放回队列，放不回时让 worker 退出
 	if err := p.workers.insert(worker); err != nil {
 		p.lock.Unlock()
+		p.options.reportError(fmt.Errorf("%w: insert idle worker: %w", ErrWorkerQueue, err))
 		return false
 	}
+	atomic.AddInt32(&p.free, 1)
 
 	// 只在有等待的 goroutine 时才唤醒
 	// 优化：减少不必要的 Signal 调用
-	if atomic.LoadInt32(&p.waiting) > 0 {
-		p.cond.Signal()
+	signaled := false
+	if p.waiting.Load() > 0 {
+		signaled = p.waiters.signal()
 	}
 	p.lock.Unlock()
 
+	// 没有本池的等待者时，空闲的 worker 交给其他子池积压的提交
+	if !signaled {
+		p.thieves.notify()
+	}
+
 	return true
 }
 
+// signal 唤醒最早开始等待 worker 的一个提交者
+// 用于 worker 退出或归还容量后，等待者可以在空出的容量上创建新的 worker。
+func (p *PoolWithFunc) signal() {
+	p.lock.Lock()
+	signaled := p.waiters.signal()
+	p.lock.Unlock()
+	if !signaled {
+		p.thieves.notify()
+	}
+}
+
+// afterIdlePush 处理放入分片缓存期间发生的等待和关闭
+// 等待者在增加等待计数后会再检查一次缓存，这里只需唤醒此后进入等待的调用方；
+// 池在放入期间被关闭时，由这里结束缓存中的 worker。
+func (p *PoolWithFunc) afterIdlePush() {
+	if p.waiting.Load() > 0 {
+		p.signal()
+	} else {
+		p.thieves.notify()
+	}
+	if !p.isOpen() {
+		p.lock.Lock()
+		p.idle.reset()
+		p.lock.Unlock()
+	}
+}
+
+// startCleaning 创建清理相关的 channel 并启动清理 goroutine
+// 如果禁用了清理（DisablePurge），则不创建任何资源
+func (p *PoolWithFunc) startCleaning() {
+	if p.options.DisablePurge {
+		return
+	}
+
+	p.stopCleaning = make(chan struct{})
+	p.cleaningDone = make(chan struct{})
+
+	// 在启动 goroutine 前创建 ticker，池创建或重启返回时扫描周期已从时钟开始计算
+	tick, stopTicker := p.options.clock().NewTicker(p.options.cleanInterval())
+	go p.cleanExpiredWorkers(tick, stopTicker, p.stopCleaning, p.cleaningDone)
+}
+
 // cleanExpiredWorkers 定期清理过期的 worker
-func (p *PoolWithFunc) cleanExpiredWorkers() {
-	ticker := time.NewTicker(p.options.ExpiryDuration)
+// tick 和 stopTicker 是本代的扫描周期，stop 和 done 是本代清理 goroutine 的 channel，
+// 由参数传入而不是读取池的字段，Reboot 替换字段时不影响仍在退出的上一代。
+func (p *PoolWithFunc) cleanExpiredWorkers(tick <-chan time.Time, stopTicker func(), stop <-chan struct{}, done chan<- struct{}) {
 	defer func() {
-		ticker.Stop()
-		close(p.cleaningDone)
+		stopTicker()
+		close(done)
 	}()
 
 	for {
 		select {
-		case <-ticker.C:
+		case <-tick:
 			// 使用 atomic 检查池状态，避免不必要的锁
-			if atomic.LoadInt32(&p.state) == CLOSED {
+			if !p.isOpen() {
 				return
 			}
 
+			// 过期的 worker 只收到退出信号，运行计数由 worker goroutine 退出时自行扣减
+			now := p.options.clock().Now()
 			p.lock.Lock()
-			expiredWorkers := p.workers.refresh(p.options.ExpiryDuration)
+			expiredWorkers := p.workers.refresh(now, p.options.ExpiryDuration)
+			atomic.AddInt32(&p.free, -int32(len(expiredWorkers)))
+			expiredWorkers = append(expiredWorkers, p.idle.refresh(now, p.options.ExpiryDuration)...)
 			p.lock.Unlock()
 
 			// 记录日志（在锁外执行，减少锁持有时间）
-			if len(expiredWorkers) > 0 && p.options.Logger != nil {
-				for _, idx := range expiredWorkers {
-					p.options.Logger.Printf("worker at index %d expired and will be recycled", idx)
-				}
+			for _, w := range expiredWorkers {
+				p.options.logEvent(LevelDebug, "worker_expired", Field{"worker_id", w.id}, Field{"idle_for", w.idleFor})
 			}
 
-			// 减少运行计数（过期的worker已经从队列中移除）
-			n := int32(len(expiredWorkers))
-			if n > 0 {
-				atomic.AddInt32(&p.running, -n)
-			}
-
-		case <-p.stopCleaning:
+		case <-stop:
 			return
 		}
 	}
@@ -407,44 +1422,156 @@ func (w *goWorkerWithFunc) run() {
 	go func() {
 		defer func() {
 			// 减少运行中的 worker 计数
-			atomic.AddInt32(&w.pool.running, -1)
+			if w.spill {
+				if atomic.AddInt32(&w.pool.spilling, -1) < 0 {
+					w.pool.options.reportError(fmt.Errorf("%w: spilling worker count is negative", ErrInvariant))
+				}
+			} else if !w.retired && atomic.AddInt32(&w.pool.running, -1) < 0 {
+				w.pool.options.reportError(fmt.Errorf("%w: running worker count is negative", ErrInvariant))
+			}
+			if w.pool.trackWorkers {
+				w.busySince.Store(0)
+				w.pool.live.remove(&w.workerState)
+			}
 
 			// 处理 panic
 			if p := recover(); p != nil {
-				if w.pool.options.PanicHandler != nil {
-					w.pool.options.PanicHandler(p)
-				} else if w.pool.options.Logger != nil {
-					w.pool.options.Logger.Printf("worker exits from panic: %v", p)
+				stack := debug.Stack()
+
+				info := TaskInfo{Pool: w.pool.options.Name, WorkerID: w.id}
+				if w.pool.trackTasks {
+					w.info.Panic = p
+					if !w.infoEnded {
+						w.infoEnded = true
+						endTask(w.pool.options, &w.pool.metrics, &w.info)
+					}
+					info = w.info
 				}
+				w.pool.recordPanic()
+				reportPanic(w.pool.options, &w.pool.metrics, &w.pool.panics, p, stack, info)
+				w.pool.events.emit(w.pool.options, Event{Type: TaskPanicked, WorkerID: w.id, Value: p})
 			}
 
-			// 通知池 worker 已退出
-			w.pool.cond.Signal()
+			// 调用 worker 生命周期钩子并发出事件
+			if atomic.LoadInt32(&w.expired) == 1 {
+				if w.pool.options.OnWorkerExpire != nil {
+					w.pool.options.OnWorkerExpire(w.id)
+				}
+				w.pool.events.emit(w.pool.options, Event{Type: WorkerExpired, WorkerID: w.id})
+			}
+			if w.pool.options.OnWorkerExit != nil {
+				w.pool.options.OnWorkerExit(w.id)
+			}
+			w.pool.events.emit(w.pool.options, Event{Type: WorkerExited, WorkerID: w.id})
+
+			// 通知池 worker 已退出，空出的容量可以执行队列中的参数
+			w.pool.signal()
+			w.pool.afterPut()
+			w.pool.quiesce()
 		}()
 
+		if w.pool.trackWorkers {
+			w.gid = goroutineID()
+			w.pool.live.add(&w.workerState)
+		}
+		w.pool.quiesce()
+		setWorkerLabels(w.pool.options)
+
+		if w.pool.options.OnWorkerCreate != nil {
+			w.pool.options.OnWorkerCreate(w.id)
+		}
+		w.pool.events.emit(w.pool.options, Event{Type: WorkerCreated, WorkerID: w.id})
+
 		// 主循环：持续接收和执行参数
-		for args := range w.args {
-			if args == nil {
-				// nil 参数表示 worker 应该退出
+		for inv := range w.args {
+			if inv.stop {
+				// 退出信号
 				return
 			}
 
 			// 执行固定函数
-			w.pool.poolFunc(args)
+			w.execute(&inv)
+
+			// 溢出 worker 只执行一次调用
+			if w.spill {
+				return
+			}
+
+			// 继续执行任务队列中的参数，队列为空后再归还
+			for w.pool.nextQueued(&inv) {
+				w.execute(&inv)
+			}
 
 			// 任务完成后，将 worker 放回池中以供复用
 			if ok := w.pool.putWorker(w); !ok {
 				// 如果放回失败（池已关闭），退出循环
 				return
 			}
+			w.pool.afterPut()
 		}
 	}()
 }
 
+// execute 执行一次调用，记录计数器和任务元数据
+// 设置了 Budget 时，调用结束（包括 panic）后归还预算名额；从任务队列取出的调用
+// 提交时没有占用名额，在执行前占用。
+// 固定函数发生 panic 时由 run 中的 recover 负责收尾
+func (w *goWorkerWithFunc) execute(inv *invocation) {
+	p := w.pool
+	defer p.settle(1)
+	if inv.queued {
+		defer p.ack(inv.args)
+	}
+	if b := p.options.Budget; b != nil {
+		if !inv.budgeted {
+			_ = b.acquire(context.Background(), time.Time{}, false, 1)
+		}
+		defer b.release(1)
+	}
+	if p.trackWorkers {
+		w.busySince.Store(time.Now().UnixNano())
+	}
+	// 函数池的调用没有任务名称，TaskInfo.Name 总是为空
+	if p.trackTasks {
+		w.info = beginTask(p.options, &p.metrics, w.id, "", inv.submitted)
+		w.infoEnded = false
+	}
+	panicked := true
+	if inv.id != 0 {
+		start := traceDispatched(p.options, inv.id, w.id, inv.submitted)
+		defer func() { traceCompleted(p.options, inv.id, w.id, start, panicked) }()
+	}
+
+	p.poolFunc(inv.args)
+	if p.options.QuarantineThreshold > 0 {
+		p.consecutivePanics.Store(0)
+	}
+
+	if p.trackWorkers {
+		w.busySince.Store(0)
+	}
+	p.metrics.completed.Add(1)
+	if p.trackTasks {
+		w.infoEnded = true
+		endTask(p.options, &p.metrics, &w.info)
+	}
+	panicked = false
+}
+
 // updateLastUsed 更新 worker 的最后使用时间
 // 用于超时回收机制
 func (w *goWorkerWithFunc) updateLastUsed() {
-	w.lastUsed = time.Now()
+	w.lastUsed.Store(w.pool.options.clock().Now().UnixNano())
+}
+
+// idleSince 返回 worker 最后一次执行完任务的时间
+func (w *goWorkerWithFunc) idleSince() time.Time {
+	return time.Unix(0, w.lastUsed.Load())
+}
+
+// IdleSince 实现 Worker 接口，返回 worker 最后一次执行完任务的时间
+func (w *goWorkerWithFunc) IdleSince() time.Time {
+	return w.idleSince()
 }
 
 // isRecycled 检查 worker 是否已被回收
@@ -452,13 +1579,27 @@ func (w *goWorkerWithFunc) isRecycled() bool {
 	return atomic.LoadInt32(&w.recycled) == 1
 }
 
-// recycle 标记 worker 为已回收状态
-func (w *goWorkerWithFunc) recycle() {
-	atomic.StoreInt32(&w.recycled, 1)
+// recycle 标记 worker 为已回收状态，已经回收过时返回 false
+func (w *goWorkerWithFunc) recycle() bool {
+	return atomic.CompareAndSwapInt32(&w.recycled, 0, 1)
+}
+
+// expire 标记 worker 为过期并结束 worker
+func (w *goWorkerWithFunc) expire() {
+	atomic.StoreInt32(&w.expired, 1)
+	w.finish()
 }
 
-// finish 结束 worker，关闭参数 channel
+// finish 结束 worker，向其发送退出信号
+// 与 goWorker 相同，不关闭参数 channel，只有第一次调用会发送退出信号。
 func (w *goWorkerWithFunc) finish() {
-	w.recycle()
-	close(w.args)
+	if w.recycle() {
+		w.args <- invocation{stop: true}
+	}
+}
+
+// discard 与 goWorker.discard 相同，结束被其他池的自定义队列取出的 worker
+func (w *goWorkerWithFunc) discard() {
+	atomic.AddInt32(&w.pool.free, -1)
+	w.finish()
 }
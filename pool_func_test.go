@@ -1,6 +1,8 @@
 package laborer
 
 import (
+	"context"
+	"errors"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -22,7 +24,7 @@ func TestNewPoolWithFunc(t *testing.T) {
 	}
 
 	// 测试无效容量
-	_, err = NewPoolWithFunc(0, pf)
+	_, err = NewPoolWithFunc(-5, pf)
 	if err != ErrInvalidPoolSize {
 		t.Errorf("期望返回 ErrInvalidPoolSize，实际返回: %v", err)
 	}
@@ -66,8 +68,8 @@ func TestPoolWithFuncInvoke(t *testing.T) {
 	time.Sleep(100 * time.Millisecond)
 
 	// 验证结果：1+2+3+...+10 = 55
-	if counter != 55 {
-		t.Errorf("期望counter为55，实际为 %d", counter)
+	if n := atomic.LoadInt32(&counter); n != 55 {
+		t.Errorf("期望counter为55，实际为 %d", n)
 	}
 }
 
@@ -143,8 +145,8 @@ func TestPoolWithFuncRelease(t *testing.T) {
 	}
 
 	// 验证所有任务都已执行
-	if counter != 10 {
-		t.Errorf("期望执行10个任务，实际执行了 %d 个", counter)
+	if n := atomic.LoadInt32(&counter); n != 10 {
+		t.Errorf("期望执行10个任务，实际执行了 %d 个", n)
 	}
 }
 
@@ -190,8 +192,8 @@ func TestPoolWithFuncReboot(t *testing.T) {
 
 	time.Sleep(100 * time.Millisecond)
 
-	if counter != 6 {
-		t.Errorf("期望执行6个任务，实际执行了 %d 个", counter)
+	if n := atomic.LoadInt32(&counter); n != 6 {
+		t.Errorf("期望执行6个任务，实际执行了 %d 个", n)
 	}
 
 	pool.Release()
@@ -249,3 +251,357 @@ func TestPoolWithFuncStatusQueries(t *testing.T) {
 
 	wg.Wait()
 }
+
+// TestPoolWithFuncInvokeBatch 测试函数池的批量提交
+func TestPoolWithFuncInvokeBatch(t *testing.T) {
+	var sum int64
+	var wg sync.WaitGroup
+	pool, err := NewPoolWithFunc(4, func(i interface{}) {
+		time.Sleep(time.Millisecond)
+		atomic.AddInt64(&sum, int64(i.(int)))
+		wg.Done()
+	})
+	if err != nil {
+		t.Fatalf("创建函数池失败: %v", err)
+	}
+	defer pool.Release()
+
+	args := make([]interface{}, 20)
+	for i := range args {
+		args[i] = i + 1
+	}
+	wg.Add(len(args))
+
+	submitted, err := pool.InvokeBatch(args)
+	if err != nil || submitted != len(args) {
+		t.Fatalf("批量提交失败: submitted=%d err=%v", submitted, err)
+	}
+	wg.Wait()
+
+	if sum != 210 {
+		t.Errorf("期望总和为 210，实际为 %d", sum)
+	}
+	if pool.Running() > 4 {
+		t.Errorf("运行的worker数量 %d 超过了容量 4", pool.Running())
+	}
+	if s := pool.Stats(); s.Submitted != 20 {
+		t.Errorf("Submitted 期望 20，实际 %d", s.Submitted)
+	}
+
+	// 非阻塞模式下超出容量的部分返回 ErrPoolOverload
+	block := make(chan struct{})
+	nb, err := NewPoolWithFunc(2, func(interface{}) { <-block }, WithNonblocking(true))
+	if err != nil {
+		t.Fatalf("创建函数池失败: %v", err)
+	}
+	defer nb.Release()
+	defer close(block)
+
+	submitted, err = nb.InvokeBatch([]interface{}{1, 2, 3})
+	if submitted != 2 || err != ErrPoolOverload {
+		t.Errorf("期望提交 2 个并返回 ErrPoolOverload，实际 submitted=%d err=%v", submitted, err)
+	}
+}
+
+// TestPoolWithFuncInvokeWithTimeout 测试带超时的阻塞提交
+func TestPoolWithFuncInvokeWithTimeout(t *testing.T) {
+	release := make(chan struct{})
+	pool, err := NewPoolWithFunc(1, func(interface{}) { <-release })
+	if err != nil {
+		t.Fatalf("创建函数池失败: %v", err)
+	}
+	defer pool.Release()
+
+	if err := pool.InvokeWithTimeout(1, time.Second); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+
+	// 池已满，等待超时后返回 ErrTimeout
+	start := time.Now()
+	if err := pool.InvokeWithTimeout(2, 50*time.Millisecond); err != ErrTimeout {
+		t.Errorf("期望返回 ErrTimeout，实际返回: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond || elapsed > time.Second {
+		t.Errorf("等待时间不正确: %v", elapsed)
+	}
+	if pool.Waiting() != 0 {
+		t.Errorf("超时后 Waiting() 应该为 0，实际 %d", pool.Waiting())
+	}
+
+	// worker 在超时前空闲时提交成功
+	time.AfterFunc(20*time.Millisecond, func() { release <- struct{}{} })
+	if err := pool.InvokeWithTimeout(3, time.Second); err != nil {
+		t.Errorf("期望提交成功，实际返回: %v", err)
+	}
+	close(release)
+}
+
+// TestPoolWithFuncMaxBlockingTasks 测试等待 worker 的调用数达到上限后新的调用立即返回 ErrPoolOverload
+func TestPoolWithFuncMaxBlockingTasks(t *testing.T) {
+	release := make(chan struct{})
+	pool, err := NewPoolWithFunc(1, func(interface{}) { <-release }, WithMaxBlockingTasks(1))
+	if err != nil {
+		t.Fatalf("创建函数池失败: %v", err)
+	}
+	defer pool.Release()
+
+	if err := pool.Invoke(1); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	errs := make(chan error, 1)
+	go func() { errs <- pool.Invoke(2) }()
+	waitFor(t, func() bool { return pool.Waiting() == 1 })
+
+	if err := pool.InvokeWithTimeout(3, time.Second); err != ErrPoolOverload {
+		t.Errorf("期望返回 ErrPoolOverload，实际返回: %v", err)
+	}
+	if n := pool.Stats().Rejected; n != 1 {
+		t.Errorf("Rejected 期望 1，实际 %d", n)
+	}
+
+	close(release)
+	if err := <-errs; err != nil {
+		t.Errorf("等待中的调用期望成功，实际返回: %v", err)
+	}
+	pool.Wait()
+}
+
+// TestPoolWithContextFunc 测试固定函数接收的上下文在关闭时取消
+func TestPoolWithContextFunc(t *testing.T) {
+	started := make(chan struct{})
+	exited := make(chan error, 1)
+	pool, err := NewPoolWithContextFunc(1, func(ctx context.Context, args interface{}) {
+		close(started)
+		<-ctx.Done()
+		exited <- ctx.Err()
+	})
+	if err != nil {
+		t.Fatalf("创建函数池失败: %v", err)
+	}
+
+	if err := pool.Invoke(1); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	<-started
+	pool.Release()
+
+	select {
+	case err := <-exited:
+		if err != context.Canceled {
+			t.Errorf("期望 context.Canceled，实际 %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Release 后处理函数没有退出")
+	}
+
+	// Reboot 后使用新的上下文
+	pool.Reboot()
+	defer pool.Release()
+	if err := pool.ctx.Load().ctx.Err(); err != nil {
+		t.Errorf("Reboot 后上下文不应该已取消: %v", err)
+	}
+
+	if _, err := NewPoolWithContextFunc(1, nil); err != ErrInvalidPoolFunc {
+		t.Errorf("期望返回 ErrInvalidPoolFunc，实际返回: %v", err)
+	}
+}
+
+// TestPoolWithFuncQuarantine 测试连续 panic 后的隔离
+func TestPoolWithFuncQuarantine(t *testing.T) {
+	quarantined := make(chan QuarantineInfo, 1)
+	done := make(chan struct{}, 10)
+	pool, err := NewPoolWithFunc(1, func(arg interface{}) {
+		if arg == "bad" {
+			panic("poison")
+		}
+		done <- struct{}{}
+	},
+		WithPanicHandler(func(interface{}) { done <- struct{}{} }),
+		WithPanicQuarantine(3, 50*time.Millisecond, func(info QuarantineInfo) {
+			quarantined <- info
+		}))
+	if err != nil {
+		t.Fatalf("创建函数池失败: %v", err)
+	}
+	defer pool.Release()
+
+	// 中间有成功的调用时连续次数清零
+	for _, arg := range []string{"bad", "bad", "ok", "bad", "bad"} {
+		_ = pool.Invoke(arg)
+		<-done
+	}
+	select {
+	case <-quarantined:
+		t.Fatal("panic 不连续时不应该进入隔离")
+	default:
+	}
+
+	_ = pool.Invoke("bad")
+	<-done
+
+	select {
+	case info := <-quarantined:
+		if info.Panics != 3 || info.Until.IsZero() {
+			t.Errorf("隔离信息不正确: %+v", info)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("连续 panic 后应该进入隔离")
+	}
+
+	if err := pool.Invoke("ok"); err != ErrPoolQuarantined {
+		t.Errorf("隔离期间期望返回 ErrPoolQuarantined，实际返回: %v", err)
+	}
+
+	// 冷却结束后恢复
+	time.Sleep(60 * time.Millisecond)
+	if err := pool.Invoke("ok"); err != nil {
+		t.Errorf("冷却结束后期望提交成功，实际返回: %v", err)
+	}
+	<-done
+}
+
+// TestPoolWithFuncSpillover 测试函数池饱和时在溢出 worker 上执行调用
+func TestPoolWithFuncSpillover(t *testing.T) {
+	block := make(chan struct{})
+	var wg sync.WaitGroup
+	pool, err := NewPoolWithFunc(1, func(interface{}) {
+		defer wg.Done()
+		<-block
+	}, WithNonblocking(true), WithSpillover(1))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	wg.Add(2)
+	for i := 0; i < 2; i++ {
+		if err := pool.Invoke(i); err != nil {
+			t.Fatalf("提交任务失败: %v", err)
+		}
+	}
+	if err := pool.InvokeWithTimeout(2, 10*time.Millisecond); err != ErrPoolOverload {
+		t.Errorf("期望超过溢出上限后返回 ErrPoolOverload，实际返回: %v", err)
+	}
+	if s := pool.Stats(); s.Running != 1 || s.Spilling != 1 || s.Spilled != 1 {
+		t.Errorf("状态不正确: %+v", s)
+	}
+
+	close(block)
+	wg.Wait()
+}
+
+// TestPoolWithFuncReleaseTimeout 测试带超时的关闭等待正在执行的任务完成
+func TestPoolWithFuncReleaseTimeout(t *testing.T) {
+	var completed int32
+	pool, err := NewPoolWithFunc(2, func(interface{}) {
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&completed, 1)
+	})
+	if err != nil {
+		t.Fatalf("创建函数池失败: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := pool.Invoke(i); err != nil {
+			t.Fatalf("提交任务失败: %v", err)
+		}
+	}
+	if err := pool.ReleaseTimeout(time.Second); err != nil {
+		t.Fatalf("关闭池失败: %v", err)
+	}
+	if n := atomic.LoadInt32(&completed); n != 2 {
+		t.Errorf("期望关闭前完成 2 个任务，实际完成 %d 个", n)
+	}
+}
+
+// TestPoolWithFuncDrain 测试函数池排空时拒绝新调用并等待已接受的调用完成
+func TestPoolWithFuncDrain(t *testing.T) {
+	block := make(chan struct{})
+	var completed int32
+	pool, err := NewPoolWithFunc(1, func(interface{}) {
+		<-block
+		atomic.AddInt32(&completed, 1)
+	})
+	if err != nil {
+		t.Fatalf("创建函数池失败: %v", err)
+	}
+
+	if err := pool.Invoke(1); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	invoked := make(chan error, 1)
+	go func() { invoked <- pool.Invoke(2) }()
+	waitFor(t, func() bool { return pool.Waiting() == 1 })
+
+	drained := make(chan error, 1)
+	go func() { drained <- pool.Drain(context.Background()) }()
+	waitFor(t, func() bool { return atomic.LoadInt32(&pool.draining) == 1 })
+	if err := pool.Invoke(3); !errors.Is(err, ErrDraining) {
+		t.Errorf("期望返回 ErrDraining，实际返回: %v", err)
+	}
+
+	close(block)
+	if err := <-drained; err != nil {
+		t.Fatalf("排空失败: %v", err)
+	}
+	if err := <-invoked; err != nil {
+		t.Errorf("等待中的提交失败: %v", err)
+	}
+	if n := atomic.LoadInt32(&completed); n != 2 {
+		t.Errorf("期望排空前完成 2 个任务，实际完成 %d 个", n)
+	}
+	if !pool.IsClosed() {
+		t.Error("池应该已关闭")
+	}
+}
+
+// TestPoolWithFuncPauseResume 测试函数池暂停期间不分配调用，恢复后继续执行
+func TestPoolWithFuncPauseResume(t *testing.T) {
+	var ran int32
+	pool, err := NewPoolWithFunc(2, func(interface{}) {
+		atomic.AddInt32(&ran, 1)
+	})
+	if err != nil {
+		t.Fatalf("创建函数池失败: %v", err)
+	}
+	defer pool.Release()
+
+	pool.Pause()
+	invoked := make(chan error, 1)
+	go func() { invoked <- pool.Invoke(1) }()
+	waitFor(t, func() bool { return pool.Waiting() == 1 })
+	if n := atomic.LoadInt32(&ran); n != 0 {
+		t.Errorf("暂停期间不应执行调用，实际执行了 %d 次", n)
+	}
+
+	pool.Resume()
+	if err := <-invoked; err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	waitFor(t, func() bool { return atomic.LoadInt32(&ran) == 1 })
+}
+
+// TestPoolWithFuncWait 测试函数池 Wait 等待所有调用完成
+func TestPoolWithFuncWait(t *testing.T) {
+	var completed int32
+	pool, err := NewPoolWithFunc(2, func(interface{}) {
+		time.Sleep(5 * time.Millisecond)
+		atomic.AddInt32(&completed, 1)
+	})
+	if err != nil {
+		t.Fatalf("创建函数池失败: %v", err)
+	}
+	defer pool.Release()
+
+	for i := 0; i < 6; i++ {
+		if err := pool.Invoke(i); err != nil {
+			t.Fatalf("提交任务失败: %v", err)
+		}
+	}
+	if err := pool.WaitWithTimeout(time.Second); err != nil {
+		t.Fatalf("等待调用完成失败: %v", err)
+	}
+	if n := atomic.LoadInt32(&completed); n != 6 {
+		t.Errorf("期望完成 6 次调用，实际完成 %d 次", n)
+	}
+}
@@ -1,12 +1,89 @@
 package laborer
 
 import (
+	"context"
+	"errors"
+	"runtime"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 )
 
+// TestNewPoolDefaultSize 测试容量为 0 时按 CPU 数量换算容量
+func TestNewPoolDefaultSize(t *testing.T) {
+	procs := runtime.GOMAXPROCS(0)
+
+	pool, err := NewPool(0)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+	if pool.Cap() != procs {
+		t.Errorf("期望容量为 GOMAXPROCS %d，实际 %d", procs, pool.Cap())
+	}
+
+	fp, err := NewPoolWithFunc(0, func(interface{}) {}, WithSizeMultiplier(4))
+	if err != nil {
+		t.Fatalf("创建函数池失败: %v", err)
+	}
+	defer fp.Release()
+	if fp.Cap() != procs*4 {
+		t.Errorf("期望容量为 %d，实际 %d", procs*4, fp.Cap())
+	}
+
+	// 指定了容量时倍数不生效
+	bounded, _ := NewPool(3, WithSizeMultiplier(4))
+	defer bounded.Release()
+	if bounded.Cap() != 3 {
+		t.Errorf("期望容量为 3，实际 %d", bounded.Cap())
+	}
+
+	if _, err := NewPool(0, WithSizeMultiplier(-1)); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("倍数为负数时期望返回 ErrInvalidOption，实际 %v", err)
+	}
+}
+
+// TestNewPoolInvalidSize 测试创建池时拒绝无效的容量
+func TestNewPoolInvalidSize(t *testing.T) {
+	for _, size := range []int{-2, -5} {
+		if _, err := NewPool(size); err != ErrInvalidPoolSize {
+			t.Errorf("容量 %d: 期望返回 ErrInvalidPoolSize，实际返回: %v", size, err)
+		}
+	}
+
+	// 超过 int32 的容量不能被截断成意外的值（仅在 64 位平台上可以表示）
+	if huge := int64(maxPoolSize) + 1; int64(int(huge)) == huge {
+		if _, err := NewPool(int(huge)); err != ErrInvalidPoolSize {
+			t.Errorf("容量 %d: 期望返回 ErrInvalidPoolSize，实际返回: %v", huge, err)
+		}
+		if _, err := NewPoolWithFunc(int(huge<<8), func(interface{}) {}); err != ErrInvalidPoolSize {
+			t.Errorf("容量 %d: 期望返回 ErrInvalidPoolSize，实际返回: %v", huge<<8, err)
+		}
+	}
+
+	pool, err := NewPool(-1)
+	if err != nil {
+		t.Fatalf("创建无限容量的池失败: %v", err)
+	}
+	defer pool.Release()
+
+	// Tune 忽略无效的容量
+	bounded, _ := NewPool(4)
+	defer bounded.Release()
+	bounded.Tune(-5)
+	if bounded.Cap() != 4 {
+		t.Errorf("Tune(-5) 后期望容量 4，实际 %d", bounded.Cap())
+	}
+	if huge := int64(maxPoolSize) + 1; int64(int(huge)) == huge {
+		bounded.Tune(int(huge))
+		if bounded.Cap() != 4 {
+			t.Errorf("Tune(%d) 后期望容量 4，实际 %d", huge, bounded.Cap())
+		}
+	}
+}
+
 // TestPoolRelease 测试优雅关闭池
 func TestPoolRelease(t *testing.T) {
 	pool, err := NewPool(5)
@@ -61,20 +138,25 @@ func TestPoolReleaseTimeout(t *testing.T) {
 	}
 
 	// 提交一些快速完成的任务
+	var completed int32
 	for i := 0; i < 5; i++ {
 		err := pool.Submit(func() {
 			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(&completed, 1)
 		})
 		if err != nil {
 			t.Errorf("提交任务失败: %v", err)
 		}
 	}
 
-	// 使用足够的超时时间关闭
+	// 使用足够的超时时间关闭，返回时所有任务都已完成
 	err = pool.ReleaseTimeout(1 * time.Second)
 	if err != nil {
 		t.Errorf("关闭池失败: %v", err)
 	}
+	if n := atomic.LoadInt32(&completed); n != 5 {
+		t.Errorf("期望关闭前完成 5 个任务，实际完成 %d 个", n)
+	}
 
 	// 验证池已关闭
 	if !pool.IsClosed() {
@@ -82,11 +164,360 @@ func TestPoolReleaseTimeout(t *testing.T) {
 	}
 }
 
-// TestPoolReleaseTimeoutExpired 测试超时关闭
-// 注意：ReleaseTimeout 的超时是针对清理过程的超时，不是等待任务完成的超时
-// 在正常情况下，清理过程很快，所以这个测试主要验证超时机制本身
+// TestPoolReleaseTimeoutExpired 测试任务在超时前没有完成
 func TestPoolReleaseTimeoutExpired(t *testing.T) {
-	t.Skip("ReleaseTimeout 在正常情况下清理很快，难以触发超时")
+	pool, err := NewPool(2)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+
+	block := make(chan struct{})
+	defer close(block)
+	for i := 0; i < 2; i++ {
+		if err := pool.Submit(func() { <-block }); err != nil {
+			t.Fatalf("提交任务失败: %v", err)
+		}
+	}
+
+	err = pool.ReleaseTimeout(20 * time.Millisecond)
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("期望返回 ErrTimeout，实际返回: %v", err)
+	}
+	if !strings.Contains(err.Error(), "2 tasks still running") {
+		t.Errorf("期望错误中包含未完成的任务数量，实际: %v", err)
+	}
+	if !pool.IsClosed() {
+		t.Error("池应该已关闭")
+	}
+}
+
+// TestRebootAfterReleaseTimeoutExpired 测试 ReleaseTimeout 超时后池在后台清理结束前保持 CLOSING，
+// 清理结束后才能重启
+func TestRebootAfterReleaseTimeoutExpired(t *testing.T) {
+	pool, err := NewPool(2)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+
+	block := make(chan struct{})
+	if err := pool.Submit(func() { <-block }); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+
+	if err := pool.ReleaseTimeout(0); !errors.Is(err, ErrTimeout) {
+		t.Fatalf("期望返回 ErrTimeout，实际返回: %v", err)
+	}
+
+	// 清理可能仍在后台进行，池处于 CLOSING 或 CLOSED，而不是半关闭的状态
+	if !pool.IsClosed() && !pool.IsClosing() {
+		t.Fatal("超时返回后池应该处于 CLOSING 或 CLOSED 状态")
+	}
+
+	waitFor(t, pool.IsClosed)
+	pool.Reboot()
+	if pool.IsClosed() || pool.IsClosing() {
+		t.Fatal("清理结束后重启应该打开池")
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	if err := pool.Submit(wg.Done); err != nil {
+		t.Fatalf("重启后提交任务失败: %v", err)
+	}
+	wg.Wait()
+
+	close(block)
+	waitFor(t, func() bool { return pool.Running() <= 2 })
+	if err := pool.Stats().Check(); err != nil {
+		t.Errorf("重启后计数异常: %v", err)
+	}
+	pool.Release()
+}
+
+// TestPoolDrain 测试排空时拒绝新任务并等待已接受的任务完成
+func TestPoolDrain(t *testing.T) {
+	pool, err := NewPool(1)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+
+	block := make(chan struct{})
+	var completed int32
+	task := func() {
+		<-block
+		atomic.AddInt32(&completed, 1)
+	}
+	if err := pool.Submit(task); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+
+	// 第二个任务阻塞等待 worker，排空时同样需要执行
+	submitted := make(chan error, 1)
+	go func() { submitted <- pool.Submit(task) }()
+	waitFor(t, func() bool { return pool.Waiting() == 1 })
+
+	drained := make(chan error, 1)
+	go func() { drained <- pool.Drain(context.Background()) }()
+	waitFor(t, func() bool { return atomic.LoadInt32(&pool.draining) == 1 })
+	if err := pool.Submit(task); !errors.Is(err, ErrDraining) {
+		t.Errorf("期望返回 ErrDraining，实际返回: %v", err)
+	}
+	if !pool.IsClosing() {
+		t.Error("排空期间池应该处于正在关闭状态")
+	}
+
+	close(block)
+	if err := <-drained; err != nil {
+		t.Fatalf("排空失败: %v", err)
+	}
+	if err := <-submitted; err != nil {
+		t.Errorf("等待中的提交失败: %v", err)
+	}
+	if n := atomic.LoadInt32(&completed); n != 2 {
+		t.Errorf("期望排空前完成 2 个任务，实际完成 %d 个", n)
+	}
+	if !pool.IsClosed() {
+		t.Error("池应该已关闭")
+	}
+	if err := pool.Drain(context.Background()); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("期望返回 ErrPoolClosed，实际返回: %v", err)
+	}
+
+	// 重启后重新接受任务
+	pool.Reboot()
+	defer pool.Release()
+	if err := pool.Submit(func() {}); err != nil {
+		t.Errorf("重启后提交任务失败: %v", err)
+	}
+}
+
+// TestPoolDrainCanceled 测试 ctx 取消时 Drain 返回，池保持排空状态
+func TestPoolDrainCanceled(t *testing.T) {
+	pool, err := NewPool(2)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	block := make(chan struct{})
+	defer close(block)
+	if err := pool.Submit(func() { <-block }); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := pool.Drain(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("期望返回 context.DeadlineExceeded，实际返回: %v", err)
+	}
+	if pool.IsClosed() {
+		t.Error("ctx 取消后池不应该被关闭")
+	}
+	if err := pool.Submit(func() {}); !errors.Is(err, ErrDraining) {
+		t.Errorf("期望返回 ErrDraining，实际返回: %v", err)
+	}
+}
+
+// TestPoolPauseResume 测试暂停期间不分配任务，恢复后继续执行
+func TestPoolPauseResume(t *testing.T) {
+	pool, err := NewPool(2)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	pool.Pause()
+	if !pool.IsPaused() {
+		t.Fatal("池应该已暂停")
+	}
+
+	// 阻塞模式下的提交等待 Resume
+	var ran int32
+	submitted := make(chan error, 1)
+	go func() { submitted <- pool.Submit(func() { atomic.AddInt32(&ran, 1) }) }()
+	waitFor(t, func() bool { return pool.Waiting() == 1 })
+	if n := atomic.LoadInt32(&ran); n != 0 {
+		t.Errorf("暂停期间不应执行任务，实际执行了 %d 个", n)
+	}
+	if ok, err := pool.tryDispatch(taskItem{run: func() {}}); ok || err != nil {
+		t.Errorf("暂停期间 tryDispatch 应该返回 false, nil，实际返回 %v, %v", ok, err)
+	}
+
+	pool.Resume()
+	if pool.IsPaused() {
+		t.Fatal("池应该已恢复")
+	}
+	if err := <-submitted; err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	waitFor(t, func() bool { return atomic.LoadInt32(&ran) == 1 })
+}
+
+// TestPoolPauseNonblocking 测试非阻塞模式下暂停期间的提交返回 ErrPoolOverload
+func TestPoolPauseNonblocking(t *testing.T) {
+	pool, err := NewPool(2, WithNonblocking(true))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	pool.Pause()
+	if err := pool.Submit(func() {}); !errors.Is(err, ErrPoolOverload) {
+		t.Errorf("期望返回 ErrPoolOverload，实际返回: %v", err)
+	}
+
+	pool.Resume()
+	if err := pool.Submit(func() {}); err != nil {
+		t.Errorf("恢复后提交任务失败: %v", err)
+	}
+}
+
+// TestPoolWait 测试 Wait 等待正在执行和阻塞等待的任务全部完成
+func TestPoolWait(t *testing.T) {
+	pool, err := NewPool(2)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	// 空闲的池立即返回
+	pool.Wait()
+
+	var completed int32
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 10; i++ {
+			if err := pool.Submit(func() {
+				time.Sleep(5 * time.Millisecond)
+				atomic.AddInt32(&completed, 1)
+			}); err != nil {
+				t.Errorf("提交任务失败: %v", err)
+			}
+		}
+		close(done)
+	}()
+	<-done
+
+	pool.Wait()
+	if n := atomic.LoadInt32(&completed); n != 10 {
+		t.Errorf("期望 Wait 返回时完成 10 个任务，实际完成 %d 个", n)
+	}
+	if pool.IsClosed() {
+		t.Error("Wait 不应关闭池")
+	}
+}
+
+// TestPoolWaitWithTimeout 测试任务在超时前没有完成
+func TestPoolWaitWithTimeout(t *testing.T) {
+	pool, err := NewPool(1)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	block := make(chan struct{})
+	if err := pool.Submit(func() { <-block }); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+
+	// 阻塞等待 worker 的提交同样计入
+	go pool.Submit(func() {})
+	waitFor(t, func() bool { return pool.Waiting() == 1 })
+
+	err = pool.WaitWithTimeout(20 * time.Millisecond)
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("期望返回 ErrTimeout，实际返回: %v", err)
+	}
+	if !strings.Contains(err.Error(), "2 tasks still running") {
+		t.Errorf("期望错误中包含未完成的任务数量，实际: %v", err)
+	}
+
+	close(block)
+	if err := pool.WaitWithTimeout(time.Second); err != nil {
+		t.Errorf("等待任务完成失败: %v", err)
+	}
+}
+
+// TestPoolWaitRunning 测试等待运行的 worker 数量到达预期值
+func TestPoolWaitRunning(t *testing.T) {
+	pool, err := NewPool(4)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	block := make(chan struct{})
+	for i := 0; i < 3; i++ {
+		if err := pool.Submit(func() { <-block }); err != nil {
+			t.Fatalf("提交任务失败: %v", err)
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := pool.WaitRunning(ctx, 3); err != nil {
+		t.Fatalf("等待 3 个 worker 失败: %v", err)
+	}
+
+	// 计数不会到达时在 ctx 取消后返回
+	short, cancelShort := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancelShort()
+	if err := pool.WaitRunning(short, 4); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("期望返回 context.DeadlineExceeded，实际 %v", err)
+	}
+
+	close(block)
+	pool.Wait()
+	pool.PurgeNow()
+	if err := pool.WaitRunning(ctx, 0); err != nil {
+		t.Errorf("等待 worker 全部退出失败: %v，运行 %d", err, pool.Running())
+	}
+}
+
+// TestPoolClosingState 测试关闭过程中的 CLOSING 状态
+func TestPoolClosingState(t *testing.T) {
+	pool, err := NewPool(2)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	if pool.IsClosing() || pool.IsClosed() {
+		t.Fatal("新建的池不应处于关闭状态")
+	}
+
+	block := make(chan struct{})
+	if err := pool.Submit(func() { <-block }); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+
+	released := make(chan error, 1)
+	go func() { released <- pool.ReleaseTimeout(time.Second) }()
+	waitFor(t, pool.IsClosing)
+
+	if pool.IsClosed() {
+		t.Error("任务完成前池不应完全关闭")
+	}
+	if err := pool.Submit(func() {}); err != ErrPoolClosed {
+		t.Errorf("期望返回 ErrPoolClosed，实际返回: %v", err)
+	}
+
+	// 正在关闭时 Reboot 不做任何事
+	pool.Reboot()
+	if !pool.IsClosing() {
+		t.Error("正在关闭时 Reboot 不应重新打开池")
+	}
+
+	close(block)
+	if err := <-released; err != nil {
+		t.Fatalf("关闭池失败: %v", err)
+	}
+	if pool.IsClosing() || !pool.IsClosed() {
+		t.Error("关闭完成后池应该处于 CLOSED 状态")
+	}
+
+	pool.Reboot()
+	defer pool.Release()
+	if pool.IsClosing() || pool.IsClosed() {
+		t.Error("重启后池应该处于 OPENED 状态")
+	}
 }
 
 // TestPoolReboot 测试重启已关闭的池
@@ -145,6 +576,57 @@ func TestPoolReboot(t *testing.T) {
 	pool.Release()
 }
 
+// TestConcurrentReleaseAndReboot 测试并发的关闭和重启不会重复关闭清理 channel 或丢失状态
+func TestConcurrentReleaseAndReboot(t *testing.T) {
+	pool, err := NewPool(4, WithExpiryDuration(time.Millisecond), WithName("reboot-race"))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				pool.Release()
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				_ = pool.ReleaseTimeout(time.Millisecond)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				pool.Reboot()
+				_ = pool.Submit(func() {})
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 最终关闭后池必须处于 CLOSED 状态并从注册表中移除
+	pool.Release()
+	waitFor(t, pool.IsClosed)
+	for _, p := range Pools() {
+		if p == Inspectable(pool) {
+			t.Error("关闭后的池仍在注册表中")
+		}
+	}
+
+	pool.Reboot()
+	if pool.IsClosed() {
+		t.Fatal("重启后池应该处于打开状态")
+	}
+	if err := pool.Submit(func() {}); err != nil {
+		t.Errorf("重启后提交任务失败: %v", err)
+	}
+	pool.Release()
+}
+
 // TestPoolStateManagement 测试状态管理
 func TestPoolStateManagement(t *testing.T) {
 	pool, err := NewPool(3)
@@ -266,6 +748,41 @@ func TestConcurrentReleaseAndSubmit(t *testing.T) {
 	}
 }
 
+// TestReleaseWorkersExit 测试与提交竞争的关闭不会 panic，所有 worker 最终都会退出
+func TestReleaseWorkersExit(t *testing.T) {
+	for round := 0; round < 20; round++ {
+		pool, err := NewPool(4)
+		if err != nil {
+			t.Fatalf("创建池失败: %v", err)
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for {
+					err := pool.Submit(func() {})
+					if err == ErrPoolClosed {
+						return
+					}
+					if err != nil {
+						t.Errorf("期望返回 nil 或 ErrPoolClosed，实际返回: %v", err)
+						return
+					}
+				}
+			}()
+		}
+
+		time.Sleep(time.Millisecond)
+		pool.Release()
+		wg.Wait()
+
+		// 在关闭之后才归还的 worker 不能留在空闲队列中
+		waitFor(t, func() bool { return pool.Running() == 0 })
+	}
+}
+
 // TestPoolCapacityAndFree 测试容量和空闲worker查询
 func TestPoolCapacityAndFree(t *testing.T) {
 	capacity := 5
@@ -309,6 +826,50 @@ func TestPoolCapacityAndFree(t *testing.T) {
 	wg.Wait()
 }
 
+// TestPoolUnboundedFree 测试无限容量的池 Free 返回 -1，IdleWorkers 返回空闲 worker 的数量
+func TestPoolUnboundedFree(t *testing.T) {
+	pool, err := NewPool(-1)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		if err := pool.Submit(wg.Done); err != nil {
+			t.Fatalf("提交任务失败: %v", err)
+		}
+	}
+	wg.Wait()
+	waitFor(t, func() bool { return pool.IdleWorkers() == pool.Running() })
+
+	if pool.Free() != -1 {
+		t.Errorf("无限容量的池 Free 期望返回 -1，实际 %d", pool.Free())
+	}
+	if pool.IdleWorkers() == 0 {
+		t.Error("IdleWorkers 应该返回空闲 worker 的数量")
+	}
+	s := pool.Stats()
+	if s.Free != -1 || s.Idle != pool.IdleWorkers() {
+		t.Errorf("快照不一致: Free=%d Idle=%d", s.Free, s.Idle)
+	}
+	if err := s.Check(); err != nil {
+		t.Errorf("快照违反不变量: %v", err)
+	}
+
+	// 有限容量的池 Free 与 IdleWorkers 相同
+	bounded, _ := NewPool(4)
+	defer bounded.Release()
+	wg.Add(1)
+	_ = bounded.Submit(wg.Done)
+	wg.Wait()
+	waitFor(t, func() bool { return bounded.IdleWorkers() == 1 })
+	if bounded.Free() != 1 {
+		t.Errorf("有限容量的池 Free 期望返回 1，实际 %d", bounded.Free())
+	}
+}
+
 // TestPoolStatusQueries 测试所有状态查询接口
 func TestPoolStatusQueries(t *testing.T) {
 	capacity := 3
@@ -481,6 +1042,10 @@ func TestPoolWaitingCount(t *testing.T) {
 	} else {
 		t.Logf("Waiting() 返回 %d 个等待的任务", waiting)
 	}
+	// 没有任务队列时等待的任务都是阻塞的提交者
+	if blocked := pool.BlockedSubmitters(); blocked != waiting {
+		t.Errorf("BlockedSubmitters() 期望与 Waiting() 相同，实际 %d != %d", blocked, waiting)
+	}
 
 	// 等待所有任务完成
 	wg.Wait()
@@ -492,3 +1057,335 @@ func TestPoolWaitingCount(t *testing.T) {
 		t.Errorf("Waiting() 所有任务完成后应该返回 0，实际返回 %d", waiting)
 	}
 }
+
+// TestPoolPurgeNow 测试立即回收空闲 worker
+func TestPoolPurgeNow(t *testing.T) {
+	pool, err := NewPool(5)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		if err := pool.Submit(func() {
+			time.Sleep(10 * time.Millisecond)
+			wg.Done()
+		}); err != nil {
+			t.Errorf("提交任务失败: %v", err)
+		}
+	}
+	wg.Wait()
+
+	// 等待 worker 放回队列
+	time.Sleep(20 * time.Millisecond)
+
+	if n := pool.PurgeNow(); n == 0 {
+		t.Error("应该回收至少一个空闲 worker")
+	}
+
+	if pool.Free() != 0 {
+		t.Errorf("回收后空闲 worker 应该为 0，实际 %d", pool.Free())
+	}
+
+	// 等待 worker goroutine 退出
+	time.Sleep(20 * time.Millisecond)
+	if pool.Running() != 0 {
+		t.Errorf("回收后 Running() 应该为 0，实际 %d", pool.Running())
+	}
+
+	// 回收后仍然可以正常提交任务
+	done := make(chan struct{})
+	if err := pool.Submit(func() { close(done) }); err != nil {
+		t.Fatalf("回收后提交任务失败: %v", err)
+	}
+	<-done
+}
+
+// TestPoolDisablePurge 测试禁用过期清理
+func TestPoolDisablePurge(t *testing.T) {
+	pool, err := NewPool(2, WithExpiryDuration(50*time.Millisecond), WithDisablePurge(true))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+
+	if pool.stopCleaning != nil || pool.cleaningDone != nil {
+		t.Error("禁用清理时不应该创建清理 channel")
+	}
+
+	done := make(chan struct{})
+	if err := pool.Submit(func() { close(done) }); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	<-done
+
+	// 超过过期时间后 worker 仍然应该存活
+	time.Sleep(150 * time.Millisecond)
+	if pool.Running() != 1 {
+		t.Errorf("禁用清理后 worker 不应该被回收，Running() = %d", pool.Running())
+	}
+
+	pool.Release()
+	pool.Reboot()
+	if pool.IsClosed() {
+		t.Error("池应该已重启")
+	}
+	pool.Release()
+}
+
+// TestWorkerHooks 测试 worker 生命周期钩子
+func TestWorkerHooks(t *testing.T) {
+	created := make(chan int, 1)
+	expired := make(chan int, 1)
+	exited := make(chan int, 1)
+
+	pool, err := NewPool(1,
+		WithExpiryDuration(50*time.Millisecond),
+		WithWorkerHooks(
+			func(id int) { created <- id },
+			func(id int) { expired <- id },
+			func(id int) { exited <- id },
+		))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	if err := pool.Submit(func() {}); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+
+	wait := func(ch chan int, name string) int {
+		select {
+		case id := <-ch:
+			return id
+		case <-time.After(time.Second):
+			t.Fatalf("等待 %s 钩子超时", name)
+			return 0
+		}
+	}
+
+	id := wait(created, "onCreate")
+	if id <= 0 {
+		t.Errorf("worker ID 应该为正数，实际 %d", id)
+	}
+	if got := wait(expired, "onExpire"); got != id {
+		t.Errorf("onExpire 的 worker ID 期望 %d，实际 %d", id, got)
+	}
+	if got := wait(exited, "onExit"); got != id {
+		t.Errorf("onExit 的 worker ID 期望 %d，实际 %d", id, got)
+	}
+}
+
+// TestPoolTune 测试运行时调整池容量
+func TestPoolTune(t *testing.T) {
+	pool, err := NewPool(2, WithNonblocking(true))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	block := make(chan struct{})
+	var wg sync.WaitGroup
+	submit := func() error {
+		wg.Add(1)
+		err := pool.Submit(func() {
+			defer wg.Done()
+			<-block
+		})
+		if err != nil {
+			wg.Done()
+		}
+		return err
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := submit(); err != nil {
+			t.Fatalf("提交任务失败: %v", err)
+		}
+	}
+	if err := submit(); err != ErrPoolOverload {
+		t.Errorf("期望返回 ErrPoolOverload，实际返回: %v", err)
+	}
+
+	// 扩容后可以继续提交
+	pool.Tune(4)
+	if pool.Cap() != 4 {
+		t.Errorf("期望容量为 4，实际为 %d", pool.Cap())
+	}
+	for i := 0; i < 2; i++ {
+		if err := submit(); err != nil {
+			t.Fatalf("扩容后提交任务失败: %v", err)
+		}
+	}
+
+	// 缩容后多出的 worker 在任务完成后退出
+	pool.Tune(1)
+	close(block)
+	wg.Wait()
+	deadline := time.Now().Add(time.Second)
+	for pool.Running() != 1 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if pool.Running() != 1 {
+		t.Errorf("期望缩容后运行 1 个 worker，实际 %d 个", pool.Running())
+	}
+
+	// 无限容量的池和无效的容量不做任何事
+	unbounded, _ := NewPool(-1)
+	defer unbounded.Release()
+	unbounded.Tune(10)
+	pool.Tune(0)
+	if unbounded.Cap() != -1 || pool.Cap() != 1 {
+		t.Errorf("期望容量不变，实际 %d 和 %d", unbounded.Cap(), pool.Cap())
+	}
+}
+
+// TestPoolFreeCounter 测试 Free 的原子计数与空闲队列保持一致
+func TestPoolFreeCounter(t *testing.T) {
+	pool, err := NewPool(8, WithExpiryDuration(50*time.Millisecond))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		if err := pool.Submit(func() {
+			defer wg.Done()
+			time.Sleep(time.Millisecond)
+		}); err != nil {
+			wg.Done()
+			t.Fatalf("提交任务失败: %v", err)
+		}
+	}
+	wg.Wait()
+
+	deadline := time.Now().Add(time.Second)
+	for pool.Free() != pool.Running() && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if len(pool.Workers()) != pool.Free() {
+		t.Errorf("Workers 与 Free 不一致: %d != %d", len(pool.Workers()), pool.Free())
+	}
+
+	// 过期回收后计数归零
+	deadline = time.Now().Add(2 * time.Second)
+	for pool.Free() != 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if pool.Free() != 0 || len(pool.Workers()) != 0 {
+		t.Errorf("期望空闲 worker 过期后 Free 为 0，实际 Free=%d Workers=%d", pool.Free(), len(pool.Workers()))
+	}
+
+	// PurgeNow 后计数归零
+	wg.Add(1)
+	if err := pool.Submit(wg.Done); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	wg.Wait()
+	deadline = time.Now().Add(time.Second)
+	for pool.Free() != 1 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	pool.PurgeNow()
+	if pool.Free() != 0 {
+		t.Errorf("期望 PurgeNow 后 Free 为 0，实际 %d", pool.Free())
+	}
+}
+
+// TestPoolTuneShrinkConcurrentPut 测试缩容后并发归还的 worker 不会使运行的 worker 少于新容量
+func TestPoolTuneShrinkConcurrentPut(t *testing.T) {
+	for round := 0; round < 20; round++ {
+		pool, err := NewPool(16)
+		if err != nil {
+			t.Fatalf("创建池失败: %v", err)
+		}
+
+		block := make(chan struct{})
+		for i := 0; i < 16; i++ {
+			if err := pool.Submit(func() { <-block }); err != nil {
+				t.Fatalf("提交任务失败: %v", err)
+			}
+		}
+		pool.Tune(8)
+		close(block)
+
+		// 16 个 worker 同时归还，恰好 8 个退出
+		waitFor(t, func() bool { return pool.Stats().Idle == 8 })
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		if err := pool.WaitRunning(ctx, 8); err != nil {
+			t.Fatalf("期望 8 个运行的 worker，实际 %d", pool.Running())
+		}
+		cancel()
+		pool.Release()
+	}
+}
+
+// TestPoolTuneWakesWaiters 测试扩容后所有阻塞的提交者都被唤醒并创建新的 worker
+func TestPoolTuneWakesWaiters(t *testing.T) {
+	block := make(chan struct{})
+	var started int32
+	task := func() {
+		atomic.AddInt32(&started, 1)
+		<-block
+	}
+
+	pool, err := NewPool(1)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+	fp, err := NewPoolWithFunc(1, func(interface{}) { task() })
+	if err != nil {
+		t.Fatalf("创建函数池失败: %v", err)
+	}
+	defer fp.Release()
+
+	submits := []struct {
+		name    string
+		submit  func() error
+		waiting func() int
+		tune    func(int)
+	}{
+		{"Pool", func() error { return pool.Submit(task) }, pool.Waiting, pool.Tune},
+		{"PoolWithFunc", func() error { return fp.Invoke(1) }, fp.Waiting, fp.Tune},
+	}
+
+	for _, s := range submits {
+		atomic.StoreInt32(&started, 0)
+		if err := s.submit(); err != nil {
+			t.Fatalf("%s: 提交任务失败: %v", s.name, err)
+		}
+
+		errs := make(chan error, 3)
+		for i := 0; i < 3; i++ {
+			go func(submit func() error) { errs <- submit() }(s.submit)
+		}
+		deadline := time.Now().Add(time.Second)
+		for s.waiting() != 3 && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		if s.waiting() != 3 {
+			t.Fatalf("%s: 期望 3 个提交者在等待，实际 %d 个", s.name, s.waiting())
+		}
+
+		// 扩容后 3 个等待者都应在新增的容量上开始执行，而不是等待第一个任务结束
+		s.tune(4)
+		for i := 0; i < 3; i++ {
+			if err := <-errs; err != nil {
+				t.Errorf("%s: 扩容后提交失败: %v", s.name, err)
+			}
+		}
+		deadline = time.Now().Add(time.Second)
+		for atomic.LoadInt32(&started) != 4 && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		if n := atomic.LoadInt32(&started); n != 4 {
+			t.Errorf("%s: 期望 4 个任务同时执行，实际 %d 个", s.name, n)
+		}
+	}
+	close(block)
+}
@@ -1,6 +1,8 @@
 package laborer
 
 import (
+	"errors"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -69,6 +71,35 @@ func TestPoolSubmitBlocking(t *testing.T) {
 	}
 }
 
+// TestPoolSubmitBlockingWorkerExit 测试阻塞的提交者在 worker 退出（而不是归还）后创建新的 worker
+func TestPoolSubmitBlockingWorkerExit(t *testing.T) {
+	pool, err := NewPool(1, WithPanicHandler(func(interface{}) {}))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	// 任务 panic 后 worker 退出，释放容量但不会回到空闲队列
+	block := make(chan struct{})
+	if err := pool.Submit(func() {
+		<-block
+		panic("boom")
+	}); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+
+	var ran int32
+	submitted := make(chan error, 1)
+	go func() { submitted <- pool.Submit(func() { atomic.AddInt32(&ran, 1) }) }()
+	waitFor(t, func() bool { return pool.Waiting() == 1 })
+
+	close(block)
+	if err := <-submitted; err != nil {
+		t.Fatalf("阻塞模式下被唤醒的提交不应失败: %v", err)
+	}
+	waitFor(t, func() bool { return atomic.LoadInt32(&ran) == 1 })
+}
+
 // TestPoolSubmitNonblocking 测试非阻塞模式
 func TestPoolSubmitNonblocking(t *testing.T) {
 	// 创建容量为2的池，非阻塞模式
@@ -98,6 +129,119 @@ func TestPoolSubmitNonblocking(t *testing.T) {
 	}
 }
 
+// TestPoolMaxBlockingTasks 测试等待 worker 的提交数达到上限后新的提交立即返回 ErrPoolOverload
+func TestPoolMaxBlockingTasks(t *testing.T) {
+	pool, err := NewPool(1, WithMaxBlockingTasks(2))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	release := make(chan struct{})
+	if err := pool.Submit(func() { <-release }); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+
+	// 两个提交阻塞等待 worker
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() { errs <- pool.Submit(func() {}) }()
+	}
+	waitFor(t, func() bool { return pool.Waiting() == 2 })
+
+	// 等待的提交数已达上限，SubmitMany 同样不再等待
+	if err := pool.Submit(func() {}); err != ErrPoolOverload {
+		t.Errorf("期望返回 ErrPoolOverload，实际返回: %v", err)
+	}
+	if err := pool.SubmitMany(func() {}); err != ErrPoolOverload {
+		t.Errorf("SubmitMany 期望返回 ErrPoolOverload，实际返回: %v", err)
+	}
+	if n := pool.Stats().Rejected; n != 2 {
+		t.Errorf("Rejected 期望 2，实际 %d", n)
+	}
+
+	// 等待中的提交在 worker 空闲后成功
+	close(release)
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != nil {
+			t.Errorf("等待中的提交期望成功，实际返回: %v", err)
+		}
+	}
+	pool.Wait()
+}
+
+// TestPoolSubmitWithTimeout 测试阻塞模式下等待 worker 超时
+func TestPoolSubmitWithTimeout(t *testing.T) {
+	pool, err := NewPool(1)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	release := make(chan struct{})
+	if err := pool.SubmitWithTimeout(func() { <-release }, time.Second); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+
+	// 池已满，等待超时后返回 ErrTimeout，任务不会被执行
+	var ran int32
+	start := time.Now()
+	if err := pool.SubmitWithTimeout(func() { atomic.AddInt32(&ran, 1) }, 50*time.Millisecond); err != ErrTimeout {
+		t.Errorf("期望返回 ErrTimeout，实际返回: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond || elapsed > time.Second {
+		t.Errorf("等待时间不正确: %v", elapsed)
+	}
+	if pool.Waiting() != 0 {
+		t.Errorf("超时后 Waiting() 应该为 0，实际 %d", pool.Waiting())
+	}
+
+	// worker 在超时前空闲时提交成功
+	time.AfterFunc(20*time.Millisecond, func() { release <- struct{}{} })
+	if err := pool.SubmitWithTimeout(func() { atomic.AddInt32(&ran, 1) }, time.Second); err != nil {
+		t.Errorf("期望提交成功，实际返回: %v", err)
+	}
+	pool.Wait()
+	if atomic.LoadInt32(&ran) != 1 {
+		t.Errorf("期望只执行提交成功的 1 个任务，实际 %d", ran)
+	}
+}
+
+// TestPoolSubmitNilTask 测试提交 nil 任务时返回 ErrNilTask，不影响 worker 和计数
+func TestPoolSubmitNilTask(t *testing.T) {
+	pool, err := NewPool(2)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	errs := map[string]error{
+		"Submit":          pool.Submit(nil),
+		"SubmitNamed":     pool.SubmitNamed("x", nil),
+		"SubmitWait":      pool.SubmitWait(nil),
+		"SubmitMany":      pool.SubmitMany(func() {}, nil),
+		"SubmitWeighted":  pool.SubmitWeighted(nil, 2),
+		"SubmitWithState": pool.SubmitWithState(nil),
+	}
+	_, errs["SubmitWithResult"] = pool.SubmitWithResult(nil)
+	_, errs["SubmitAll"] = pool.SubmitAll([]func(){nil})
+	for name, err := range errs {
+		if err != ErrNilTask {
+			t.Errorf("%s 期望返回 ErrNilTask，实际 %v", name, err)
+		}
+	}
+
+	if err := pool.WaitWithTimeout(time.Second); err != nil {
+		t.Fatalf("拒绝的 nil 任务不应该计入未完成的任务: %v", err)
+	}
+	if err := pool.SubmitWait(func() {}); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	if s := pool.Stats(); s.Submitted != 1 || s.Completed != 1 {
+		t.Errorf("统计不正确: %+v", s)
+	}
+}
+
 // TestPoolSubmitAfterClose 测试关闭后提交任务
 func TestPoolSubmitAfterClose(t *testing.T) {
 	pool, err := NewPool(5)
@@ -171,6 +315,110 @@ func TestSubmitWithResult(t *testing.T) {
 	}
 }
 
+// resizeTask 携带自身字段的任务对象
+type resizeTask struct {
+	width int
+	done  *int32
+}
+
+func (r resizeTask) Run() { atomic.AddInt32(r.done, int32(r.width)) }
+
+// areaTask 携带自身字段的带返回值的任务对象
+type areaTask struct{ w, h int }
+
+func (a areaTask) Run() (interface{}, error) { return a.w * a.h, nil }
+
+// TestSubmitTask 测试提交实现了 Task 和 TaskWithResult 接口的任务对象
+func TestSubmitTask(t *testing.T) {
+	pool, err := NewPool(2)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	var done int32
+	for i := 1; i <= 3; i++ {
+		if err := pool.SubmitTask(resizeTask{width: i, done: &done}); err != nil {
+			t.Fatalf("提交任务失败: %v", err)
+		}
+	}
+	pool.Wait()
+	if atomic.LoadInt32(&done) != 6 {
+		t.Errorf("期望累计 6，实际 %d", done)
+	}
+
+	f, err := pool.SubmitTaskWithResult(areaTask{w: 3, h: 4})
+	if err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	if result, err := f.Get(); err != nil || result != 12 {
+		t.Errorf("期望结果为 12，实际 %v, %v", result, err)
+	}
+
+	// nil 任务对象返回错误，不会在调用方 panic
+	if err := pool.SubmitTask(nil); err != ErrNilTask {
+		t.Errorf("期望返回 ErrNilTask，实际 %v", err)
+	}
+	if _, err := pool.SubmitTaskWithResult(nil); err != ErrNilTask {
+		t.Errorf("期望返回 ErrNilTask，实际 %v", err)
+	}
+}
+
+// TestSubmitNamed 测试任务名称出现在任务钩子、DumpStacks 和 panic 记录中
+func TestSubmitNamed(t *testing.T) {
+	names := make(chan string, 4)
+	pool, err := NewPool(1,
+		WithTaskHooks(func(info TaskInfo) { names <- info.Name }, nil),
+		WithPanicHandler(func(interface{}) {}),
+		WithStackDumps(true))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	release := make(chan struct{})
+	if err := pool.SubmitNamed("resize", func() { <-release }); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	if name := <-names; name != "resize" {
+		t.Errorf("期望任务钩子收到名称 resize，实际 %q", name)
+	}
+	stacks := pool.DumpStacks()
+	if len(stacks) != 1 || stacks[0].Task != "resize" {
+		t.Errorf("期望 DumpStacks 包含任务名称 resize，实际 %+v", stacks)
+	}
+	if ns := pool.NamedStats()["resize"]; ns.Running != 1 {
+		t.Errorf("期望 resize 有 1 个执行中的任务，实际 %+v", ns)
+	}
+	close(release)
+
+	// 没有名称的任务不会沿用上一个任务的名称
+	if err := pool.SubmitWait(func() {}); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	if name := <-names; name != "" {
+		t.Errorf("没有名称的任务期望名称为空，实际 %q", name)
+	}
+
+	_ = pool.SubmitNamed("send-mail", func() { panic("boom") })
+	<-names
+	waitFor(t, func() bool { return len(pool.RecentPanics()) == 1 })
+	if task := pool.RecentPanics()[0].Task; task != "send-mail" {
+		t.Errorf("期望 panic 记录包含任务名称 send-mail，实际 %q", task)
+	}
+
+	// 按名称的计数不包括没有名称的任务
+	stats := pool.NamedStats()
+	if len(stats) != 2 || stats["resize"] != (NamedStats{Completed: 1}) || stats["send-mail"] != (NamedStats{Panicked: 1}) {
+		t.Errorf("按名称的统计不正确: %+v", stats)
+	}
+
+	pool.Release()
+	if err := pool.SubmitNamed("resize", func() {}); err != ErrPoolClosed {
+		t.Errorf("关闭后期望返回 ErrPoolClosed，实际 %v", err)
+	}
+}
+
 // TestSubmitWithResultError 测试带错误返回的任务
 func TestSubmitWithResultError(t *testing.T) {
 	pool, err := NewPool(5)
@@ -322,3 +570,620 @@ func TestMultipleFutureGet(t *testing.T) {
 		t.Errorf("多次获取结果不一致: %v vs %v", result1, result2)
 	}
 }
+
+// TestTaskHooks 测试任务生命周期钩子
+func TestTaskHooks(t *testing.T) {
+	var started int32
+	completed := make(chan TaskInfo, 3)
+
+	pool, err := NewPool(2,
+		WithName("hooks"),
+		WithPanicHandler(func(interface{}) {}),
+		WithTaskHooks(func(info TaskInfo) {
+			atomic.AddInt32(&started, 1)
+		}, func(info TaskInfo) {
+			completed <- info
+		}))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	_ = pool.Submit(func() { time.Sleep(10 * time.Millisecond) })
+	_, _ = pool.SubmitWithResult(func() (interface{}, error) { return nil, errors.New("boom") })
+	_ = pool.Submit(func() { panic("oops") })
+
+	var sawErr, sawPanic, sawDuration bool
+	for i := 0; i < 3; i++ {
+		select {
+		case info := <-completed:
+			if info.Pool != "hooks" {
+				t.Errorf("Pool 期望 hooks，实际 %q", info.Pool)
+			}
+			if info.SubmittedAt.IsZero() || info.StartedAt.Before(info.SubmittedAt) {
+				t.Errorf("时间元数据不正确: %+v", info)
+			}
+			if info.Err != nil {
+				sawErr = true
+			}
+			if info.Panic != nil {
+				sawPanic = true
+			}
+			if info.Duration >= 10*time.Millisecond {
+				sawDuration = true
+			}
+		case <-time.After(time.Second):
+			t.Fatal("等待 onComplete 超时")
+		}
+	}
+
+	if !sawErr || !sawPanic || !sawDuration {
+		t.Errorf("钩子信息缺失: err=%v panic=%v duration=%v", sawErr, sawPanic, sawDuration)
+	}
+	if n := atomic.LoadInt32(&started); n != 3 {
+		t.Errorf("onStart 期望调用 3 次，实际 %d", n)
+	}
+}
+
+// TestTaskHookPanic 测试 onComplete 钩子 panic 时只被调用一次
+func TestTaskHookPanic(t *testing.T) {
+	var calls atomic.Int32
+	hook := func(TaskInfo) {
+		calls.Add(1)
+		panic("hook")
+	}
+	recovered := make(chan interface{}, 2)
+	handler := WithPanicHandler(func(r interface{}) { recovered <- r })
+
+	pool, err := NewPool(1, handler, WithTaskHooks(nil, hook))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+	fp, err := NewPoolWithFunc(1, func(interface{}) {}, handler, WithTaskHooks(nil, hook))
+	if err != nil {
+		t.Fatalf("创建函数池失败: %v", err)
+	}
+	defer fp.Release()
+
+	_ = pool.Submit(func() {})
+	_ = fp.Invoke(1)
+	for i := 0; i < 2; i++ {
+		if r := <-recovered; r != "hook" {
+			t.Errorf("期望恢复钩子的 panic，实际 %v", r)
+		}
+	}
+	pool.Wait()
+	fp.Wait()
+	if n := calls.Load(); n != 2 {
+		t.Errorf("每个任务的 onComplete 期望只调用 1 次，实际共 %d 次", n)
+	}
+}
+
+// TestOverloadHandler 测试过载回调
+func TestOverloadHandler(t *testing.T) {
+	var got OverloadInfo
+	var calls int32
+
+	pool, err := NewPool(1, WithName("overload"), WithNonblocking(true),
+		WithOverloadHandler(func(info OverloadInfo) {
+			atomic.AddInt32(&calls, 1)
+			got = info
+		}))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	block := make(chan struct{})
+	defer close(block)
+	if err := pool.Submit(func() { <-block }); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+
+	if err := pool.Submit(func() {}); err != ErrPoolOverload {
+		t.Fatalf("期望返回 ErrPoolOverload，实际返回: %v", err)
+	}
+
+	if atomic.LoadInt32(&calls) != 1 {
+		t.Fatalf("过载回调期望调用 1 次，实际 %d", calls)
+	}
+	if got.Pool != "overload" || got.Running != 1 || got.Cap != 1 {
+		t.Errorf("过载信息不正确: %+v", got)
+	}
+}
+
+// TestPanicHandlerV2 测试带栈和任务元数据的 panic 处理函数
+func TestPanicHandlerV2(t *testing.T) {
+	type report struct {
+		recovered interface{}
+		stack     []byte
+		info      TaskInfo
+	}
+	reports := make(chan report, 1)
+	var legacyCalls int32
+
+	pool, err := NewPool(1,
+		WithName("v2"),
+		WithPanicHandler(func(interface{}) { atomic.AddInt32(&legacyCalls, 1) }),
+		WithPanicHandlerV2(func(recovered interface{}, stack []byte, info TaskInfo) {
+			reports <- report{recovered, stack, info}
+		}))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	_ = pool.Submit(func() { panic("oops") })
+
+	select {
+	case r := <-reports:
+		if r.recovered != "oops" {
+			t.Errorf("recovered 期望 oops，实际 %v", r.recovered)
+		}
+		if !strings.Contains(string(r.stack), "TestPanicHandlerV2") {
+			t.Error("栈中应该包含任务的调用位置")
+		}
+		if r.info.Pool != "v2" || r.info.Panic != "oops" || r.info.SubmittedAt.IsZero() {
+			t.Errorf("任务元数据不正确: %+v", r.info)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("等待 panic 处理函数超时")
+	}
+
+	if atomic.LoadInt32(&legacyCalls) != 0 {
+		t.Error("设置 PanicHandlerV2 后不应该调用 PanicHandler")
+	}
+}
+
+// TestWorkerInitTeardown 测试 per-worker 资源的初始化和释放
+func TestWorkerInitTeardown(t *testing.T) {
+	var seq, tornDown int32
+
+	pool, err := NewPool(2,
+		WithWorkerInit(func() (interface{}, error) {
+			return atomic.AddInt32(&seq, 1), nil
+		}),
+		WithWorkerTeardown(func(state interface{}) {
+			atomic.AddInt32(&tornDown, 1)
+		}))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+
+	states := make(chan interface{}, 1)
+	if err := pool.SubmitWithState(func(state interface{}) { states <- state }); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	if state := <-states; state != int32(1) {
+		t.Errorf("任务应该收到 worker 的资源 1，实际 %v", state)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	pool.Release()
+	time.Sleep(20 * time.Millisecond)
+
+	if atomic.LoadInt32(&tornDown) != atomic.LoadInt32(&seq) {
+		t.Errorf("释放次数 %d 应该等于初始化次数 %d", tornDown, seq)
+	}
+
+	// 初始化失败时提交应该返回 ErrWorkerInit
+	failing, err := NewPool(1, WithWorkerInit(func() (interface{}, error) {
+		return nil, errors.New("dial failed")
+	}))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer failing.Release()
+
+	if err := failing.Submit(func() {}); !errors.Is(err, ErrWorkerInit) {
+		t.Errorf("期望返回 ErrWorkerInit，实际返回: %v", err)
+	}
+	if failing.Running() != 0 {
+		t.Errorf("初始化失败后 Running() 应该为 0，实际 %d", failing.Running())
+	}
+
+	// PoolWithFunc 不支持 per-worker 资源，应该在创建时拒绝
+	_, err = NewPoolWithFunc(1, func(interface{}) {}, WithWorkerInit(func() (interface{}, error) { return nil, nil }))
+	if !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("期望 NewPoolWithFunc 返回 ErrInvalidOption，实际返回: %v", err)
+	}
+}
+
+// TestSubmitWithResultPanic 测试任务 panic 时 future 以 PanicError 完成
+func TestSubmitWithResultPanic(t *testing.T) {
+	pool, err := NewPool(1, WithPanicHandler(func(interface{}) {}))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	future, err := pool.SubmitWithResult(func() (interface{}, error) {
+		panic("oops")
+	})
+	if err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+
+	_, err = future.GetWithTimeout(time.Second)
+	var pe *PanicError
+	if !errors.As(err, &pe) {
+		t.Fatalf("期望返回 PanicError，实际返回: %v", err)
+	}
+	if pe.Value != "oops" || len(pe.Stack) == 0 {
+		t.Errorf("PanicError 内容不正确: %v", pe)
+	}
+}
+
+// TestSubmitWeighted 测试多槽任务占用多个容量槽位
+func TestSubmitWeighted(t *testing.T) {
+	pool, err := NewPool(4, WithNonblocking(true))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	if err := pool.SubmitWeighted(func() {}, 5); err != ErrInvalidTaskWeight {
+		t.Errorf("期望返回 ErrInvalidTaskWeight，实际返回: %v", err)
+	}
+	if err := pool.SubmitWeighted(func() {}, 0); err != ErrInvalidTaskWeight {
+		t.Errorf("期望返回 ErrInvalidTaskWeight，实际返回: %v", err)
+	}
+
+	// 权重为 3 的任务执行期间只剩 1 个槽位
+	block := make(chan struct{})
+	heavyDone := make(chan struct{})
+	if err := pool.SubmitWeighted(func() {
+		<-block
+		close(heavyDone)
+	}, 3); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	if err := pool.Submit(func() { <-block }); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	if err := pool.Submit(func() {}); err != ErrPoolOverload {
+		t.Errorf("期望返回 ErrPoolOverload，实际返回: %v", err)
+	}
+	if err := pool.SubmitWeighted(func() {}, 2); err != ErrPoolOverload {
+		t.Errorf("期望返回 ErrPoolOverload，实际返回: %v", err)
+	}
+
+	// 重任务结束后归还全部槽位
+	close(block)
+	<-heavyDone
+	deadline := time.Now().Add(time.Second)
+	for pool.Free() != 4 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if pool.Free() != 4 || pool.Running() != 4 {
+		t.Errorf("期望 4 个空闲 worker，实际 Free=%d Running=%d", pool.Free(), pool.Running())
+	}
+	if err := pool.SubmitWeighted(func() {}, 4); err != nil {
+		t.Errorf("提交任务失败: %v", err)
+	}
+}
+
+// TestSubmitMany 测试成组提交的任务全部被接受或全部不被接受
+func TestSubmitMany(t *testing.T) {
+	pool, err := NewPool(3, WithNonblocking(true))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	if err := pool.SubmitMany(); err != nil {
+		t.Errorf("没有任务时期望返回 nil，实际 %v", err)
+	}
+	if err := pool.SubmitMany(func() {}, func() {}, func() {}, func() {}); err != ErrPoolOverload {
+		t.Errorf("任务数超过容量时期望返回 ErrPoolOverload，实际 %v", err)
+	}
+
+	block := make(chan struct{})
+	if err := pool.Submit(func() { <-block }); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+
+	// 只剩 2 个槽位时 3 个任务都不执行
+	var ran atomic.Int32
+	task := func() { ran.Add(1) }
+	if err := pool.SubmitMany(task, task, task); err != ErrPoolOverload {
+		t.Fatalf("期望返回 ErrPoolOverload，实际 %v", err)
+	}
+	if err := pool.SubmitMany(task, task); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	close(block)
+	pool.Wait()
+	if ran.Load() != 2 {
+		t.Errorf("期望只执行被接受的 2 个任务，实际 %d", ran.Load())
+	}
+	// 整组被拒绝时每个任务都记为被拒绝
+	if s := pool.Stats(); s.Submitted != 3 || s.Rejected != 7 || s.Waiting != 0 {
+		t.Errorf("期望提交 3 个、拒绝 7 个任务且没有等待，实际 %+v", s)
+	}
+}
+
+// TestSubmitManyBlocking 测试阻塞模式下等待凑齐 worker 后一起执行
+func TestSubmitManyBlocking(t *testing.T) {
+	pool, err := NewPool(2)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	block := make(chan struct{})
+	_ = pool.Submit(func() { <-block })
+
+	var ran atomic.Int32
+	done := make(chan error)
+	go func() { done <- pool.SubmitMany(func() { ran.Add(1) }, func() { ran.Add(1) }) }()
+
+	select {
+	case err := <-done:
+		t.Fatalf("只有 1 个空闲槽位时 SubmitMany 不应该返回，实际 %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+	if ran.Load() != 0 {
+		t.Fatal("凑齐 worker 之前不应该执行任何任务")
+	}
+
+	close(block)
+	if err := <-done; err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	pool.Wait()
+	if ran.Load() != 2 {
+		t.Errorf("期望执行 2 个任务，实际 %d", ran.Load())
+	}
+}
+
+// TestSubmitWeightedTuneShrink 测试等待期间缩容到权重以下时返回而不是永久等待
+func TestSubmitWeightedTuneShrink(t *testing.T) {
+	pool, err := NewPool(4)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	block := make(chan struct{})
+	for i := 0; i < 2; i++ {
+		_ = pool.Submit(func() { <-block })
+	}
+
+	weighted := make(chan error)
+	go func() { weighted <- pool.SubmitWeighted(func() {}, 3) }()
+	waitFor(t, func() bool { return pool.Waiting() == 1 })
+
+	pool.Tune(2)
+	if err := <-weighted; err != ErrPoolOverload {
+		t.Errorf("缩容到权重以下时期望返回 ErrPoolOverload，实际 %v", err)
+	}
+
+	// 等待中的 SubmitMany 同样返回，不再占住 weightedLock
+	many := make(chan error)
+	go func() { many <- pool.SubmitMany(func() {}, func() {}) }()
+	waitFor(t, func() bool { return pool.Waiting() == 1 })
+	pool.Tune(1)
+	if err := <-many; err != ErrPoolOverload {
+		t.Errorf("缩容到任务数以下时期望返回 ErrPoolOverload，实际 %v", err)
+	}
+
+	close(block)
+	pool.Tune(2)
+	if err := pool.SubmitMany(func() {}, func() {}); err != nil {
+		t.Errorf("提交任务失败: %v", err)
+	}
+}
+
+// TestSubmitWait 测试 SubmitWait 在任务执行完毕后才返回，并返回任务的 panic
+func TestSubmitWait(t *testing.T) {
+	recovered := make(chan interface{}, 1)
+	pool, err := NewPool(2, WithPanicHandler(func(r interface{}) { recovered <- r }))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+
+	done := false
+	if err := pool.SubmitWait(func() {
+		time.Sleep(10 * time.Millisecond)
+		done = true
+	}); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	if !done {
+		t.Fatal("SubmitWait 返回时任务应该已经执行完毕")
+	}
+
+	var pe *PanicError
+	if err := pool.SubmitWait(func() { panic("boom") }); !errors.As(err, &pe) || pe.Value != "boom" {
+		t.Errorf("任务 panic 时期望返回 PanicError，实际 %v", err)
+	}
+	if r := <-recovered; r != "boom" {
+		t.Errorf("期望 PanicHandler 收到 boom，实际 %v", r)
+	}
+
+	pool.Release()
+	if err := pool.SubmitWait(func() {}); err != ErrPoolClosed {
+		t.Errorf("关闭后期望返回 ErrPoolClosed，实际 %v", err)
+	}
+}
+
+// TestSubmitWaitWithTimeout 测试等待 worker 和等待执行超时时返回 ErrTimeout
+func TestSubmitWaitWithTimeout(t *testing.T) {
+	pool, err := NewPool(1)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	if err := pool.SubmitWaitWithTimeout(func() {}, time.Second); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+
+	// 任务执行时间超过 timeout
+	release := make(chan struct{})
+	if err := pool.SubmitWaitWithTimeout(func() { <-release }, 20*time.Millisecond); err != ErrTimeout {
+		t.Fatalf("任务未执行完毕时期望返回 ErrTimeout，实际 %v", err)
+	}
+
+	// 唯一的 worker 被占用，等待 worker 超时，任务不会被执行
+	var ran atomic.Bool
+	if err := pool.SubmitWaitWithTimeout(func() { ran.Store(true) }, 20*time.Millisecond); err != ErrTimeout {
+		t.Fatalf("没有空闲 worker 时期望返回 ErrTimeout，实际 %v", err)
+	}
+	close(release)
+	pool.Wait()
+	if ran.Load() {
+		t.Error("等待 worker 超时的任务不应该被执行")
+	}
+	if s := pool.Stats(); s.Submitted != 2 {
+		t.Errorf("期望提交 2 个任务，实际 %d", s.Submitted)
+	}
+}
+
+// TestSpillover 测试池饱和时在溢出 worker 上执行任务
+func TestSpillover(t *testing.T) {
+	pool, err := NewPool(1, WithNonblocking(true), WithSpillover(2), WithPanicHandler(func(interface{}) {}))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	block := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		if err := pool.Submit(func() {
+			defer wg.Done()
+			<-block
+		}); err != nil {
+			t.Fatalf("提交任务失败: %v", err)
+		}
+	}
+	if err := pool.Submit(func() {}); err != ErrPoolOverload {
+		t.Errorf("期望超过溢出上限后返回 ErrPoolOverload，实际返回: %v", err)
+	}
+	if s := pool.Stats(); s.Running != 1 || s.Spilling != 2 || s.Spilled != 2 || s.Submitted != 3 {
+		t.Errorf("状态不正确: %+v", s)
+	}
+
+	close(block)
+	wg.Wait()
+
+	// 溢出 worker 执行完任务后退出，归还溢出名额；panic 同样被恢复
+	deadline := time.Now().Add(time.Second)
+	for pool.Stats().Spilling != 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	block = make(chan struct{})
+	pool.Submit(func() { <-block })
+	f, err := pool.SubmitWithResult(func() (interface{}, error) { panic("boom") })
+	if err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	if _, err := f.Get(); err == nil {
+		t.Error("期望溢出 worker 上 panic 的任务返回错误")
+	}
+	close(block)
+
+	if s := pool.Stats(); s.Running != 1 || s.Spilled != 3 {
+		t.Errorf("状态不正确: %+v", s)
+	}
+}
+
+// TestSubmitAll 测试批量提交任务
+func TestSubmitAll(t *testing.T) {
+	pool, err := NewPool(4)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	var sum int64
+	var wg sync.WaitGroup
+	tasks := make([]func(), 20)
+	for i := range tasks {
+		n := int64(i + 1)
+		tasks[i] = func() {
+			defer wg.Done()
+			time.Sleep(time.Millisecond)
+			atomic.AddInt64(&sum, n)
+		}
+	}
+
+	// 第二轮提交复用第一轮留下的空闲 worker
+	for round := 1; round <= 2; round++ {
+		wg.Add(len(tasks))
+		submitted, err := pool.SubmitAll(tasks)
+		if err != nil || submitted != len(tasks) {
+			t.Fatalf("批量提交失败: submitted=%d err=%v", submitted, err)
+		}
+		wg.Wait()
+
+		if sum != int64(210*round) {
+			t.Errorf("期望总和为 %d，实际为 %d", 210*round, sum)
+		}
+		if pool.Running() > 4 {
+			t.Errorf("运行的worker数量 %d 超过了容量 4", pool.Running())
+		}
+	}
+	if s := pool.Stats(); s.Submitted != 40 {
+		t.Errorf("Submitted 期望 40，实际 %d", s.Submitted)
+	}
+
+	// 非阻塞模式下超出容量的部分返回 ErrPoolOverload
+	block := make(chan struct{})
+	nb, err := NewPool(2, WithNonblocking(true))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer nb.Release()
+	defer close(block)
+
+	wait := func() { <-block }
+	submitted, err := nb.SubmitAll([]func(){wait, wait, wait})
+	if submitted != 2 || err != ErrPoolOverload {
+		t.Errorf("期望提交 2 个并返回 ErrPoolOverload，实际 submitted=%d err=%v", submitted, err)
+	}
+}
+
+// TestRunningNeverExceedsCap 测试并发提交时运行的 worker 数量不超过容量
+func TestRunningNeverExceedsCap(t *testing.T) {
+	const capacity = 4
+	for _, opts := range [][]Option{nil, {WithNonblocking(true)}} {
+		pool, err := NewPool(capacity, opts...)
+		if err != nil {
+			t.Fatalf("创建池失败: %v", err)
+		}
+
+		var maxRunning int32
+		task := func() {
+			n := int32(pool.Running())
+			for {
+				m := atomic.LoadInt32(&maxRunning)
+				if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 32; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 20; j++ {
+					_ = pool.Submit(task)
+				}
+			}()
+		}
+		wg.Wait()
+		pool.Release()
+
+		if m := atomic.LoadInt32(&maxRunning); m > capacity {
+			t.Errorf("运行的 worker 数量超过容量: %d > %d", m, capacity)
+		}
+	}
+}
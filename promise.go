@@ -0,0 +1,53 @@
+package laborer
+
+// Promise 是由外部代码完成的 Future。
+//
+// 当结果来自池之外的异步事件（例如 webhook 回调、消息队列的回复）时，
+// 可以用 Promise 把它桥接为 Future，从而复用 Get / GetWithTimeout / IsDone
+// 等等待与超时机制。
+//
+// Promise 只能完成一次，之后的 Complete 调用会被忽略。
+//
+// 示例:
+//
+//	promise := laborer.NewPromise()
+//	pending[requestID] = promise
+//
+//	// 在回调中完成
+//	promise.Complete(reply, nil)
+//
+//	// 在消费方等待
+//	result, err := promise.Future().GetWithTimeout(5 * time.Second)
+type Promise struct {
+	f *future
+}
+
+// NewPromise 创建一个尚未完成的 Promise。
+//
+// 返回:
+//   - *Promise: 新创建的 Promise
+func NewPromise() *Promise {
+	return &Promise{f: newFuture()}
+}
+
+// Complete 以给定的结果和错误完成 Promise。
+//
+// 所有等待 Future 的 goroutine 都会被唤醒。
+// 只有第一次调用生效，之后的调用不会改变结果。
+//
+// 参数:
+//   - result: 结果值
+//   - err: 错误，成功时为 nil
+func (p *Promise) Complete(result interface{}, err error) {
+	p.f.setResult(result, err)
+}
+
+// Future 返回与该 Promise 关联的 Future。
+//
+// 多次调用返回同一个 Future。
+//
+// 返回:
+//   - Future: 在 Complete 调用后完成的 Future
+func (p *Promise) Future() Future {
+	return p.f
+}
@@ -0,0 +1,56 @@
+package laborer
+
+import "sync"
+
+// registry 保存进程内所有存活的池
+// 池在创建和 Reboot 时注册，在 Release 时注销
+var registry struct {
+	mu    sync.Mutex
+	pools []Inspectable
+}
+
+// register 将池加入全局注册表
+func register(p Inspectable) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	for _, existing := range registry.pools {
+		if existing == p {
+			return
+		}
+	}
+	registry.pools = append(registry.pools, p)
+}
+
+// unregister 将池从全局注册表中移除
+func unregister(p Inspectable) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	for i, existing := range registry.pools {
+		if existing == p {
+			registry.pools = append(registry.pools[:i], registry.pools[i+1:]...)
+			return
+		}
+	}
+}
+
+// Pools 返回进程内所有存活（未关闭）的池，按创建顺序排列。
+//
+// 配合 WithName 使用，监控、日志和 DebugHandler 可以区分同一进程中的多个池。
+// 池在 Release 后从注册表中移除，Reboot 后重新加入；
+// 因此不再使用的池应当调用 Release，否则注册表会一直持有其引用。
+//
+// 示例:
+//
+//	for _, p := range laborer.Pools() {
+//	    log.Printf("%s: %+v", p.Name(), p.Stats())
+//	}
+func Pools() []Inspectable {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	out := make([]Inspectable, len(registry.pools))
+	copy(out, registry.pools)
+	return out
+}
@@ -0,0 +1,44 @@
+package laborer
+
+import "testing"
+
+// containsPool 检查注册表中是否存在指定的池
+func containsPool(p Inspectable) bool {
+	for _, existing := range Pools() {
+		if existing == p {
+			return true
+		}
+	}
+	return false
+}
+
+// TestPoolRegistry 测试全局池注册表
+func TestPoolRegistry(t *testing.T) {
+	pool, err := NewPool(1, WithName("image-resize"))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	funcPool, err := NewPoolWithFunc(1, func(interface{}) {}, WithName("webhook"))
+	if err != nil {
+		t.Fatalf("创建函数池失败: %v", err)
+	}
+	defer funcPool.Release()
+
+	if pool.Name() != "image-resize" || funcPool.Name() != "webhook" {
+		t.Errorf("池名称不正确: %q, %q", pool.Name(), funcPool.Name())
+	}
+	if !containsPool(pool) || !containsPool(funcPool) {
+		t.Fatal("新创建的池应该在注册表中")
+	}
+
+	pool.Release()
+	if containsPool(pool) {
+		t.Error("关闭后的池不应该在注册表中")
+	}
+
+	pool.Reboot()
+	if !containsPool(pool) {
+		t.Error("重启后的池应该重新加入注册表")
+	}
+	pool.Release()
+}
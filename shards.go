@@ -0,0 +1,285 @@
+package laborer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// shard 分片池中单个子池需要提供的方法
+type shard interface {
+	Running() int
+	Free() int
+	IdleWorkers() int
+	Cap() int
+	Waiting() int
+	BlockedSubmitters() int
+	IsClosed() bool
+	Stats() Stats
+	Release()
+	ReleaseTimeout(timeout time.Duration) error
+	Reboot()
+	Tune(size int)
+}
+
+// shards 一组独立的子池，为分片池提供汇总的状态查询和生命周期管理
+// 嵌入到分片池类型中，使其方法成为分片池的方法。
+type shards[T shard] []T
+
+// poolShards 由通用池组成的分片
+type poolShards = shards[*Pool]
+
+// funcShards 由函数池组成的分片
+type funcShards = shards[*PoolWithFunc]
+
+// newPoolShards 创建 n 个容量为 sizePerPool 的通用子池
+// 任意子池创建失败时释放已创建的子池并返回错误。
+func newPoolShards(n, sizePerPool int, options ...Option) (poolShards, error) {
+	s := make(poolShards, n)
+	for i := range s {
+		pool, err := NewPool(sizePerPool, options...)
+		if err != nil {
+			s[:i].Release()
+			return nil, err
+		}
+		s[i] = pool
+	}
+	return s, nil
+}
+
+// newFuncShards 创建 n 个容量为 sizePerPool 的函数子池
+// 任意子池创建失败时释放已创建的子池并返回错误。
+func newFuncShards(n, sizePerPool int, pf func(interface{}), options ...Option) (funcShards, error) {
+	s := make(funcShards, n)
+	for i := range s {
+		pool, err := NewPoolWithFunc(sizePerPool, pf, options...)
+		if err != nil {
+			s[:i].Release()
+			return nil, err
+		}
+		s[i] = pool
+	}
+	return s, nil
+}
+
+// pick 根据负载均衡策略选择一个子池
+func (s shards[T]) pick(b *balancer) T {
+	return s[s.pickIndex(b)]
+}
+
+// pickIndex 根据负载均衡策略选择一个子池，返回其下标
+func (s shards[T]) pickIndex(b *balancer) int {
+	return b.pick(len(s), s.load(b))
+}
+
+// pickIndexByKey 为带键的任务选择一个子池，返回其下标
+func (s shards[T]) pickIndexByKey(b *balancer, key string) int {
+	return b.pickKey(len(s), key, s.load(b))
+}
+
+// load 返回按策略统计子池负载的函数
+func (s shards[T]) load(b *balancer) func(i int) int {
+	return func(i int) int {
+		if b.strategy == LeastTasks {
+			return s[i].Running() + s[i].Waiting()
+		}
+		return s[i].Running()
+	}
+}
+
+// steal 从第 first 个子池开始依次尝试 try，直到某个子池接受任务
+// try 返回 false 表示该子池已满。所有子池都已满时返回 false。
+func (s shards[T]) steal(first int, try func(T) (bool, error)) (bool, error) {
+	for k := 0; k < len(s); k++ {
+		ok, err := try(s[(first+k)%len(s)])
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+	return false, nil
+}
+
+// thieves 启用工作窃取的分片池中，等待任意子池空出 worker 的提交者
+//
+// 阻塞模式下所有子池都已满时，提交者不在选中的子池上等待，而是在这里等待：
+// 任意子池的 worker 变为空闲、退出或容量增加，且该子池没有自己的等待者时唤醒一个提交者，
+// 由它重新尝试所有子池。这样空闲的子池会接手其他子池积压的提交，
+// 突发流量集中到某个子池时不必等待该子池的 worker。
+type thieves struct {
+	mu      sync.Mutex
+	waiters waitQueue
+
+	// gen 每次通知时递增，提交者在开始等待前检查它，避免错过尝试期间的通知
+	gen uint64
+
+	// count 正在窃取的提交者数量，为 0 时通知不获取锁
+	count atomic.Int32
+}
+
+// wait 反复从所有子池中窃取，直到某个子池接受任务或返回错误
+// try 尝试所有子池一次；每次都失败后等待下一次通知。
+// blocked 是选中子池的窃取计数，等待期间计入该子池的 BlockedSubmitters。
+func (t *thieves) wait(blocked *atomic.Int32, try func() (bool, error)) error {
+	blocked.Add(1)
+	defer blocked.Add(-1)
+	t.count.Add(1)
+	defer t.count.Add(-1)
+
+	var w *waiter
+	for {
+		t.mu.Lock()
+		gen := t.gen
+		t.mu.Unlock()
+
+		ok, err := try()
+		if err != nil || ok {
+			return err
+		}
+
+		t.mu.Lock()
+		if t.gen == gen {
+			if w == nil {
+				w = newWaiter()
+			}
+			_ = t.waiters.wait(context.Background(), w, &t.mu, time.Time{})
+		}
+		t.mu.Unlock()
+	}
+}
+
+// notify 唤醒一个等待的提交者，未启用工作窃取（t 为 nil）时不做任何事
+func (t *thieves) notify() {
+	if t == nil || t.count.Load() == 0 {
+		return
+	}
+	t.mu.Lock()
+	t.gen++
+	t.waiters.signal()
+	t.mu.Unlock()
+}
+
+// broadcast 唤醒所有等待的提交者，用于子池恢复、扩容和关闭
+func (t *thieves) broadcast() {
+	if t == nil || t.count.Load() == 0 {
+		return
+	}
+	t.mu.Lock()
+	t.gen++
+	t.waiters.broadcast()
+	t.mu.Unlock()
+}
+
+// Running 返回所有子池正在运行的 worker 总数
+func (s shards[T]) Running() int {
+	n := 0
+	for _, p := range s {
+		n += p.Running()
+	}
+	return n
+}
+
+// Free 返回所有子池空闲的 worker 总数，子池为无限容量时返回 -1
+func (s shards[T]) Free() int {
+	n := 0
+	for _, p := range s {
+		if p.Free() == -1 {
+			return -1
+		}
+		n += p.Free()
+	}
+	return n
+}
+
+// IdleWorkers 返回所有子池空闲队列中 worker 的总数
+func (s shards[T]) IdleWorkers() int {
+	n := 0
+	for _, p := range s {
+		n += p.IdleWorkers()
+	}
+	return n
+}
+
+// Cap 返回所有子池的总容量，子池为无限容量时返回 -1
+func (s shards[T]) Cap() int {
+	n := 0
+	for _, p := range s {
+		if p.Cap() == -1 {
+			return -1
+		}
+		n += p.Cap()
+	}
+	return n
+}
+
+// Waiting 返回所有子池等待执行的任务总数
+func (s shards[T]) Waiting() int {
+	n := 0
+	for _, p := range s {
+		n += p.Waiting()
+	}
+	return n
+}
+
+// BlockedSubmitters 返回所有子池阻塞等待 worker 的提交者总数
+func (s shards[T]) BlockedSubmitters() int {
+	n := 0
+	for _, p := range s {
+		n += p.BlockedSubmitters()
+	}
+	return n
+}
+
+// IsClosed 返回池是否已关闭
+func (s shards[T]) IsClosed() bool {
+	return s[0].IsClosed()
+}
+
+// Stats 返回所有子池汇总后的状态快照
+func (s shards[T]) Stats() Stats {
+	var stats Stats
+	for _, p := range s {
+		stats.merge(p.Stats())
+	}
+	return stats
+}
+
+// Release 关闭所有子池
+func (s shards[T]) Release() {
+	for _, p := range s {
+		p.Release()
+	}
+}
+
+// ReleaseTimeout 带超时地关闭所有子池
+// 所有子池同时关闭并等待各自的任务完成，共享同一个超时时间，返回遇到的所有错误。
+func (s shards[T]) ReleaseTimeout(timeout time.Duration) error {
+	errs := make([]error, len(s))
+
+	var wg sync.WaitGroup
+	for i, p := range s {
+		wg.Add(1)
+		go func(i int, p T) {
+			defer wg.Done()
+			errs[i] = p.ReleaseTimeout(timeout)
+		}(i, p)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// tune 将每个子池的容量调整为 sizePerPool
+func (s shards[T]) tune(sizePerPool int) {
+	for _, p := range s {
+		p.Tune(sizePerPool)
+	}
+}
+
+// Reboot 重启所有已关闭的子池
+func (s shards[T]) Reboot() {
+	for _, p := range s {
+		p.Reboot()
+	}
+}
@@ -0,0 +1,40 @@
+package laborer
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// maxBackoff 自旋锁两次尝试之间让出处理器的最大次数
+const maxBackoff = 16
+
+// spinLock 带指数退避的自旋锁
+//
+// 池的临界区（从队列取出或放回一个 worker）很短，竞争时自旋重试
+// 比 sync.Mutex 让 goroutine 休眠再唤醒的开销更小。每次获取失败后
+// 让出处理器的次数翻倍，直到 maxBackoff，避免大量 goroutine 同时自旋。
+type spinLock uint32
+
+// newSpinLock 创建一个自旋锁
+func newSpinLock() sync.Locker {
+	return new(spinLock)
+}
+
+// Lock 获取锁，获取失败时按指数退避重试
+func (sl *spinLock) Lock() {
+	backoff := 1
+	for !atomic.CompareAndSwapUint32((*uint32)(sl), 0, 1) {
+		for i := 0; i < backoff; i++ {
+			runtime.Gosched()
+		}
+		if backoff < maxBackoff {
+			backoff <<= 1
+		}
+	}
+}
+
+// Unlock 释放锁
+func (sl *spinLock) Unlock() {
+	atomic.StoreUint32((*uint32)(sl), 0)
+}
@@ -0,0 +1,79 @@
+package laborer
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// TestSpinLock 测试自旋锁的互斥
+func TestSpinLock(t *testing.T) {
+	lock := newSpinLock()
+	counter := 0
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				lock.Lock()
+				counter++
+				lock.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if counter != 8000 {
+		t.Errorf("期望计数为 8000，实际为 %d", counter)
+	}
+}
+
+// TestPoolSpinLock 测试使用自旋锁的池在阻塞模式下正常等待和唤醒
+func TestPoolSpinLock(t *testing.T) {
+	pool, err := NewPool(-1, WithSpinLock(true))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	var count int32
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		if err := pool.Submit(func() {
+			defer wg.Done()
+			atomic.AddInt32(&count, 1)
+		}); err != nil {
+			wg.Done()
+			t.Fatalf("提交任务失败: %v", err)
+		}
+	}
+	wg.Wait()
+	if count != 100 {
+		t.Errorf("期望执行 100 个任务，实际 %d 个", count)
+	}
+
+	// 容量有限时提交者在条件变量上等待空闲 worker
+	fp, err := NewPoolWithFunc(2, func(interface{}) {
+		atomic.AddInt32(&count, 1)
+		wg.Done()
+	}, WithSpinLock(true))
+	if err != nil {
+		t.Fatalf("创建函数池失败: %v", err)
+	}
+	defer fp.Release()
+
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		if err := fp.Invoke(i); err != nil {
+			wg.Done()
+			t.Fatalf("提交任务失败: %v", err)
+		}
+	}
+	wg.Wait()
+	if count != 200 {
+		t.Errorf("期望执行 200 个任务，实际 %d 个", count)
+	}
+}
@@ -0,0 +1,251 @@
+package laborer
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultStatsInterval 状态订阅的默认推送间隔
+const DefaultStatsInterval = time.Second
+
+// Stats 表示池在某一时刻的运行状态快照。
+//
+// 其中 Running、Free、Idle、Cap、Waiting、Spilling 为瞬时值（gauge），
+// Submitted、Completed、Rejected、Failed、Panicked、Spilled 为自池创建以来单调递增的累计值（counter）。
+// 累计值使用 int64 存储，长时间运行的服务不会发生溢出回绕。
+//
+// 示例:
+//
+//	stats := pool.Stats()
+//	log.Printf("running=%d submitted=%d completed=%d panicked=%d",
+//	    stats.Running, stats.Submitted, stats.Completed, stats.Panicked)
+type Stats struct {
+	// Running 当前运行的 worker 数量
+	Running int
+
+	// Free 当前空闲的 worker 数量，无限容量的池为 -1
+	Free int
+
+	// Idle 空闲队列中 worker 的实际数量，对无限容量的池同样有效
+	Idle int
+
+	// Cap 池的容量
+	Cap int
+
+	// Waiting 等待执行的任务数量
+	Waiting int
+
+	// Spilling 当前正在运行的溢出 worker 数量（不计入 Running）
+	// 仅在启用 WithSpillover 时有值
+	Spilling int
+
+	// Submitted 成功提交的任务总数
+	Submitted int64
+
+	// Completed 正常执行完成的任务总数（包括返回错误的任务）
+	Completed int64
+
+	// Rejected 因池过载（ErrPoolOverload）被拒绝的任务总数，向已关闭的池提交（ErrPoolClosed）不计入
+	Rejected int64
+
+	// Failed 返回错误的任务总数（仅统计带返回值的任务）
+	Failed int64
+
+	// Panicked 执行过程中发生 panic 的任务总数
+	Panicked int64
+
+	// Spilled 池饱和时在溢出 worker 上执行的任务总数（已计入 Submitted）
+	Spilled int64
+
+	// QueueWait 任务从提交到开始执行的等待时间统计
+	// 仅在启用 WithLatencyHistogram 时有值
+	QueueWait LatencyStats
+
+	// Execution 任务的执行时间统计
+	// 仅在启用 WithLatencyHistogram 时有值
+	Execution LatencyStats
+}
+
+// NamedStats 由 SubmitNamed 提交的一类任务的统计，通过 Pool.NamedStats 按任务名称获取
+// Running 为瞬时值，Completed 和 Panicked 为累计值。
+type NamedStats struct {
+	// Running 正在执行的任务数量
+	Running int
+
+	// Completed 正常结束的任务总数
+	Completed int64
+
+	// Panicked 发生 panic 的任务总数
+	Panicked int64
+}
+
+// Check 检查快照中的瞬时值是否满足池的不变量，不满足时返回描述第一个违反项的错误
+// 运行计数只由 worker goroutine 在退出时扣减，Running、Idle、Waiting、Spilling 都不会为负数，
+// Free 只在无限容量（Cap 为 -1）时为 -1。适合在测试或调试时发现计数器漂移。
+func (s Stats) Check() error {
+	free := s.Free
+	if s.Cap == -1 && free == -1 {
+		free = 0
+	}
+
+	for _, g := range []struct {
+		name  string
+		value int
+	}{
+		{"running", s.Running},
+		{"free", free},
+		{"idle", s.Idle},
+		{"waiting", s.Waiting},
+		{"spilling", s.Spilling},
+	} {
+		if g.value < 0 {
+			return fmt.Errorf("stats: %s is negative: %d", g.name, g.value)
+		}
+	}
+	return nil
+}
+
+// OverloadInfo 描述一次因池过载而被拒绝的提交。
+//
+// 在 WithOverloadHandler 设置的回调中使用，
+// 包含拒绝发生时池的瞬时状态，便于输出有针对性的告警或触发扩容。
+type OverloadInfo struct {
+	// Pool 池的名称
+	Pool string
+
+	// Running 拒绝发生时运行的 worker 数量
+	Running int
+
+	// Waiting 拒绝发生时等待执行的任务数量
+	Waiting int
+
+	// Cap 池的容量
+	Cap int
+}
+
+// QuarantineInfo 描述函数池进入 panic 隔离状态时的信息
+//
+// 在 WithPanicQuarantine 设置的回调中使用。
+type QuarantineInfo struct {
+	// Pool 池的名称
+	Pool string
+
+	// Panics 触发隔离的连续 panic 次数
+	Panics int
+
+	// Until 隔离结束、恢复接收任务的时间
+	Until time.Time
+}
+
+// poolMetrics 保存池的累计计数器
+// 使用 atomic.Int64 保证 32 位平台上的对齐要求
+type poolMetrics struct {
+	submitted atomic.Int64
+	completed atomic.Int64
+	rejected  atomic.Int64
+	failed    atomic.Int64
+	panicked  atomic.Int64
+	spilled   atomic.Int64
+
+	// queueWait 和 execution 为延迟直方图，未启用时为 nil
+	queueWait *histogram
+	execution *histogram
+}
+
+// enableLatency 根据桶上界创建延迟直方图
+func (m *poolMetrics) enableLatency(buckets []time.Duration) {
+	if len(buckets) == 0 {
+		return
+	}
+	m.queueWait = newHistogram(buckets)
+	m.execution = newHistogram(buckets)
+}
+
+// fill 将累计计数器写入 Stats
+func (m *poolMetrics) fill(s *Stats) {
+	s.Submitted = m.submitted.Load()
+	s.Completed = m.completed.Load()
+	s.Rejected = m.rejected.Load()
+	s.Failed = m.failed.Load()
+	s.Panicked = m.panicked.Load()
+	s.Spilled = m.spilled.Load()
+
+	if m.queueWait != nil {
+		s.QueueWait = m.queueWait.snapshot()
+		s.Execution = m.execution.snapshot()
+	}
+}
+
+// merge 将另一个池的快照累加到 s 中，用于汇总分片池的状态
+// 容量为 -1（无限）的分片会使汇总后的 Cap 和 Free 也为 -1。
+func (s *Stats) merge(o Stats) {
+	s.Running += o.Running
+	s.Idle += o.Idle
+	s.Waiting += o.Waiting
+	s.Spilling += o.Spilling
+	if s.Cap == -1 || o.Cap == -1 {
+		s.Cap = -1
+		s.Free = -1
+	} else {
+		s.Cap += o.Cap
+		s.Free += o.Free
+	}
+
+	s.Submitted += o.Submitted
+	s.Completed += o.Completed
+	s.Rejected += o.Rejected
+	s.Failed += o.Failed
+	s.Panicked += o.Panicked
+	s.Spilled += o.Spilled
+
+	s.QueueWait.merge(o.QueueWait)
+	s.Execution.merge(o.Execution)
+}
+
+// subscribeStats 启动一个 goroutine 按 interval 周期性地推送状态快照
+//
+// 返回的 channel 缓冲为 1，消费者处理不及时时会丢弃旧快照，只保留最新的一份，
+// 因此订阅永远不会阻塞池的运行。调用返回的 unsubscribe 函数停止推送并关闭 channel，
+// 多次调用是安全的。
+func subscribeStats(interval time.Duration, snapshot func() Stats) (<-chan Stats, func()) {
+	if interval <= 0 {
+		interval = DefaultStatsInterval
+	}
+
+	ch := make(chan Stats, 1)
+	stop := make(chan struct{})
+	var once sync.Once
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer func() {
+			ticker.Stop()
+			close(ch)
+		}()
+
+		for {
+			select {
+			case <-ticker.C:
+				s := snapshot()
+				select {
+				case ch <- s:
+				default:
+					// 丢弃未被消费的旧快照，替换为最新的
+					select {
+					case <-ch:
+					default:
+					}
+					ch <- s
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	return ch, func() {
+		once.Do(func() { close(stop) })
+	}
+}
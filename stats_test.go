@@ -0,0 +1,346 @@
+package laborer
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestPoolStatsCounters 测试累计任务计数器
+func TestPoolStatsCounters(t *testing.T) {
+	pool, err := NewPool(1, WithNonblocking(true), WithPanicHandler(func(interface{}) {}))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	// 正常任务
+	done := make(chan struct{})
+	if err := pool.Submit(func() { close(done) }); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	<-done
+	time.Sleep(10 * time.Millisecond)
+
+	// 返回错误的任务
+	f, err := pool.SubmitWithResult(func() (interface{}, error) {
+		return nil, errors.New("boom")
+	})
+	if err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	_, _ = f.Get()
+	time.Sleep(10 * time.Millisecond)
+
+	// 占满池后被拒绝的任务
+	block := make(chan struct{})
+	if err := pool.Submit(func() { <-block }); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	if err := pool.Submit(func() {}); err != ErrPoolOverload {
+		t.Errorf("期望返回 ErrPoolOverload，实际返回: %v", err)
+	}
+	close(block)
+	time.Sleep(10 * time.Millisecond)
+
+	// 发生 panic 的任务
+	if err := pool.Submit(func() { panic("oops") }); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	stats := pool.Stats()
+	if stats.Submitted != 4 {
+		t.Errorf("Submitted 期望 4，实际 %d", stats.Submitted)
+	}
+	if stats.Completed != 3 {
+		t.Errorf("Completed 期望 3，实际 %d", stats.Completed)
+	}
+	if stats.Rejected != 1 {
+		t.Errorf("Rejected 期望 1，实际 %d", stats.Rejected)
+	}
+	if stats.Failed != 1 {
+		t.Errorf("Failed 期望 1，实际 %d", stats.Failed)
+	}
+	if stats.Panicked != 1 {
+		t.Errorf("Panicked 期望 1，实际 %d", stats.Panicked)
+	}
+	if stats.Cap != 1 {
+		t.Errorf("Cap 期望 1，实际 %d", stats.Cap)
+	}
+}
+
+// TestPoolLatencyHistogram 测试任务延迟直方图
+func TestPoolLatencyHistogram(t *testing.T) {
+	pool, err := NewPool(2, WithLatencyHistogram(time.Millisecond, 10*time.Millisecond, 100*time.Millisecond))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	for i := 0; i < 4; i++ {
+		f, err := pool.SubmitWithResult(func() (interface{}, error) {
+			time.Sleep(20 * time.Millisecond)
+			return nil, nil
+		})
+		if err != nil {
+			t.Fatalf("提交任务失败: %v", err)
+		}
+		defer f.Get()
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	stats := pool.Stats()
+	if stats.Execution.Count != 4 {
+		t.Fatalf("Execution.Count 期望 4，实际 %d", stats.Execution.Count)
+	}
+	if stats.Execution.Min < 20*time.Millisecond {
+		t.Errorf("Execution.Min 应该不小于 20ms，实际 %v", stats.Execution.Min)
+	}
+	if stats.Execution.P95 != 100*time.Millisecond && stats.Execution.P95 != stats.Execution.Max {
+		t.Errorf("Execution.P95 估算错误: %v", stats.Execution.P95)
+	}
+	if len(stats.Execution.Buckets) != 4 {
+		t.Errorf("期望 4 个桶（含 +Inf），实际 %d", len(stats.Execution.Buckets))
+	}
+	if stats.QueueWait.Count != 4 {
+		t.Errorf("QueueWait.Count 期望 4，实际 %d", stats.QueueWait.Count)
+	}
+}
+
+// TestHistogramSnapshot 测试直方图的百分位估算
+func TestHistogramSnapshot(t *testing.T) {
+	h := newHistogram([]time.Duration{10 * time.Millisecond, time.Millisecond})
+	for i := 0; i < 99; i++ {
+		h.observe(500 * time.Microsecond)
+	}
+	h.observe(time.Second)
+
+	s := h.snapshot()
+	if s.Count != 100 {
+		t.Fatalf("Count 期望 100，实际 %d", s.Count)
+	}
+	if s.P50 != time.Millisecond {
+		t.Errorf("P50 期望 1ms，实际 %v", s.P50)
+	}
+	if s.Max != time.Second {
+		t.Errorf("Max 期望 1s，实际 %v", s.Max)
+	}
+	if s.Buckets[2].Count != 1 {
+		t.Errorf("+Inf 桶期望 1 个样本，实际 %d", s.Buckets[2].Count)
+	}
+}
+
+// TestPoolSubscribeStats 测试状态订阅
+func TestPoolSubscribeStats(t *testing.T) {
+	pool, err := NewPool(3)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	ch, unsubscribe := pool.SubscribeStats(10 * time.Millisecond)
+
+	select {
+	case s := <-ch:
+		if s.Cap != 3 {
+			t.Errorf("Cap 期望 3，实际 %d", s.Cap)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("未收到状态快照")
+	}
+
+	unsubscribe()
+	unsubscribe()
+
+	// 取消订阅后 channel 应该被关闭
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case _, ok := <-ch:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatal("取消订阅后 channel 未关闭")
+		}
+	}
+}
+
+// TestStatsMerge 测试汇总多个快照
+func TestStatsMerge(t *testing.T) {
+	a := newHistogram([]time.Duration{time.Millisecond, 10 * time.Millisecond})
+	b := newHistogram([]time.Duration{time.Millisecond, 10 * time.Millisecond})
+	a.observe(500 * time.Microsecond)
+	b.observe(5 * time.Millisecond)
+	b.observe(20 * time.Millisecond)
+
+	var s Stats
+	s.merge(Stats{Running: 1, Cap: 2, Submitted: 3, Execution: a.snapshot()})
+	s.merge(Stats{Running: 2, Cap: 2, Submitted: 4, Execution: b.snapshot()})
+
+	if s.Running != 3 || s.Cap != 4 || s.Submitted != 7 {
+		t.Errorf("计数汇总不正确: %+v", s)
+	}
+
+	e := s.Execution
+	if e.Count != 3 || e.Min != 500*time.Microsecond || e.Max != 20*time.Millisecond {
+		t.Errorf("直方图汇总不正确: %+v", e)
+	}
+	if e.Buckets[0].Count != 1 || e.Buckets[1].Count != 1 || e.Buckets[2].Count != 1 {
+		t.Errorf("桶计数汇总不正确: %+v", e.Buckets)
+	}
+	if e.P50 != 10*time.Millisecond {
+		t.Errorf("P50 期望 10ms，实际 %v", e.P50)
+	}
+
+	s.merge(Stats{Cap: -1, Free: -1, Idle: 2})
+	if s.Cap != -1 || s.Free != -1 {
+		t.Errorf("包含无限容量时 Cap 和 Free 应该为 -1，实际 Cap=%d Free=%d", s.Cap, s.Free)
+	}
+	if s.Idle != 2 {
+		t.Errorf("Idle 汇总不正确: %d", s.Idle)
+	}
+	if err := s.Check(); err != nil {
+		t.Errorf("无限容量的快照不应该违反不变量: %v", err)
+	}
+}
+
+// TestPoolWithFuncStatsParity 测试函数池与通用池拥有相同的统计和钩子
+func TestPoolWithFuncStatsParity(t *testing.T) {
+	completed := make(chan TaskInfo, 2)
+	panics := make(chan TaskInfo, 1)
+	opts := []Option{
+		WithName("parity"),
+		WithLatencyHistogram(),
+		WithTaskHooks(nil, func(info TaskInfo) { completed <- info }),
+		WithPanicHandlerV2(func(_ interface{}, _ []byte, info TaskInfo) { panics <- info }),
+	}
+
+	pool, err := NewPoolWithFunc(1, func(arg interface{}) {
+		if arg == "panic" {
+			panic("oops")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}, opts...)
+	if err != nil {
+		t.Fatalf("创建函数池失败: %v", err)
+	}
+	defer pool.Release()
+
+	var obs Observable = pool
+	_ = pool.Invoke("ok")
+	_ = pool.Invoke("panic")
+
+	for i := 0; i < 2; i++ {
+		select {
+		case info := <-completed:
+			if info.Pool != "parity" || info.SubmittedAt.IsZero() {
+				t.Errorf("任务元数据不正确: %+v", info)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("等待 onComplete 超时")
+		}
+	}
+
+	select {
+	case info := <-panics:
+		if info.Panic != "oops" || info.StartedAt.IsZero() {
+			t.Errorf("panic 任务元数据不正确: %+v", info)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("等待 panic 处理函数超时")
+	}
+
+	s := obs.Stats()
+	if s.Submitted != 2 || s.Panicked != 1 {
+		t.Errorf("计数不正确: %+v", s)
+	}
+	if s.Execution.Count != 2 || s.QueueWait.Count != 2 {
+		t.Errorf("直方图期望记录 2 次，实际 execution=%d queueWait=%d", s.Execution.Count, s.QueueWait.Count)
+	}
+
+	// 通用池同样实现了 Observable
+	p, err := NewPool(1, opts...)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer p.Release()
+	obs = p
+	if obs.Name() != "parity" {
+		t.Errorf("Name() 期望 parity，实际 %q", obs.Name())
+	}
+}
+
+// TestStatsCheckAfterExpiry 测试过期清理后运行计数不会被重复扣减
+func TestStatsCheckAfterExpiry(t *testing.T) {
+	pool, err := NewPool(4,
+		WithExpiryDuration(10*time.Millisecond),
+		WithCleanInterval(5*time.Millisecond))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	for i := 0; i < 4; i++ {
+		if err := pool.Submit(func() {}); err != nil {
+			t.Fatalf("提交任务失败: %v", err)
+		}
+	}
+	waitFor(t, func() bool { return pool.Running() == 0 })
+
+	// 多等待几轮清理，计数不应继续减少
+	time.Sleep(30 * time.Millisecond)
+	if err := pool.Stats().Check(); err != nil {
+		t.Fatalf("状态不满足不变量: %v", err)
+	}
+	if n := pool.Running(); n != 0 {
+		t.Errorf("期望运行计数为 0，实际 %d", n)
+	}
+
+	if err := (Stats{Running: -1}).Check(); err == nil {
+		t.Error("负数的运行计数应该违反不变量")
+	}
+}
+
+// TestStatsClosedNotRejected 测试向已关闭的池提交不计入被拒绝的任务数
+func TestStatsClosedNotRejected(t *testing.T) {
+	pool, err := NewPool(1, WithNonblocking(true))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	pool.Release()
+
+	if err := pool.Submit(func() {}); err != ErrPoolClosed {
+		t.Errorf("Submit 期望返回 ErrPoolClosed，实际返回: %v", err)
+	}
+	if err := pool.SubmitMany(func() {}, func() {}); err != ErrPoolClosed {
+		t.Errorf("SubmitMany 期望返回 ErrPoolClosed，实际返回: %v", err)
+	}
+	// 取得 worker 失败之后池被关闭
+	if err := pool.overload(2); err != ErrPoolClosed {
+		t.Errorf("overload 期望返回 ErrPoolClosed，实际返回: %v", err)
+	}
+	if n := pool.Stats().Rejected; n != 0 {
+		t.Errorf("Rejected 期望 0，实际 %d", n)
+	}
+
+	fp, err := NewPoolWithFunc(1, func(interface{}) {}, WithNonblocking(true))
+	if err != nil {
+		t.Fatalf("创建函数池失败: %v", err)
+	}
+	fp.Release()
+
+	if err := fp.Invoke(1); err != ErrPoolClosed {
+		t.Errorf("Invoke 期望返回 ErrPoolClosed，实际返回: %v", err)
+	}
+	if err := fp.InvokeWithTimeout(1, time.Millisecond); err != ErrPoolClosed {
+		t.Errorf("InvokeWithTimeout 期望返回 ErrPoolClosed，实际返回: %v", err)
+	}
+	if err := fp.overload(); err != ErrPoolClosed {
+		t.Errorf("overload 期望返回 ErrPoolClosed，实际返回: %v", err)
+	}
+	if n := fp.Stats().Rejected; n != 0 {
+		t.Errorf("Rejected 期望 0，实际 %d", n)
+	}
+}
@@ -0,0 +1,196 @@
+package laborer
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// TaskInfo 描述一次任务执行的元数据。
+//
+// 在任务钩子（WithTaskHooks）中使用：onStart 调用时 Duration、Err、Panic 尚未赋值，
+// onComplete 调用时所有字段均已确定。
+type TaskInfo struct {
+	// Pool 任务所属池的名称
+	Pool string
+
+	// WorkerID 执行任务的 worker 的编号，与日志、worker 钩子和 DumpStacks 中的编号一致
+	WorkerID int
+
+	// Name 任务的名称，通过 SubmitNamed 提交时设置，其余任务为空
+	Name string
+
+	// SubmittedAt 任务的提交时间
+	SubmittedAt time.Time
+
+	// StartedAt 任务开始执行的时间
+	StartedAt time.Time
+
+	// QueueWait 任务从提交到开始执行的等待时间
+	QueueWait time.Duration
+
+	// Duration 任务的执行时间
+	Duration time.Duration
+
+	// Err 带返回值的任务返回的错误
+	Err error
+
+	// Panic 任务发生 panic 时恢复的值，未发生 panic 时为 nil
+	Panic interface{}
+}
+
+// Task 可以提交到池中执行的任务对象
+// 任务需要携带自己的字段或元数据时，实现 Task 的结构体可以直接通过 SubmitTask 提交，
+// 不必再包装成闭包。
+type Task interface {
+	// Run 执行任务
+	Run()
+}
+
+// TaskWithResult 可以提交到池中执行的带返回值的任务对象，通过 SubmitTaskWithResult 提交
+type TaskWithResult interface {
+	// Run 执行任务并返回结果
+	Run() (interface{}, error)
+}
+
+// taskItem 表示投递给 worker 的一个任务
+// 以值的形式通过 channel 传递，不会产生额外的内存分配。
+type taskItem struct {
+	// run 无返回值的任务
+	run func()
+
+	// runState 使用 per-worker 资源的任务
+	runState func(state interface{})
+
+	// call 带返回值的任务，结果写入 future
+	call func() (interface{}, error)
+
+	// future 接收 call 的执行结果
+	future *future
+
+	// name 任务的名称，通过 SubmitNamed 提交时设置
+	name string
+
+	// submitted 提交时间，仅在需要记录任务元数据或追踪任务时赋值
+	submitted time.Time
+
+	// id 追踪日志中的任务 ID，未开启追踪时为 0
+	id uint64
+
+	// stop 为 true 表示 worker 应该退出
+	stop bool
+}
+
+// isStop 检查是否为退出信号
+func (t *taskItem) isStop() bool {
+	return t.stop
+}
+
+// isNil 检查任务是否没有可执行的函数，这样的任务在提交时被拒绝
+func (t *taskItem) isNil() bool {
+	return t.run == nil && t.runState == nil && t.call == nil
+}
+
+// hasNilTask 检查一组任务中是否有 nil
+func hasNilTask(tasks []func()) bool {
+	for _, task := range tasks {
+		if task == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// trackTasks 检查是否需要记录任务的时间元数据
+// 只有启用了延迟统计或任务钩子时才需要，避免默认配置下的 time.Now 开销
+func trackTasks(opts *Options) bool {
+	return len(opts.LatencyBuckets) > 0 || opts.OnTaskStart != nil || opts.OnTaskComplete != nil ||
+		opts.PanicHandlerV2 != nil
+}
+
+// beginTask 记录任务开始执行，返回任务元数据
+func beginTask(opts *Options, m *poolMetrics, workerID int, name string, submitted time.Time) TaskInfo {
+	now := time.Now()
+	info := TaskInfo{
+		Pool:        opts.Name,
+		WorkerID:    workerID,
+		Name:        name,
+		SubmittedAt: submitted,
+		StartedAt:   now,
+		QueueWait:   now.Sub(submitted),
+	}
+
+	if m.queueWait != nil {
+		m.queueWait.observe(info.QueueWait)
+	}
+	if opts.OnTaskStart != nil {
+		opts.OnTaskStart(info)
+	}
+
+	return info
+}
+
+// endTask 记录任务执行结束（包括 panic 的情况）
+func endTask(opts *Options, m *poolMetrics, info *TaskInfo) {
+	info.Duration = time.Since(info.StartedAt)
+
+	if m.execution != nil {
+		m.execution.observe(info.Duration)
+	}
+	if opts.OnTaskComplete != nil {
+		opts.OnTaskComplete(*info)
+	}
+}
+
+// namedTasks 按任务名称累计的计数器，记录 SubmitNamed 提交的任务
+// 名称的数量由调用方使用过的名称决定，因此名称应当取自有限的集合。
+type namedTasks struct {
+	// counters 任务名称到 *namedCounters 的映射，只增不删
+	counters sync.Map
+}
+
+// namedCounters 一类带名称任务的计数器
+type namedCounters struct {
+	running   atomic.Int32
+	completed atomic.Int64
+	panicked  atomic.Int64
+}
+
+// get 返回名称对应的计数器，第一次使用时创建
+func (n *namedTasks) get(name string) *namedCounters {
+	if c, ok := n.counters.Load(name); ok {
+		return c.(*namedCounters)
+	}
+	c, _ := n.counters.LoadOrStore(name, &namedCounters{})
+	return c.(*namedCounters)
+}
+
+// begin 记录一个带名称的任务开始执行
+func (c *namedCounters) begin() {
+	c.running.Add(1)
+}
+
+// end 记录一个带名称的任务执行结束，panicked 表示任务是否发生了 panic
+func (c *namedCounters) end(panicked bool) {
+	c.running.Add(-1)
+	if panicked {
+		c.panicked.Add(1)
+	} else {
+		c.completed.Add(1)
+	}
+}
+
+// snapshot 返回所有名称的计数快照
+func (n *namedTasks) snapshot() map[string]NamedStats {
+	stats := make(map[string]NamedStats)
+	n.counters.Range(func(key, value interface{}) bool {
+		c := value.(*namedCounters)
+		stats[key.(string)] = NamedStats{
+			Running:   int(c.running.Load()),
+			Completed: c.completed.Load(),
+			Panicked:  c.panicked.Load(),
+		}
+		return true
+	})
+	return stats
+}
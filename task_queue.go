@@ -0,0 +1,243 @@
+package laborer
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// TaskQueue 函数池的待执行任务队列，通过 WithTaskQueue 设置
+//
+// 设置后函数池没有空闲 worker 时，提交的参数放入队列并立即返回，
+// worker 执行完当前的调用后从队列中取出下一个参数继续执行。
+// 默认的实现是 NewMemoryTaskQueue 创建的内存队列；以 Redis 或磁盘实现此接口，
+// 可以让尚未执行的任务在进程重启后继续执行。
+//
+// 方法会被多个 goroutine 并发调用，实现需要自行保证线程安全。
+// 池创建时开始执行队列中已有的参数。通过池提交的参数从提交起计为待执行的调用，
+// 由其他来源放入队列的参数在取出时才计入，Wait 和 Drain 不会等待尚未取出的这些参数。
+type TaskQueue interface {
+	// Push 将参数追加到队列末尾
+	// 队列已满时返回 ErrTaskQueueFull，提交按未设置队列的规则处理；
+	// 返回其他错误时提交失败并返回该错误。
+	Push(args interface{}) error
+
+	// Pop 取出队列中最早的参数，队列为空时返回 ErrTaskQueueEmpty
+	Pop() (interface{}, error)
+
+	// Len 返回队列中的参数数量
+	Len() int
+}
+
+// TaskAcker 由需要确认的 TaskQueue 实现
+//
+// 队列实现了此接口时，从队列取出的参数执行结束（包括 panic）后调用 Ack。
+// 持久化队列可以在 Pop 时只标记参数，在 Ack 时才删除记录，
+// 进程在执行期间退出时，未确认的参数在重启后重新执行。
+type TaskAcker interface {
+	// Ack 确认参数已执行结束，返回的错误通过 WithErrorHandler 上报
+	Ack(args interface{}) error
+}
+
+// memoryTaskQueue 内存中的 FIFO 任务队列
+type memoryTaskQueue struct {
+	mu sync.Mutex
+
+	// items 队列中的参数，head 为队首的位置
+	items []interface{}
+	head  int
+
+	// size 队列容量，小于等于 0 表示不限制
+	size int
+}
+
+// NewMemoryTaskQueue 创建内存中的 FIFO 任务队列
+// size 为队列容量，小于等于 0 表示不限制。队列中的参数在进程退出后丢失。
+func NewMemoryTaskQueue(size int) TaskQueue {
+	return &memoryTaskQueue{size: size}
+}
+
+// Push 实现 TaskQueue 接口
+func (q *memoryTaskQueue) Push(args interface{}) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.size > 0 && len(q.items)-q.head >= q.size {
+		return ErrTaskQueueFull
+	}
+
+	// 已取出的部分超过一半时整体前移，避免底层数组无限增长
+	if q.head > 0 && q.head*2 >= len(q.items) {
+		n := copy(q.items, q.items[q.head:])
+		clear(q.items[n:])
+		q.items = q.items[:n]
+		q.head = 0
+	}
+	q.items = append(q.items, args)
+	return nil
+}
+
+// Pop 实现 TaskQueue 接口
+func (q *memoryTaskQueue) Pop() (interface{}, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.head == len(q.items) {
+		return nil, ErrTaskQueueEmpty
+	}
+
+	args := q.items[q.head]
+	q.items[q.head] = nil
+	q.head++
+	return args, nil
+}
+
+// Len 实现 TaskQueue 接口
+func (q *memoryTaskQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.items) - q.head
+}
+
+// enqueue 在设置了任务队列时投递调用：队列为空且有空闲 worker 时直接交给 worker，
+// 否则放入队列。队列已满时返回 false，调用方按未设置队列的规则投递。
+func (p *PoolWithFunc) enqueue(inv invocation) (bool, error) {
+	q := p.options.TaskQueue
+	if q.Len() == 0 {
+		if w := p.tryGetWorker(); w != nil {
+			p.metrics.submitted.Add(1)
+			w.args <- inv
+			return true, nil
+		}
+	}
+
+	// 先记录再放入，保证 worker 取出这个参数时能看到记录
+	p.queued.Add(1)
+	if err := q.Push(inv.args); err != nil {
+		p.unqueue()
+		if errors.Is(err, ErrTaskQueueFull) {
+			return false, nil
+		}
+		traceRejected(p.options, inv.id, err)
+		return false, err
+	}
+	traceQueued(p.options, inv.id)
+	// 队列中的调用不占用预算，取出执行时再占用
+	p.releaseBudget(inv)
+
+	// 放入队列期间可能有 worker 变为空闲
+	p.kick()
+	return true, nil
+}
+
+// kick 取得一个空闲 worker 并交给它队列中最早的参数
+// 没有空闲 worker、队列为空或取出失败时返回 false。
+func (p *PoolWithFunc) kick() bool {
+	for p.isOpen() {
+		w := p.tryGetWorker()
+		if w == nil {
+			return false
+		}
+
+		inv, err := p.dequeue()
+		if err == nil {
+			w.args <- inv
+			return true
+		}
+		if !p.putWorker(w) {
+			w.finish()
+			return false
+		}
+
+		// 持有 worker 期间其他提交方的 kick 取不到 worker，
+		// 归还后队列中又有参数时由这里继续投递
+		if !errors.Is(err, ErrTaskQueueEmpty) || p.options.TaskQueue.Len() == 0 {
+			return false
+		}
+	}
+	return false
+}
+
+// drainQueue 将队列中的参数交给所有可用的 worker
+// 用于创建、恢复、重启和扩容之后，此时可能没有 worker 正在从队列中取参数。
+func (p *PoolWithFunc) drainQueue() {
+	if p.options.TaskQueue == nil {
+		return
+	}
+	for p.kick() {
+	}
+}
+
+// afterPut worker 变为空闲后检查队列
+// worker 在取队列为空之后、变为空闲之前，提交方可能放入了参数却没有取得 worker，
+// 由这里把这些参数交给空闲的 worker。
+func (p *PoolWithFunc) afterPut() {
+	if q := p.options.TaskQueue; q != nil && q.Len() > 0 {
+		p.kick()
+	}
+}
+
+// nextQueued 执行完一次调用的 worker 从队列中取出下一个参数
+// 池已关闭、已暂停或缩容后运行的 worker 超过容量时返回 false，worker 按原来的规则归还或退出。
+func (p *PoolWithFunc) nextQueued(inv *invocation) bool {
+	if p.options.TaskQueue == nil || !p.isOpen() || p.IsPaused() {
+		return false
+	}
+	if capacity := atomic.LoadInt32(&p.capacity); capacity != -1 && atomic.LoadInt32(&p.running) > capacity {
+		return false
+	}
+
+	next, err := p.dequeue()
+	if err != nil {
+		return false
+	}
+	*inv = next
+	return true
+}
+
+// dequeue 从队列中取出最早的参数
+// 队列为空时返回 ErrTaskQueueEmpty；取出失败时上报 ErrTaskQueue 并返回该错误。
+func (p *PoolWithFunc) dequeue() (invocation, error) {
+	args, err := p.options.TaskQueue.Pop()
+	if err != nil {
+		if !errors.Is(err, ErrTaskQueueEmpty) {
+			p.options.reportError(fmt.Errorf("%w: pop: %w", ErrTaskQueue, err))
+		}
+		return invocation{}, err
+	}
+
+	p.unqueue()
+	p.metrics.submitted.Add(1)
+	inv := invocation{args: args, queued: true}
+	if p.trackTasks {
+		inv.submitted = time.Now()
+	}
+	return inv, nil
+}
+
+// unqueue 扣减一个本池放入队列的调用记录
+// 没有记录可扣减时，说明取出的参数由其他来源放入，或者记录已被这样的参数扣减，
+// 此时为其补记 inflight，使每个取出的参数都恰好计入一次。
+func (p *PoolWithFunc) unqueue() {
+	for {
+		n := p.queued.Load()
+		if n <= 0 {
+			p.inflight.Add(1)
+			return
+		}
+		if p.queued.CompareAndSwap(n, n-1) {
+			return
+		}
+	}
+}
+
+// ack 确认从队列取出的参数已执行结束
+func (p *PoolWithFunc) ack(args interface{}) {
+	acker, ok := p.options.TaskQueue.(TaskAcker)
+	if !ok {
+		return
+	}
+	if err := acker.Ack(args); err != nil {
+		p.options.reportError(fmt.Errorf("%w: ack: %w", ErrTaskQueue, err))
+	}
+}
@@ -0,0 +1,253 @@
+package laborer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// ackQueue 记录确认的参数的内存队列
+type ackQueue struct {
+	TaskQueue
+
+	mu    sync.Mutex
+	acked []interface{}
+}
+
+func (q *ackQueue) Ack(args interface{}) error {
+	q.mu.Lock()
+	q.acked = append(q.acked, args)
+	q.mu.Unlock()
+	return nil
+}
+
+// TestTaskQueue 测试池饱和时参数进入队列，由 worker 依次执行
+func TestTaskQueue(t *testing.T) {
+	var sum, active, maxActive int64
+	pool, err := NewPoolWithFunc(2, func(args interface{}) {
+		cur := atomic.AddInt64(&active, 1)
+		for {
+			old := atomic.LoadInt64(&maxActive)
+			if cur <= old || atomic.CompareAndSwapInt64(&maxActive, old, cur) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		atomic.AddInt64(&sum, int64(args.(int)))
+		atomic.AddInt64(&active, -1)
+	}, WithNonblocking(true), WithTaskQueue(NewMemoryTaskQueue(0)))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	for i := 1; i <= 100; i++ {
+		if err := pool.Invoke(i); err != nil {
+			t.Fatalf("提交调用失败: %v", err)
+		}
+	}
+	if pool.Waiting() == 0 {
+		t.Error("池饱和时 Waiting 应该包括队列中的参数")
+	}
+
+	pool.Wait()
+	if got := atomic.LoadInt64(&sum); got != 5050 {
+		t.Errorf("期望总和为 5050，实际为 %d", got)
+	}
+	if m := atomic.LoadInt64(&maxActive); m > 2 {
+		t.Errorf("并发度 %d 超过了池容量 2", m)
+	}
+	if s := pool.Stats(); s.Rejected != 0 || s.Submitted != 100 || s.Waiting != 0 {
+		t.Errorf("期望提交 100 次、没有拒绝且没有等待，实际 %+v", s)
+	}
+}
+
+// TestTaskQueueFull 测试队列已满时按非阻塞配置返回 ErrPoolOverload
+func TestTaskQueueFull(t *testing.T) {
+	block := make(chan struct{})
+	pool, err := NewPoolWithFunc(1, func(interface{}) { <-block },
+		WithNonblocking(true), WithTaskQueue(NewMemoryTaskQueue(1)))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	if err := pool.Invoke(1); err != nil {
+		t.Fatalf("提交调用失败: %v", err)
+	}
+	if err := pool.Invoke(2); err != nil {
+		t.Fatalf("队列未满时应该放入队列，实际 %v", err)
+	}
+	if err := pool.Invoke(3); err != ErrPoolOverload {
+		t.Errorf("队列已满时期望返回 ErrPoolOverload，实际 %v", err)
+	}
+	if pool.Waiting() != 1 {
+		t.Errorf("期望 1 个参数在队列中，实际 %d", pool.Waiting())
+	}
+
+	close(block)
+	pool.Wait()
+}
+
+// TestTaskQueueRestore 测试创建池时执行队列中已有的参数并确认
+func TestTaskQueueRestore(t *testing.T) {
+	q := &ackQueue{TaskQueue: NewMemoryTaskQueue(0)}
+	for i := 0; i < 10; i++ {
+		_ = q.Push(i)
+	}
+
+	var n atomic.Int64
+	pool, err := NewPoolWithFunc(3, func(interface{}) { n.Add(1) }, WithTaskQueue(q))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	// 队列中已有的参数在取出时才计入 inflight，等待全部确认
+	waitFor(t, func() bool {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		return len(q.acked) == 10
+	})
+	if n.Load() != 10 {
+		t.Errorf("期望执行 10 次，实际 %d", n.Load())
+	}
+	waitFor(t, func() bool { return pool.inflight.Load() == 0 })
+}
+
+// TestTaskQueueRestoreContextFunc 测试带上下文的函数池在创建好上下文之后才执行队列中已有的参数
+func TestTaskQueueRestoreContextFunc(t *testing.T) {
+	q := NewMemoryTaskQueue(0)
+	for i := 0; i < 10; i++ {
+		_ = q.Push(i)
+	}
+
+	var n atomic.Int64
+	pool, err := NewPoolWithContextFunc(3, func(ctx context.Context, _ interface{}) {
+		if ctx.Err() == nil {
+			n.Add(1)
+		}
+	}, WithTaskQueue(q))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	waitFor(t, func() bool { return n.Load() == 10 })
+	waitFor(t, func() bool { return pool.inflight.Load() == 0 })
+}
+
+// TestTaskQueueForeignItems 测试其他来源放入队列的参数在取出时计入 inflight，计数不会漂移
+func TestTaskQueueForeignItems(t *testing.T) {
+	q := NewMemoryTaskQueue(0)
+	block := make(chan struct{})
+	var n atomic.Int64
+	pool, err := NewPoolWithFunc(1, func(interface{}) {
+		<-block
+		n.Add(1)
+	}, WithTaskQueue(q))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	for i := 0; i < 5; i++ {
+		if err := pool.Invoke(i); err != nil {
+			t.Fatalf("提交调用失败: %v", err)
+		}
+		_ = q.Push(-i)
+	}
+	close(block)
+
+	waitFor(t, func() bool { return n.Load() == 10 })
+	waitFor(t, func() bool { return pool.inflight.Load() == 0 })
+	if err := pool.WaitWithTimeout(time.Second); err != nil {
+		t.Errorf("全部执行完毕后 Wait 应该立即返回，实际 %v", err)
+	}
+}
+
+// TestTaskQueuePause 测试暂停期间参数留在队列中，恢复后执行
+func TestTaskQueuePause(t *testing.T) {
+	var n atomic.Int64
+	pool, err := NewPoolWithFunc(2, func(interface{}) { n.Add(1) }, WithTaskQueue(NewMemoryTaskQueue(0)))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	pool.Pause()
+	for i := 0; i < 5; i++ {
+		if err := pool.Invoke(i); err != nil {
+			t.Fatalf("暂停时应该放入队列，实际 %v", err)
+		}
+	}
+	if pool.Waiting() != 5 || n.Load() != 0 {
+		t.Fatalf("暂停时参数应该留在队列中，Waiting = %d，执行 %d 次", pool.Waiting(), n.Load())
+	}
+
+	pool.Resume()
+	if err := pool.WaitWithTimeout(time.Second); err != nil {
+		t.Fatalf("恢复后等待执行失败: %v", err)
+	}
+	if n.Load() != 5 {
+		t.Errorf("期望执行 5 次，实际 %d", n.Load())
+	}
+}
+
+// failingQueue Pop 总是失败的队列
+type failingQueue struct{}
+
+func (failingQueue) Push(interface{}) error    { return nil }
+func (failingQueue) Pop() (interface{}, error) { return nil, errors.New("connection refused") }
+func (failingQueue) Len() int                  { return 1 }
+
+// TestTaskQueueErrors 测试取出失败时上报 ErrTaskQueue
+func TestTaskQueueErrors(t *testing.T) {
+	var reported atomic.Value
+	pool, err := NewPoolWithFunc(1, func(interface{}) {}, WithTaskQueue(failingQueue{}),
+		WithErrorHandler(func(err error) { reported.Store(err) }))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	err, _ = reported.Load().(error)
+	if !errors.Is(err, ErrTaskQueue) {
+		t.Errorf("期望上报 ErrTaskQueue，实际 %v", err)
+	}
+}
+
+// TestTaskQueuePoolRejected 测试 Pool 拒绝 TaskQueue
+func TestTaskQueuePoolRejected(t *testing.T) {
+	if _, err := NewPool(1, WithTaskQueue(NewMemoryTaskQueue(0))); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("期望返回 ErrInvalidOption，实际 %v", err)
+	}
+}
+
+// TestMemoryTaskQueue 测试内存队列的 FIFO 顺序和容量
+func TestMemoryTaskQueue(t *testing.T) {
+	q := NewMemoryTaskQueue(3)
+	if _, err := q.Pop(); err != ErrTaskQueueEmpty {
+		t.Fatalf("空队列期望返回 ErrTaskQueueEmpty，实际 %v", err)
+	}
+
+	next := 0
+	for round := 0; round < 10; round++ {
+		for q.Len() < 3 {
+			if err := q.Push(next); err != nil {
+				t.Fatalf("推入失败: %v", err)
+			}
+			next++
+		}
+		if err := q.Push(next); err != ErrTaskQueueFull {
+			t.Fatalf("队列已满时期望返回 ErrTaskQueueFull，实际 %v", err)
+		}
+		v, _ := q.Pop()
+		if v != round {
+			t.Fatalf("期望按 FIFO 顺序取出 %d，实际 %v", round, v)
+		}
+	}
+}
@@ -0,0 +1,343 @@
+package laborer
+
+import (
+	"sync"
+	"time"
+)
+
+// TenantConfig 定义一个租户（或任务类别）的调度配置
+type TenantConfig struct {
+	// MaxConcurrency 该租户同时执行的任务上限，0 表示只受池容量限制
+	MaxConcurrency int
+
+	// Weight 该租户分配剩余容量时的权重，小于等于 0 时按 1 处理
+	Weight int
+
+	// MaxQueued 该租户排队任务的上限，超过时 Submit 返回 ErrPoolOverload
+	// 0 表示不限制
+	MaxQueued int
+}
+
+// TenantStats 一个租户的运行状态快照
+type TenantStats struct {
+	// Running 该租户正在执行的任务数量
+	Running int
+
+	// Queued 该租户排队等待的任务数量
+	Queued int
+}
+
+// tenant 一个租户的任务队列和运行计数
+type tenant struct {
+	config  TenantConfig
+	queue   []func()
+	running int
+}
+
+// weight 返回租户的有效权重
+func (t *tenant) weight() int {
+	if t.config.Weight <= 0 {
+		return 1
+	}
+	return t.config.Weight
+}
+
+// eligible 返回租户是否有任务且未达到并发上限
+func (t *tenant) eligible() bool {
+	if len(t.queue) == 0 {
+		return false
+	}
+	return t.config.MaxConcurrency <= 0 || t.running < t.config.MaxConcurrency
+}
+
+// TenantPool 多租户调度池，在一个 Pool 之上按租户隔离和公平分配容量
+//
+// 每次提交都带有租户（或任务类别）的键，每个租户有自己的排队队列。
+// 池有空闲容量时，在未达到并发上限的租户中选择 running/weight 最小的
+// 租户执行其最早的任务，因此一个流量很大的租户最多占用其并发上限，
+// 剩余容量按权重在其他有任务的租户间分配，不会饿死其他租户。
+//
+// 示例:
+//
+//	tp, _ := laborer.NewTenantPool(100)
+//	defer tp.Release()
+//
+//	tp.SetTenant("free", laborer.TenantConfig{MaxConcurrency: 10, Weight: 1})
+//	tp.SetTenant("paid", laborer.TenantConfig{Weight: 4})
+//
+//	tp.Submit(req.TenantID, handle)
+type TenantPool struct {
+	pool *Pool
+
+	mu       sync.Mutex
+	tenants  map[string]*tenant
+	order    []*tenant
+	next     int
+	defaults TenantConfig
+
+	// active 已启动的调度循环数量，idle 其中尚未取到任务的数量
+	active int
+	idle   int
+}
+
+// NewTenantPool 创建一个新的多租户调度池
+// size: 池的容量，即所有租户同时执行的任务上限，-1 表示无限容量
+// options: 配置选项，与 NewPool 相同；调度由 TenantPool 负责，
+// 提交不会因底层池已满而被拒绝，Nonblocking 选项不生效
+func NewTenantPool(size int, options ...Option) (*TenantPool, error) {
+	pool, err := NewPool(size, append(options, WithNonblocking(false))...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TenantPool{
+		pool:    pool,
+		tenants: make(map[string]*tenant),
+	}, nil
+}
+
+// SetDefaultTenant 设置未通过 SetTenant 配置的租户所使用的配置
+// 只影响之后第一次出现的租户。
+func (tp *TenantPool) SetDefaultTenant(config TenantConfig) {
+	tp.mu.Lock()
+	tp.defaults = config
+	tp.mu.Unlock()
+}
+
+// SetTenant 设置一个租户的调度配置，可以在运行时调整
+func (tp *TenantPool) SetTenant(key string, config TenantConfig) {
+	tp.mu.Lock()
+	tp.tenant(key).config = config
+	tp.mu.Unlock()
+
+	tp.schedule()
+}
+
+// Submit 以租户 key 的身份提交一个任务
+// 任务先进入该租户的队列，由调度按并发上限和权重决定执行时机。
+func (tp *TenantPool) Submit(key string, task func()) error {
+	if tp.pool.IsClosed() {
+		return ErrPoolClosed
+	}
+	if task == nil {
+		return ErrNilTask
+	}
+
+	tp.mu.Lock()
+	t := tp.tenant(key)
+	if t.config.MaxQueued > 0 && len(t.queue) >= t.config.MaxQueued {
+		tp.mu.Unlock()
+		tp.pool.reject()
+		return ErrPoolOverload
+	}
+	t.queue = append(t.queue, task)
+	tp.mu.Unlock()
+
+	return tp.schedule()
+}
+
+// TenantStats 返回租户 key 的运行状态快照
+func (tp *TenantPool) TenantStats(key string) TenantStats {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+
+	t, ok := tp.tenants[key]
+	if !ok {
+		return TenantStats{}
+	}
+	return TenantStats{Running: t.running, Queued: len(t.queue)}
+}
+
+// tenant 返回租户 key 的状态，不存在时按默认配置创建
+// 调用方必须持有 mu
+func (tp *TenantPool) tenant(key string) *tenant {
+	t, ok := tp.tenants[key]
+	if !ok {
+		t = &tenant{config: tp.defaults}
+		tp.tenants[key] = t
+		tp.order = append(tp.order, t)
+	}
+	return t
+}
+
+// schedule 在容量允许且有可执行任务时启动新的调度循环
+func (tp *TenantPool) schedule() error {
+	for {
+		tp.mu.Lock()
+		if !tp.hasCapacity() || tp.runnable() <= tp.idle {
+			tp.mu.Unlock()
+			return nil
+		}
+		tp.active++
+		tp.idle++
+		tp.mu.Unlock()
+
+		if err := tp.pool.Submit(tp.loop); err != nil {
+			tp.mu.Lock()
+			tp.active--
+			tp.idle--
+			tp.mu.Unlock()
+			return err
+		}
+	}
+}
+
+// hasCapacity 返回是否还能启动新的调度循环
+// 调用方必须持有 mu
+func (tp *TenantPool) hasCapacity() bool {
+	capacity := tp.pool.Cap()
+	return capacity == -1 || tp.active < capacity
+}
+
+// runnable 返回在各租户并发上限内可以立即执行的任务数量
+// 调用方必须持有 mu
+func (tp *TenantPool) runnable() int {
+	n := 0
+	for _, t := range tp.order {
+		if !t.eligible() {
+			continue
+		}
+		k := len(t.queue)
+		if limit := t.config.MaxConcurrency; limit > 0 && limit-t.running < k {
+			k = limit - t.running
+		}
+		n += k
+	}
+	return n
+}
+
+// pick 在可执行的租户中选择 running/weight 最小的一个，没有时返回 nil
+// 从上次选中的下一个租户开始比较，权重份额相同的租户轮流执行。
+// 调用方必须持有 mu
+func (tp *TenantPool) pick() *tenant {
+	var best *tenant
+	bestIdx := 0
+	for k := range tp.order {
+		i := (tp.next + k) % len(tp.order)
+		t := tp.order[i]
+		if !t.eligible() {
+			continue
+		}
+		// 比较 t.running/t.weight < best.running/best.weight
+		if best == nil || t.running*best.weight() < best.running*t.weight() {
+			best, bestIdx = t, i
+		}
+	}
+	if best != nil {
+		tp.next = bestIdx + 1
+	}
+	return best
+}
+
+// loop 调度循环，占用底层池的一个 worker，持续执行被选中租户的任务
+// 没有可执行的任务时退出，将 worker 归还给底层池。
+func (tp *TenantPool) loop() {
+	for {
+		tp.mu.Lock()
+		t := tp.pick()
+		if t == nil || tp.pool.IsClosed() {
+			tp.active--
+			tp.idle--
+			tp.mu.Unlock()
+			return
+		}
+		task := t.queue[0]
+		t.queue[0] = nil
+		t.queue = t.queue[1:]
+		t.running++
+		tp.idle--
+		tp.mu.Unlock()
+
+		tp.run(t, task)
+	}
+}
+
+// run 执行一个任务并归还租户的并发名额
+// 任务 panic 时当前调度循环随 worker 一起结束，由另一个 goroutine 补上调度循环，
+// panic 本身仍由底层池按 PanicHandler 处理。
+func (tp *TenantPool) run(t *tenant, task func()) {
+	finished := false
+	defer func() {
+		tp.mu.Lock()
+		t.running--
+		if finished {
+			tp.idle++
+		} else {
+			tp.active--
+		}
+		tp.mu.Unlock()
+
+		if !finished {
+			go tp.schedule()
+		}
+	}()
+
+	task()
+	finished = true
+}
+
+// Running 返回当前正在运行的 worker 数量
+func (tp *TenantPool) Running() int {
+	return tp.pool.Running()
+}
+
+// Free 返回当前空闲的 worker 数量
+func (tp *TenantPool) Free() int {
+	return tp.pool.Free()
+}
+
+// Cap 返回池的容量
+func (tp *TenantPool) Cap() int {
+	return tp.pool.Cap()
+}
+
+// Waiting 返回所有租户排队等待的任务数量
+func (tp *TenantPool) Waiting() int {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+
+	n := 0
+	for _, t := range tp.order {
+		n += len(t.queue)
+	}
+	return n
+}
+
+// IsClosed 返回池是否已关闭
+func (tp *TenantPool) IsClosed() bool {
+	return tp.pool.IsClosed()
+}
+
+// Stats 返回底层池的运行状态快照，Waiting 为所有租户排队的任务数量
+func (tp *TenantPool) Stats() Stats {
+	s := tp.pool.Stats()
+	s.Waiting = tp.Waiting()
+	return s
+}
+
+// Release 优雅关闭池，尚未开始执行的排队任务会被丢弃
+func (tp *TenantPool) Release() {
+	tp.pool.Release()
+	tp.dropQueued()
+}
+
+// ReleaseTimeout 带超时的优雅关闭，尚未开始执行的排队任务会被丢弃
+func (tp *TenantPool) ReleaseTimeout(timeout time.Duration) error {
+	err := tp.pool.ReleaseTimeout(timeout)
+	tp.dropQueued()
+	return err
+}
+
+// Reboot 重启已关闭的池
+func (tp *TenantPool) Reboot() {
+	tp.pool.Reboot()
+}
+
+// dropQueued 丢弃所有租户排队的任务
+func (tp *TenantPool) dropQueued() {
+	tp.mu.Lock()
+	for _, t := range tp.order {
+		t.queue = nil
+	}
+	tp.mu.Unlock()
+}
@@ -0,0 +1,129 @@
+package laborer
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestTenantPoolMaxConcurrency 测试租户的并发上限和其他租户不被饿死
+func TestTenantPoolMaxConcurrency(t *testing.T) {
+	tp, err := NewTenantPool(4)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer tp.Release()
+
+	tp.SetTenant("noisy", TenantConfig{MaxConcurrency: 2})
+
+	var cur, peak int32
+	var wg sync.WaitGroup
+	block := make(chan struct{})
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		if err := tp.Submit("noisy", func() {
+			n := atomic.AddInt32(&cur, 1)
+			for {
+				old := atomic.LoadInt32(&peak)
+				if n <= old || atomic.CompareAndSwapInt32(&peak, old, n) {
+					break
+				}
+			}
+			<-block
+			atomic.AddInt32(&cur, -1)
+			wg.Done()
+		}); err != nil {
+			t.Fatalf("提交任务失败: %v", err)
+		}
+	}
+
+	// noisy 租户被限制在 2 个并发，其他租户仍能立即执行
+	done := make(chan struct{})
+	if err := tp.Submit("quiet", func() { close(done) }); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("其他租户的任务被饿死")
+	}
+
+	if s := tp.TenantStats("noisy"); s.Running != 2 || s.Queued != 18 {
+		t.Errorf("租户状态不正确: %+v", s)
+	}
+
+	close(block)
+	wg.Wait()
+	if peak != 2 {
+		t.Errorf("期望并发峰值为 2，实际 %d", peak)
+	}
+}
+
+// TestTenantPoolWeights 测试剩余容量按权重分配
+func TestTenantPoolWeights(t *testing.T) {
+	tp, err := NewTenantPool(4)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer tp.Release()
+
+	tp.SetTenant("a", TenantConfig{Weight: 3})
+	tp.SetTenant("b", TenantConfig{Weight: 1})
+
+	// 先占满容量，让两个租户的任务都在队列中等待
+	block := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		tp.Submit("warmup", func() {
+			<-block
+			wg.Done()
+		})
+	}
+
+	release := make(chan struct{})
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		tp.Submit("a", func() {
+			<-release
+			wg.Done()
+		})
+		tp.Submit("b", func() {
+			<-release
+			wg.Done()
+		})
+	}
+
+	close(block)
+	deadline := time.Now().Add(time.Second)
+	for tp.TenantStats("a").Running+tp.TenantStats("b").Running < 4 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	if a, b := tp.TenantStats("a").Running, tp.TenantStats("b").Running; a != 3 || b != 1 {
+		t.Errorf("期望按 3:1 分配容量，实际 a=%d b=%d", a, b)
+	}
+
+	close(release)
+	wg.Wait()
+}
+
+// TestTenantPoolPanic 测试任务 panic 后调度继续进行
+func TestTenantPoolPanic(t *testing.T) {
+	tp, err := NewTenantPool(1, WithPanicHandler(func(interface{}) {}))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer tp.Release()
+
+	done := make(chan struct{})
+	tp.Submit("a", func() { panic("boom") })
+	tp.Submit("a", func() { close(done) })
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("panic 后排队的任务没有继续执行")
+	}
+}
@@ -0,0 +1,96 @@
+package laborer
+
+import "time"
+
+// TieredPool 由固定的专用层和临时的溢出层组成的两级池
+//
+// 专用层的 worker 常驻不回收，承载基线流量以获得稳定的延迟；
+// 专用层已满时任务溢出到溢出层，溢出层有自己的容量和更短的空闲过期时间，
+// 突发流量过去后临时扩出的 worker 很快被回收。两层都已满时回到专用层，
+// 按阻塞/非阻塞配置处理。状态查询返回两层的汇总值。
+//
+// 示例:
+//
+//	tp, _ := laborer.NewTieredPool(50, 200, 5*time.Second)
+//	defer tp.Release()
+//
+//	tp.Submit(func() {
+//	    handle(req)
+//	})
+type TieredPool struct {
+	poolShards
+}
+
+// NewTieredPool 创建一个新的两级池
+// dedicated: 专用层的容量，必须为正数
+// overflow: 溢出层的容量，-1 表示无限容量
+// overflowExpiry: 溢出层 worker 的空闲过期时间，必须为正数
+// options: 配置选项，应用到两层；专用层总是禁用过期清理，
+// 溢出层总是使用 overflowExpiry 作为过期时间
+func NewTieredPool(dedicated, overflow int, overflowExpiry time.Duration, options ...Option) (*TieredPool, error) {
+	if dedicated <= 0 {
+		return nil, ErrInvalidPoolSize
+	}
+	if overflowExpiry <= 0 {
+		return nil, ErrInvalidPoolExpiry
+	}
+
+	d, err := NewPool(dedicated, append(options, WithDisablePurge(true))...)
+	if err != nil {
+		return nil, err
+	}
+
+	o, err := NewPool(overflow, append(options, WithDisablePurge(false), WithExpiryDuration(overflowExpiry))...)
+	if err != nil {
+		d.Release()
+		return nil, err
+	}
+
+	return &TieredPool{poolShards: poolShards{d, o}}, nil
+}
+
+// Submit 提交一个任务，专用层已满时溢出到溢出层
+func (tp *TieredPool) Submit(task func()) error {
+	return tp.dispatch(taskItem{run: task})
+}
+
+// SubmitWithResult 提交一个带返回值的任务，专用层已满时溢出到溢出层
+func (tp *TieredPool) SubmitWithResult(task func() (interface{}, error)) (Future, error) {
+	f := newFuture()
+	if err := tp.dispatch(taskItem{call: task, future: f}); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// dispatch 依次尝试专用层和溢出层，都已满时在专用层上按其配置等待或拒绝
+func (tp *TieredPool) dispatch(t taskItem) error {
+	if tp.IsClosed() {
+		return ErrPoolClosed
+	}
+	if t.isNil() {
+		return ErrNilTask
+	}
+
+	d := tp.Dedicated()
+	t = d.newTask(t)
+
+	ok, err := tp.steal(0, func(p *Pool) (bool, error) {
+		return p.tryDispatch(t)
+	})
+	if err != nil || ok {
+		return err
+	}
+
+	return d.dispatch(t)
+}
+
+// Dedicated 返回专用层，用于单独查询其状态
+func (tp *TieredPool) Dedicated() *Pool {
+	return tp.poolShards[0]
+}
+
+// Overflow 返回溢出层，用于单独查询其状态
+func (tp *TieredPool) Overflow() *Pool {
+	return tp.poolShards[1]
+}
@@ -0,0 +1,49 @@
+package laborer
+
+import (
+	"testing"
+	"time"
+)
+
+// TestTieredPool 测试专用层已满时溢出到溢出层
+func TestTieredPool(t *testing.T) {
+	if _, err := NewTieredPool(0, 1, time.Second); err != ErrInvalidPoolSize {
+		t.Errorf("期望返回 ErrInvalidPoolSize，实际返回: %v", err)
+	}
+	if _, err := NewTieredPool(1, 1, 0); err != ErrInvalidPoolExpiry {
+		t.Errorf("期望返回 ErrInvalidPoolExpiry，实际返回: %v", err)
+	}
+
+	tp, err := NewTieredPool(2, 2, 50*time.Millisecond, WithNonblocking(true), WithCleanInterval(10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer tp.Release()
+
+	block := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		if err := tp.Submit(func() { <-block }); err != nil {
+			t.Fatalf("提交任务失败: %v", err)
+		}
+	}
+	if d, o := tp.Dedicated().Running(), tp.Overflow().Running(); d != 2 || o != 2 {
+		t.Errorf("期望两层各运行 2 个 worker，实际专用层 %d 溢出层 %d", d, o)
+	}
+	if err := tp.Submit(func() {}); err != ErrPoolOverload {
+		t.Errorf("期望返回 ErrPoolOverload，实际返回: %v", err)
+	}
+	if s := tp.Stats(); s.Cap != 4 || s.Running != 4 {
+		t.Errorf("汇总状态不正确: %+v", s)
+	}
+
+	// 突发结束后溢出层的 worker 过期回收，专用层的 worker 常驻
+	close(block)
+	deadline := time.Now().Add(time.Second)
+	for (tp.Dedicated().Free() != 2 || tp.Overflow().Free() != 0) && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if d, o := tp.Dedicated().Free(), tp.Overflow().Free(); d != 2 || o != 0 {
+		t.Errorf("期望只保留专用层的 2 个空闲 worker，实际专用层 %d 溢出层 %d", d, o)
+	}
+}
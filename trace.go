@@ -0,0 +1,56 @@
+package laborer
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// tracer 按任务记录提交、派发和完成的追踪日志，可以在运行时开关
+// 关闭时每次提交只多一次 atomic 读取。
+type tracer struct {
+	// enabled 是否开启追踪
+	enabled atomic.Bool
+
+	// seq 最后分配的任务 ID
+	seq atomic.Uint64
+}
+
+// submitted 开启追踪时为新提交的任务分配 ID 并记录 task_submitted
+// 未开启时返回 0，表示不追踪这个任务。
+func (tr *tracer) submitted(opts *Options) uint64 {
+	if !tr.enabled.Load() {
+		return 0
+	}
+	id := tr.seq.Add(1)
+	opts.logEvent(LevelDebug, "task_submitted", Field{"task_id", id})
+	return id
+}
+
+// traceRejected 记录被追踪的任务没有被执行，err 为提交返回的错误，为 nil 时不做任何事
+func traceRejected(opts *Options, id uint64, err error) {
+	if id != 0 && err != nil {
+		opts.logEvent(LevelDebug, "task_rejected", Field{"task_id", id}, Field{"error", err})
+	}
+}
+
+// traceQueued 记录被追踪的任务放入了任务队列
+// 队列只保存参数，从队列取出后不再追踪这个任务。
+func traceQueued(opts *Options, id uint64) {
+	if id != 0 {
+		opts.logEvent(LevelDebug, "task_queued", Field{"task_id", id})
+	}
+}
+
+// traceDispatched 记录被追踪的任务交给 worker 开始执行，返回开始时间
+func traceDispatched(opts *Options, id uint64, workerID int, submitted time.Time) time.Time {
+	now := time.Now()
+	opts.logEvent(LevelDebug, "task_dispatched", Field{"task_id", id}, Field{"worker_id", workerID},
+		Field{"wait", now.Sub(submitted)})
+	return now
+}
+
+// traceCompleted 记录被追踪的任务执行结束，panicked 表示任务是否发生了 panic
+func traceCompleted(opts *Options, id uint64, workerID int, start time.Time, panicked bool) {
+	opts.logEvent(LevelDebug, "task_completed", Field{"task_id", id}, Field{"worker_id", workerID},
+		Field{"duration", time.Since(start)}, Field{"panicked", panicked})
+}
@@ -0,0 +1,115 @@
+package laborer
+
+import (
+	"testing"
+	"time"
+)
+
+// traceEvents 返回按任务 ID 分组的追踪事件名称
+func traceEvents(r *eventRecorder) map[interface{}][]string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make(map[interface{}][]string)
+	for _, e := range r.events {
+		if len(e.Fields) > 0 && e.Fields[0].Key == "task_id" {
+			out[e.Fields[0].Value] = append(out[e.Fields[0].Value], e.Event)
+		}
+	}
+	return out
+}
+
+// lastEvent 返回最后记录的事件
+func lastEvent(r *eventRecorder) LogEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.events[len(r.events)-1]
+}
+
+// TestPoolTrace 测试运行时开启追踪后记录每个任务的提交、派发和完成
+func TestPoolTrace(t *testing.T) {
+	events := &eventRecorder{}
+	pool, err := NewPool(1, WithEventLogger(events), WithNonblocking(true), WithPanicHandler(func(interface{}) {}))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	// 未开启时不记录
+	if err := pool.Submit(func() {}); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	pool.Wait()
+	if len(traceEvents(events)) != 0 {
+		t.Fatal("未开启追踪时不应该记录任务事件")
+	}
+
+	pool.SetTrace(true)
+	if !pool.Tracing() {
+		t.Fatal("SetTrace(true) 后 Tracing 应该返回 true")
+	}
+
+	block := make(chan struct{})
+	if err := pool.Submit(func() { <-block }); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	// 池已满，第二个任务被拒绝
+	if err := pool.Submit(func() {}); err != ErrPoolOverload {
+		t.Fatalf("期望返回 ErrPoolOverload，实际返回: %v", err)
+	}
+	close(block)
+	pool.Wait()
+
+	waitFor(t, func() bool {
+		tasks := traceEvents(events)
+		return len(tasks[uint64(1)]) == 3
+	})
+	tasks := traceEvents(events)
+	if got := tasks[uint64(1)]; got[0] != "task_submitted" || got[1] != "task_dispatched" || got[2] != "task_completed" {
+		t.Errorf("任务 1 的事件顺序不正确: %v", got)
+	}
+	if got := tasks[uint64(2)]; len(got) != 2 || got[1] != "task_rejected" {
+		t.Errorf("任务 2 应该被记录为拒绝: %v", got)
+	}
+
+	// panic 的任务标记为 panicked
+	if err := pool.Submit(func() { panic("boom") }); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	waitFor(t, func() bool { return len(traceEvents(events)[uint64(3)]) == 3 })
+	if e := lastEvent(events); e.Event != "task_completed" || e.Fields[3] != (Field{"panicked", true}) {
+		t.Errorf("panic 的任务应该标记为 panicked，实际 %s", e)
+	}
+
+	pool.SetTrace(false)
+	events.mu.Lock()
+	n := len(events.events)
+	events.mu.Unlock()
+	_ = pool.Submit(func() {})
+	pool.Wait()
+	if len(traceEvents(events)) != 3 || len(events.events) != n {
+		t.Error("关闭追踪后不应该再记录任务事件")
+	}
+}
+
+// TestPoolWithFuncTrace 测试函数池的追踪日志
+func TestPoolWithFuncTrace(t *testing.T) {
+	events := &eventRecorder{}
+	pool, err := NewPoolWithFunc(2, func(interface{}) {}, WithEventLogger(events), WithTrace(true))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	if err := pool.Invoke(1); err != nil {
+		t.Fatalf("提交调用失败: %v", err)
+	}
+	waitFor(t, func() bool { return len(traceEvents(events)[uint64(1)]) == 3 })
+
+	e, _ := events.find("task_dispatched")
+	if e.Fields[1].Key != "worker_id" || e.Fields[2].Key != "wait" {
+		t.Errorf("task_dispatched 事件的字段不正确: %+v", e)
+	}
+	if _, ok := e.Fields[2].Value.(time.Duration); !ok {
+		t.Errorf("wait 应该是 time.Duration，实际 %T", e.Fields[2].Value)
+	}
+}
@@ -0,0 +1,194 @@
+package laborer
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// waiter 一个阻塞等待 worker 的提交者
+type waiter struct {
+	// ready 被唤醒时写入，缓冲为 1，唤醒不会因为等待者尚未开始等待而丢失
+	ready chan struct{}
+
+	// woken 是否已被唤醒过，被唤醒后仍未取到 worker 时重新排在队首
+	woken bool
+}
+
+// newWaiter 创建一个等待者
+func newWaiter() *waiter {
+	return &waiter{ready: make(chan struct{}, 1)}
+}
+
+// waitQueue 阻塞等待 worker 的提交者队列
+//
+// 每个等待者在自己的 channel 上等待，唤醒按加入队列的顺序（FIFO）进行。
+// 与 sync.Cond 不同，等待可以被超时或 ctx 取消打断，放弃等待的提交者
+// 会从队列中移除，已经收到的唤醒转交给下一个等待者。
+// 所有方法都必须在持有池的锁时调用。
+type waitQueue struct {
+	waiters []*waiter
+}
+
+// len 返回等待者数量
+func (q *waitQueue) len() int {
+	return len(q.waiters)
+}
+
+// push 将等待者加入队列，已被唤醒过的等待者加入队首
+func (q *waitQueue) push(w *waiter) {
+	if !w.woken {
+		q.waiters = append(q.waiters, w)
+		return
+	}
+	q.waiters = append(q.waiters, nil)
+	copy(q.waiters[1:], q.waiters)
+	q.waiters[0] = w
+}
+
+// remove 将等待者从队列中移除，等待者已被唤醒（不在队列中）时返回 false
+func (q *waitQueue) remove(w *waiter) bool {
+	for i, x := range q.waiters {
+		if x == w {
+			copy(q.waiters[i:], q.waiters[i+1:])
+			q.waiters[len(q.waiters)-1] = nil
+			q.waiters = q.waiters[:len(q.waiters)-1]
+			return true
+		}
+	}
+	return false
+}
+
+// signal 唤醒最早开始等待的一个等待者，队列为空时返回 false
+func (q *waitQueue) signal() bool {
+	if len(q.waiters) == 0 {
+		return false
+	}
+
+	w := q.waiters[0]
+	q.waiters[0] = nil
+	q.waiters = q.waiters[1:]
+	w.ready <- struct{}{}
+	return true
+}
+
+// broadcast 唤醒所有等待者
+func (q *waitQueue) broadcast() {
+	for q.signal() {
+	}
+}
+
+// wait 将 w 加入队列，在 lock 外等待被唤醒、ctx 被取消或到达 deadline
+// 调用方必须持有 lock，返回时重新持有 lock。
+// 被唤醒时返回 nil，调用方应当重新尝试获取 worker；
+// ctx 被取消时返回 ctx.Err()，到达 deadline 时返回 ErrTimeout，deadline 为零值表示不限时。
+func (q *waitQueue) wait(ctx context.Context, w *waiter, lock sync.Locker, deadline time.Time) error {
+	q.push(w)
+	lock.Unlock()
+
+	var timeout <-chan time.Time
+	if !deadline.IsZero() {
+		timer := time.NewTimer(time.Until(deadline))
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	var err error
+	select {
+	case <-w.ready:
+		lock.Lock()
+		w.woken = true
+		return nil
+	case <-ctx.Done():
+		err = ctx.Err()
+	case <-timeout:
+		err = ErrTimeout
+	}
+
+	lock.Lock()
+	if !q.remove(w) {
+		// 放弃等待前已被唤醒，将唤醒转交给下一个等待者
+		<-w.ready
+		q.signal()
+	}
+	return err
+}
+
+// zeroSignal 计数降为 0 的通知
+//
+// 用于 Wait、Drain、ReleaseTimeout 和 WaitRunning 等待池中的任务或 worker 到达预期的数量，而不必轮询计数。
+// 等待者先取得 channel 再检查计数，计数降为 0 的一方调用 notify 关闭 channel，
+// 两者以相反的顺序访问计数和等待者数量，通知不会丢失。没有等待者时 notify 不获取锁。
+type zeroSignal struct {
+	mu sync.Mutex
+	ch chan struct{}
+
+	// waiters 正在等待的 goroutine 数量
+	waiters atomic.Int32
+}
+
+// channel 返回下一次 notify 时关闭的 channel
+func (s *zeroSignal) channel() <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ch == nil {
+		s.ch = make(chan struct{})
+	}
+	return s.ch
+}
+
+// notify 唤醒所有等待者，使它们重新检查计数
+// 在计数降为 0 之后调用。
+func (s *zeroSignal) notify() {
+	if s.waiters.Load() == 0 {
+		return
+	}
+	s.mu.Lock()
+	if s.ch != nil {
+		close(s.ch)
+		s.ch = nil
+	}
+	s.mu.Unlock()
+}
+
+// await 等待 count 降为 0
+// ctx 被取消时返回 ctx.Err()；deadline 不为零值时最多等待到 deadline，
+// 超时返回 ErrTimeout。出错时同时返回 count 的当前值，此时 count 已降为 0 则返回 nil。
+func (s *zeroSignal) await(ctx context.Context, deadline time.Time, count func() int) (int, error) {
+	if n := count(); n <= 0 {
+		return 0, nil
+	}
+
+	s.waiters.Add(1)
+	defer s.waiters.Add(-1)
+
+	var timeout <-chan time.Time
+	if !deadline.IsZero() {
+		timer := time.NewTimer(time.Until(deadline))
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	for {
+		ch := s.channel()
+		n := count()
+		if n <= 0 {
+			return 0, nil
+		}
+
+		var err error
+		select {
+		case <-ch:
+			continue
+		case <-ctx.Done():
+			err = ctx.Err()
+		case <-timeout:
+			err = ErrTimeout
+		}
+		if n := count(); n > 0 {
+			return n, err
+		}
+		return 0, nil
+	}
+}
@@ -0,0 +1,142 @@
+package laborer
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestWaitQueueFIFO 测试阻塞的提交者按开始等待的顺序取得 worker
+func TestWaitQueueFIFO(t *testing.T) {
+	pool, err := NewPool(1)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	block := make(chan struct{})
+	if err := pool.Submit(func() { <-block }); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+
+	var mu sync.Mutex
+	var order []int
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := pool.Submit(func() {
+				mu.Lock()
+				order = append(order, i)
+				mu.Unlock()
+			}); err != nil {
+				t.Errorf("提交任务失败: %v", err)
+			}
+		}()
+		// 确认上一个提交者已开始等待后再启动下一个
+		waitFor(t, func() bool { return pool.Waiting() == i+1 })
+	}
+
+	close(block)
+	wg.Wait()
+	waitFor(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(order) == 5
+	})
+	for i, n := range order {
+		if n != i {
+			t.Fatalf("期望按等待顺序执行，实际顺序 %v", order)
+		}
+	}
+}
+
+// TestSubmitContext 测试阻塞等待 worker 时通过 ctx 取消提交
+func TestSubmitContext(t *testing.T) {
+	pool, err := NewPool(1)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+	fp, err := NewPoolWithFunc(1, func(arg interface{}) { <-arg.(chan struct{}) })
+	if err != nil {
+		t.Fatalf("创建函数池失败: %v", err)
+	}
+	defer fp.Release()
+
+	block := make(chan struct{})
+	if err := pool.SubmitContext(context.Background(), func() { <-block }); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	if err := fp.InvokeContext(context.Background(), block); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := pool.SubmitContext(ctx, func() {}); err != context.DeadlineExceeded {
+		t.Errorf("期望返回 context.DeadlineExceeded，实际返回: %v", err)
+	}
+	if err := fp.InvokeContext(ctx, block); err != context.DeadlineExceeded {
+		t.Errorf("期望返回 context.DeadlineExceeded，实际返回: %v", err)
+	}
+	if pool.Waiting() != 0 || fp.Waiting() != 0 {
+		t.Errorf("期望取消后没有等待者，实际 %d 和 %d", pool.Waiting(), fp.Waiting())
+	}
+
+	// 放弃等待的提交者不影响之后的提交
+	close(block)
+	done := make(chan struct{})
+	if err := pool.SubmitContext(context.Background(), func() { close(done) }); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("任务没有执行")
+	}
+}
+
+// TestZeroSignal 测试计数降为 0 时唤醒等待者，以及取消和超时
+func TestZeroSignal(t *testing.T) {
+	var s zeroSignal
+	var mu sync.Mutex
+	count := 1
+	get := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return count
+	}
+
+	if n, err := s.await(context.Background(), time.Now().Add(10*time.Millisecond), get); err != ErrTimeout || n != 1 {
+		t.Errorf("期望超时返回 1 和 ErrTimeout，实际 %d, %v", n, err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := s.await(ctx, time.Time{}, get); err != context.Canceled {
+		t.Errorf("期望返回 context.Canceled，实际 %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := s.await(context.Background(), time.Time{}, get)
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	mu.Lock()
+	count = 0
+	mu.Unlock()
+	s.notify()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("期望计数降为 0 后返回 nil，实际 %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("计数降为 0 后等待者未被唤醒")
+	}
+}
@@ -0,0 +1,51 @@
+package laborer
+
+import (
+	"io/fs"
+	"path/filepath"
+)
+
+// WalkFiles 遍历以 root 为根的文件树，并在池中以有界并发对每个文件调用 fn。
+//
+// 遍历按 filepath.WalkDir 的顺序进行，fn 对每个非目录的条目（包括符号链接）调用一次。
+// 池满时（阻塞模式下）遍历会暂停，直到有 worker 空闲，因此并发度由池的容量决定。
+// 读取目录失败时记录该错误并跳过该目录，其余部分继续遍历；
+// fn 返回的错误（panic 时为 *PanicError）同样被记录，不影响其他文件。
+// 提交失败（例如 ErrPoolClosed、非阻塞池的 ErrPoolOverload）时停止遍历。
+// 所有已提交的文件处理完成后返回。
+//
+// 参数:
+//   - pool: 执行 fn 的池
+//   - root: 文件树的根
+//   - fn: 处理单个文件的函数，参数为 root 与文件相对路径的拼接
+//
+// 返回:
+//   - error: 没有错误时为 nil，否则为遍历、提交和 fn 的错误经 errors.Join 合并后的结果
+//
+// 示例:
+//
+//	err := laborer.WalkFiles(pool, "./images", func(path string) error {
+//	    return resize(path)
+//	})
+func WalkFiles(pool *Pool, root string, fn func(path string) error) error {
+	group := NewTaskGroup(pool)
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			group.record(err)
+			if d != nil && d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		// 提交错误已由 Submit 记录
+		if err := group.Submit(func() error { return fn(path) }); err != nil {
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return group.Wait()
+}
@@ -0,0 +1,87 @@
+package laborer
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestWalkFiles 测试文件树中的每个文件都在池中处理，错误被合并返回
+func TestWalkFiles(t *testing.T) {
+	root := t.TempDir()
+	var want []string
+	for _, name := range []string{"a.txt", "b.bad", "sub/c.txt", "sub/deep/d.bad", "sub/deep/e.txt"} {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("创建目录失败: %v", err)
+		}
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatalf("创建文件失败: %v", err)
+		}
+		want = append(want, path)
+	}
+
+	pool, err := NewPool(2)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	errBad := errors.New("bad file")
+	var mu sync.Mutex
+	var got []string
+	err = WalkFiles(pool, root, func(path string) error {
+		mu.Lock()
+		got = append(got, path)
+		mu.Unlock()
+		if strings.HasSuffix(path, ".bad") {
+			return errBad
+		}
+		return nil
+	})
+
+	sort.Strings(got)
+	sort.Strings(want)
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("期望处理 %v，实际 %v", want, got)
+	}
+	if !errors.Is(err, errBad) {
+		t.Fatalf("期望返回合并的处理错误，实际 %v", err)
+	}
+	if n := len(err.(interface{ Unwrap() []error }).Unwrap()); n != 2 {
+		t.Errorf("期望合并 2 个错误，实际 %d", n)
+	}
+}
+
+// TestWalkFilesErrors 测试遍历失败和提交失败时返回的错误
+func TestWalkFilesErrors(t *testing.T) {
+	pool, err := NewPool(2)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+
+	noop := func(string) error { return nil }
+	if err := WalkFiles(pool, filepath.Join(t.TempDir(), "missing"), noop); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("根不存在时期望返回 fs.ErrNotExist，实际 %v", err)
+	}
+
+	root := t.TempDir()
+	for _, name := range []string{"a", "b"} {
+		if err := os.WriteFile(filepath.Join(root, name), nil, 0o644); err != nil {
+			t.Fatalf("创建文件失败: %v", err)
+		}
+	}
+	pool.Release()
+	err = WalkFiles(pool, root, noop)
+	if !errors.Is(err, ErrPoolClosed) {
+		t.Fatalf("池已关闭时期望返回 ErrPoolClosed，实际 %v", err)
+	}
+	if n := len(err.(interface{ Unwrap() []error }).Unwrap()); n != 1 {
+		t.Errorf("提交失败后应该停止遍历，期望 1 个错误，实际 %d", n)
+	}
+}
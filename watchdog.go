@@ -0,0 +1,237 @@
+package laborer
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// WorkerStack 表示一个正在执行任务的 worker 的栈信息。
+type WorkerStack struct {
+	// WorkerID worker 在池内的编号
+	WorkerID int `json:"worker_id"`
+
+	// Goroutine worker 所在 goroutine 的 ID
+	Goroutine int64 `json:"goroutine"`
+
+	// Task 当前任务的名称，任务没有名称时为空
+	Task string `json:"task,omitempty"`
+
+	// BusyFor 当前任务已经执行的时长
+	BusyFor time.Duration `json:"busy_for"`
+
+	// Stack worker goroutine 的调用栈
+	Stack string `json:"stack"`
+}
+
+// workerState 保存 worker 的可观测状态
+// 由 goWorker 和 goWorkerWithFunc 嵌入，供 DumpStacks 和看门狗读取
+type workerState struct {
+	// id 在池内唯一的 worker 编号，每次启动时分配
+	id int
+
+	// gid worker 所在 goroutine 的 ID，worker 启动时记录
+	gid int64
+
+	// busySince 当前任务开始执行的时间（UnixNano），空闲时为 0
+	busySince atomic.Int64
+
+	// reported 看门狗已经上报过的 busySince，避免同一个任务被重复上报
+	reported atomic.Int64
+
+	// taskName 当前任务的名称，空闲或任务没有名称时为 nil
+	taskName atomic.Pointer[string]
+}
+
+// workerID 返回 worker 在池内的编号
+func (s *workerState) workerID() int {
+	return s.id
+}
+
+// setTaskName 记录 worker 开始执行一个有名称的任务
+// 开启 pprof 标签时同时为 worker goroutine 加上任务名称标签，只能在 worker goroutine 中调用。
+func (s *workerState) setTaskName(opts *Options, name string) {
+	s.taskName.Store(&name)
+	setTaskLabels(opts, name)
+}
+
+// clearTaskName 记录有名称的任务执行结束，并恢复 worker goroutine 的 pprof 标签
+func (s *workerState) clearTaskName(opts *Options) {
+	s.taskName.Store(nil)
+	setWorkerLabels(opts)
+}
+
+// currentTaskName 返回当前任务的名称，空闲或任务没有名称时返回空字符串
+func (s *workerState) currentTaskName() string {
+	if name := s.taskName.Load(); name != nil {
+		return *name
+	}
+	return ""
+}
+
+// trackWorkers 检查是否需要记录 worker 的 goroutine 和忙碌状态
+// 只有 DumpStacks、看门狗或泄漏检查需要，避免默认配置下每个 worker 解析 goroutine ID、
+// 每个任务读取时间的开销
+func trackWorkers(opts *Options) bool {
+	return opts.StackDumps || opts.WatchdogLimit > 0 || opts.LeakCheckGrace > 0
+}
+
+// workerSet 保存池中所有存活的 worker（包括空闲和忙碌的）
+type workerSet struct {
+	m sync.Map
+}
+
+// add 记录一个存活的 worker
+func (s *workerSet) add(w *workerState) {
+	s.m.Store(w, struct{}{})
+}
+
+// remove 移除一个已退出的 worker
+func (s *workerSet) remove(w *workerState) {
+	s.m.Delete(w)
+}
+
+// busy 返回正在执行任务且已执行超过 min 的 worker
+func (s *workerSet) busy(now int64, min time.Duration) []*workerState {
+	var out []*workerState
+	s.m.Range(func(key, _ interface{}) bool {
+		w := key.(*workerState)
+		if since := w.busySince.Load(); since != 0 && time.Duration(now-since) >= min {
+			out = append(out, w)
+		}
+		return true
+	})
+	return out
+}
+
+// goroutineID 解析当前 goroutine 的 ID
+// 栈的第一行格式为 "goroutine 123 [running]:"
+func goroutineID() int64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i > 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseInt(string(b), 10, 64)
+	return id
+}
+
+// allStacks 获取所有 goroutine 的栈，按 goroutine ID 索引
+func allStacks() map[int64]string {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, len(buf)*2)
+	}
+
+	stacks := make(map[int64]string)
+	for _, g := range bytes.Split(buf, []byte("\n\n")) {
+		header := bytes.TrimPrefix(g, []byte("goroutine "))
+		i := bytes.IndexByte(header, ' ')
+		if i <= 0 {
+			continue
+		}
+		if id, err := strconv.ParseInt(string(header[:i]), 10, 64); err == nil {
+			stacks[id] = string(g)
+		}
+	}
+	return stacks
+}
+
+// dumpStacks 获取指定 worker 的栈信息
+// 已经结束任务的 worker 会被跳过
+func dumpStacks(workers []*workerState, now int64) []WorkerStack {
+	if len(workers) == 0 {
+		return nil
+	}
+
+	stacks := allStacks()
+	out := make([]WorkerStack, 0, len(workers))
+	for _, w := range workers {
+		since := w.busySince.Load()
+		if since == 0 {
+			continue
+		}
+		out = append(out, WorkerStack{
+			WorkerID:  w.id,
+			Goroutine: w.gid,
+			Task:      w.currentTaskName(),
+			BusyFor:   time.Duration(now - since),
+			Stack:     stacks[w.gid],
+		})
+	}
+	return out
+}
+
+// watchdog 定期检查执行时间超过 limit 的 worker
+// 每个任务最多上报一次，上报通过 onStuck 回调，未设置回调时写入日志
+type watchdog struct {
+	stop chan struct{}
+	done chan struct{}
+}
+
+// startWatchdog 启动看门狗 goroutine，未配置 WatchdogLimit 时返回 nil
+func startWatchdog(opts *Options, workers *workerSet) *watchdog {
+	if opts.WatchdogLimit <= 0 {
+		return nil
+	}
+
+	wd := &watchdog{
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	go func() {
+		defer close(wd.done)
+
+		ticker := time.NewTicker(opts.WatchdogLimit / 2)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				now := time.Now().UnixNano()
+				var stuck []*workerState
+				for _, w := range workers.busy(now, opts.WatchdogLimit) {
+					since := w.busySince.Load()
+					if w.reported.Swap(since) != since {
+						stuck = append(stuck, w)
+					}
+				}
+
+				for _, s := range dumpStacks(stuck, now) {
+					if opts.OnStuckWorker != nil {
+						opts.OnStuckWorker(s)
+					} else {
+						fields := []Field{{"worker_id", s.WorkerID}, {"busy_for", s.BusyFor}, {"stack", s.Stack}}
+						if s.Task != "" {
+							fields = append(fields, Field{"task", s.Task})
+						}
+						opts.logEvent(LevelWarn, "worker_stuck", fields...)
+					}
+				}
+			case <-wd.stop:
+				return
+			}
+		}
+	}()
+
+	return wd
+}
+
+// close 停止看门狗并等待其退出，可以安全地在 nil 上调用
+func (wd *watchdog) close() {
+	if wd == nil {
+		return
+	}
+	close(wd.stop)
+	<-wd.done
+}
@@ -0,0 +1,85 @@
+package laborer
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// stuckTask 模拟一个挂起的任务
+func stuckTask(release chan struct{}) {
+	<-release
+}
+
+// TestDumpStacks 测试获取忙碌 worker 的栈
+func TestDumpStacks(t *testing.T) {
+	pool, err := NewPool(2, WithStackDumps(true))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	release := make(chan struct{})
+	defer close(release)
+	if err := pool.Submit(func() { stuckTask(release) }); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	// 未启用时不记录 worker 的状态
+	plain, err := NewPool(2)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer plain.Release()
+	if err := plain.Submit(func() { stuckTask(release) }); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	if stacks := plain.DumpStacks(); stacks != nil {
+		t.Errorf("未启用 WithStackDumps 时期望返回 nil，实际 %+v", stacks)
+	}
+
+	stacks := pool.DumpStacks()
+	if len(stacks) != 1 {
+		t.Fatalf("期望 1 个忙碌 worker，实际 %d", len(stacks))
+	}
+	if !strings.Contains(stacks[0].Stack, "stuckTask") {
+		t.Errorf("栈中应该包含挂起的任务函数:\n%s", stacks[0].Stack)
+	}
+	if stacks[0].WorkerID <= 0 || stacks[0].BusyFor <= 0 {
+		t.Errorf("栈信息不正确: %+v", stacks[0])
+	}
+}
+
+// TestWatchdog 测试卡住 worker 的看门狗
+func TestWatchdog(t *testing.T) {
+	stuck := make(chan WorkerStack, 4)
+	pool, err := NewPoolWithFunc(1, func(arg interface{}) {
+		stuckTask(arg.(chan struct{}))
+	}, WithWatchdog(20*time.Millisecond, func(s WorkerStack) { stuck <- s }))
+	if err != nil {
+		t.Fatalf("创建函数池失败: %v", err)
+	}
+	defer pool.Release()
+
+	release := make(chan struct{})
+	if err := pool.Invoke(release); err != nil {
+		t.Fatalf("Invoke失败: %v", err)
+	}
+
+	select {
+	case s := <-stuck:
+		if s.BusyFor < 20*time.Millisecond {
+			t.Errorf("BusyFor 应该超过上限，实际 %v", s.BusyFor)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("看门狗未上报卡住的 worker")
+	}
+
+	// 同一个任务不应重复上报
+	time.Sleep(60 * time.Millisecond)
+	close(release)
+	if n := len(stuck); n != 0 {
+		t.Errorf("同一个任务被重复上报了 %d 次", n)
+	}
+}
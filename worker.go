@@ -1,6 +1,8 @@
 package laborer
 
 import (
+	"fmt"
+	"runtime/debug"
 	"sync/atomic"
 	"time"
 )
@@ -11,13 +13,41 @@ type goWorker struct {
 	pool *Pool
 
 	// 任务 channel
-	task chan func()
+	task chan taskItem
 
-	// 最后使用时间（用于超时回收）
-	lastUsed time.Time
+	// info 当前任务的元数据，仅由 worker goroutine 读写
+	info TaskInfo
+
+	// infoEnded 当前任务已经调用过 endTask，OnTaskComplete 发生 panic 时 recover 不会再次调用
+	infoEnded bool
+
+	// state 由 WorkerInit 创建的 per-worker 资源
+	state interface{}
+
+	// future 当前正在执行的带返回值任务的 future，用于在 panic 时完成它
+	future *future
+
+	// workerState worker 的编号和忙碌状态
+	workerState
+
+	// 创建时间
+	created time.Time
+
+	// 最后使用时间（UnixNano，用于超时回收）
+	// 归还时在锁外写入，清理 goroutine 和 Workers 在锁内读取，使用 atomic 避免数据竞争
+	lastUsed atomic.Int64
 
 	// 回收标志
 	recycled int32
+
+	// 过期标志，由清理 goroutine 在回收空闲超时的 worker 时设置
+	expired int32
+
+	// spill 是否为池饱和时创建的溢出 worker，只执行一个任务且不计入运行计数
+	spill bool
+
+	// retired 缩容时由 putWorker 扣减了运行计数，退出时不再扣减
+	retired bool
 }
 
 // run 启动 worker 的主循环，处理任务执行
@@ -26,30 +56,88 @@ func (w *goWorker) run() {
 	go func() {
 		defer func() {
 			// 减少运行中的 worker 计数
-			atomic.AddInt32(&w.pool.running, -1)
+			if w.spill {
+				if atomic.AddInt32(&w.pool.spilling, -1) < 0 {
+					w.pool.options.reportError(fmt.Errorf("%w: spilling worker count is negative", ErrInvariant))
+				}
+			} else if !w.retired && atomic.AddInt32(&w.pool.running, -1) < 0 {
+				w.pool.options.reportError(fmt.Errorf("%w: running worker count is negative", ErrInvariant))
+			}
+			if w.pool.trackWorkers {
+				w.busySince.Store(0)
+				w.pool.live.remove(&w.workerState)
+			}
 
 			// 处理 panic
 			if p := recover(); p != nil {
-				if w.pool.options.PanicHandler != nil {
-					w.pool.options.PanicHandler(p)
-				} else if w.pool.options.Logger != nil {
-					w.pool.options.Logger.Printf("worker exits from panic: %v", p)
+				stack := debug.Stack()
+
+				// 将 panic 传递给 future，避免 Get 永久阻塞
+				if w.future != nil {
+					w.future.setResult(nil, &PanicError{Pool: w.pool.options.Name, WorkerID: w.id, Value: p, Stack: stack})
+					w.future = nil
 				}
+
+				info := TaskInfo{Pool: w.pool.options.Name, WorkerID: w.id, Name: w.currentTaskName()}
+				if w.pool.trackTasks {
+					w.info.Panic = p
+					if !w.infoEnded {
+						w.infoEnded = true
+						endTask(w.pool.options, &w.pool.metrics, &w.info)
+					}
+					info = w.info
+				}
+				reportPanic(w.pool.options, &w.pool.metrics, &w.pool.panics, p, stack, info)
+				w.pool.events.emit(w.pool.options, Event{Type: TaskPanicked, WorkerID: w.id, Value: p})
+			}
+			w.taskName.Store(nil)
+
+			// 释放 per-worker 资源
+			w.teardown()
+
+			// 调用 worker 生命周期钩子并发出事件
+			if atomic.LoadInt32(&w.expired) == 1 {
+				if w.pool.options.OnWorkerExpire != nil {
+					w.pool.options.OnWorkerExpire(w.id)
+				}
+				w.pool.events.emit(w.pool.options, Event{Type: WorkerExpired, WorkerID: w.id})
+			}
+			if w.pool.options.OnWorkerExit != nil {
+				w.pool.options.OnWorkerExit(w.id)
 			}
+			w.pool.events.emit(w.pool.options, Event{Type: WorkerExited, WorkerID: w.id})
 
 			// 通知池 worker 已退出
-			w.pool.cond.Signal()
+			w.pool.signal()
+			w.pool.quiesce()
 		}()
 
+		if w.pool.trackWorkers {
+			w.gid = goroutineID()
+			w.pool.live.add(&w.workerState)
+		}
+		w.pool.quiesce()
+		setWorkerLabels(w.pool.options)
+
+		if w.pool.options.OnWorkerCreate != nil {
+			w.pool.options.OnWorkerCreate(w.id)
+		}
+		w.pool.events.emit(w.pool.options, Event{Type: WorkerCreated, WorkerID: w.id})
+
 		// 主循环：持续接收和执行任务
-		for task := range w.task {
-			if task == nil {
-				// nil 任务表示 worker 应该退出
+		for t := range w.task {
+			if t.isStop() {
+				// 空任务表示 worker 应该退出
 				return
 			}
 
 			// 执行任务
-			task()
+			w.execute(&t)
+
+			// 溢出 worker 只执行一个任务
+			if w.spill {
+				return
+			}
 
 			// 任务完成后，将 worker 放回池中以供复用
 			if ok := w.pool.putWorker(w); !ok {
@@ -60,18 +148,156 @@ func (w *goWorker) run() {
 	}()
 }
 
+// reportPanic 记录任务 panic 并调用 panic 处理函数
+// 优先调用 PanicHandlerV2，其次是 PanicHandler，都未设置时写入日志
+func reportPanic(opts *Options, m *poolMetrics, panics *panicLog, p interface{}, stack []byte, info TaskInfo) {
+	info.Panic = p
+
+	m.panicked.Add(1)
+	panics.record(info.WorkerID, info.Name, p, stack)
+
+	switch {
+	case opts.PanicHandlerV2 != nil:
+		opts.PanicHandlerV2(p, stack, info)
+	case opts.PanicHandler != nil:
+		opts.PanicHandler(p)
+	default:
+		// 完整的堆栈很长，单独以 Debug 级别记录，按级别过滤的日志管道默认只保留一行错误
+		fields := []Field{{"worker_id", info.WorkerID}, {"panic", p}}
+		if info.Name != "" {
+			fields = append(fields, Field{"task", info.Name})
+		}
+		opts.logEvent(LevelError, "worker_panic", fields...)
+		opts.logEvent(LevelDebug, "worker_panic_stack", Field{"worker_id", info.WorkerID}, Field{"stack", stack})
+	}
+}
+
+// execute 执行单个任务，记录计数器和任务元数据
+// 设置了 Budget 时，任务结束（包括 panic）后归还提交时占用的预算名额。
+// 任务发生 panic 时由 run 中的 recover 负责收尾
+func (w *goWorker) execute(t *taskItem) {
+	p := w.pool
+	defer p.settle(1)
+	if b := p.options.Budget; b != nil {
+		defer b.release(1)
+	}
+	if p.trackWorkers {
+		w.busySince.Store(time.Now().UnixNano())
+	}
+	panicked := true
+	if t.name != "" {
+		w.setTaskName(p.options, t.name)
+		c := p.named.get(t.name)
+		c.begin()
+		defer func() { c.end(panicked) }()
+	}
+	if p.trackTasks {
+		w.info = beginTask(p.options, &p.metrics, w.id, t.name, t.submitted)
+		w.infoEnded = false
+	}
+	if t.id != 0 {
+		start := traceDispatched(p.options, t.id, w.id, t.submitted)
+		defer func() { traceCompleted(p.options, t.id, w.id, start, panicked) }()
+	}
+
+	if t.call != nil {
+		w.future = t.future
+		result, err := t.call()
+		w.future = nil
+		if err != nil {
+			p.metrics.failed.Add(1)
+		}
+		w.info.Err = err
+		t.future.setResult(result, err)
+	} else if t.runState != nil {
+		t.runState(w.state)
+	} else {
+		t.run()
+	}
+
+	if p.trackWorkers {
+		w.busySince.Store(0)
+	}
+	if t.name != "" {
+		w.clearTaskName(p.options)
+	}
+	p.metrics.completed.Add(1)
+	if p.trackTasks {
+		w.infoEnded = true
+		endTask(p.options, &p.metrics, &w.info)
+	}
+	panicked = false
+}
+
+// init 调用 WorkerInit 创建 per-worker 资源
+// 在 worker goroutine 启动前同步执行，失败时返回包装了 ErrWorkerInit 的错误
+func (w *goWorker) init() error {
+	w.state = nil
+	if w.pool.options.WorkerInit == nil {
+		return nil
+	}
+
+	state, err := w.pool.options.WorkerInit()
+	if err != nil {
+		return w.pool.options.nameError(fmt.Errorf("%w: %w", ErrWorkerInit, err))
+	}
+	w.state = state
+	return nil
+}
+
+// teardown 调用 WorkerTeardown 释放 per-worker 资源
+func (w *goWorker) teardown() {
+	if w.pool.options.WorkerTeardown != nil && w.pool.options.WorkerInit != nil {
+		w.pool.options.WorkerTeardown(w.state)
+	}
+	w.state = nil
+}
+
+// updateLastUsed 更新 worker 的最后使用时间
+// 用于超时回收机制
+func (w *goWorker) updateLastUsed() {
+	w.lastUsed.Store(w.pool.options.clock().Now().UnixNano())
+}
+
+// idleSince 返回 worker 最后一次执行完任务的时间
+func (w *goWorker) idleSince() time.Time {
+	return time.Unix(0, w.lastUsed.Load())
+}
+
+// IdleSince 实现 Worker 接口，返回 worker 最后一次执行完任务的时间
+func (w *goWorker) IdleSince() time.Time {
+	return w.idleSince()
+}
+
 // isRecycled 检查 worker 是否已被回收
 func (w *goWorker) isRecycled() bool {
 	return atomic.LoadInt32(&w.recycled) == 1
 }
 
-// recycle 标记 worker 为已回收状态
-func (w *goWorker) recycle() {
-	atomic.StoreInt32(&w.recycled, 1)
+// recycle 标记 worker 为已回收状态，已经回收过时返回 false
+func (w *goWorker) recycle() bool {
+	return atomic.CompareAndSwapInt32(&w.recycled, 0, 1)
+}
+
+// expire 标记 worker 为过期并结束 worker
+func (w *goWorker) expire() {
+	atomic.StoreInt32(&w.expired, 1)
+	w.finish()
 }
 
-// finish 结束 worker，关闭任务 channel
+// finish 结束 worker，向其发送退出信号
+// 不关闭任务 channel：即使有提交者与关闭过程竞争，向 worker 投递任务也不会
+// 因 channel 已关闭而 panic。只有第一次调用会发送退出信号，
+// 被结束的 worker 都已从空闲队列中取出，channel 的缓冲为空，发送不会阻塞。
 func (w *goWorker) finish() {
-	w.recycle()
-	close(w.task)
+	if w.recycle() {
+		w.task <- taskItem{stop: true}
+	}
+}
+
+// discard 结束被其他池的自定义队列取出的 worker
+// worker 已离开所属池的空闲队列，扣减所属池的空闲计数；退出时归还所属池的运行计数。
+func (w *goWorker) discard() {
+	atomic.AddInt32(&w.pool.free, -1)
+	w.finish()
 }
@@ -1,6 +1,9 @@
 package laborer
 
-import "time"
+import (
+	"sort"
+	"time"
+)
 
 // loopQueue 使用循环队列（FIFO）结构实现 worker 队列
 // 适用于大容量场景，提供高效的入队和出队操作
@@ -11,7 +14,6 @@ type loopQueue struct {
 	tail   int
 	size   int
 	isFull bool
-	expiry []*goWorker
 }
 
 // newWorkerLoopQueue 创建一个新的循环队列
@@ -80,61 +82,47 @@ func (wq *loopQueue) detach() *goWorker {
 	return w
 }
 
+// each 从队列头部到尾部遍历所有 worker
+func (wq *loopQueue) each(fn func(worker *goWorker)) {
+	n := wq.len()
+	for i := 0; i < n; i++ {
+		fn(wq.items[(wq.head+i)%wq.size])
+	}
+}
+
 // refresh 清理过期的 worker
-// 从队列头部开始检查，移除所有超过 duration 时间未使用的 worker
-// 返回被清理的 worker 索引列表
-// 优化：减少内存分配，批量处理过期 worker
-func (wq *loopQueue) refresh(duration time.Duration) []int {
+// 队列中的 worker 按归还时间从头部到尾部排列，二分查找过期边界后
+// 一次性移除头部所有超过 duration 时间未使用的 worker
+// 返回被清理的 worker 的编号和空闲时长
+func (wq *loopQueue) refresh(now time.Time, duration time.Duration) []expiredWorker {
 	if wq.isEmpty() {
 		return nil
 	}
 
-	expiryTime := time.Now().Add(-duration)
-
-	// 复用 expiry 切片
-	if cap(wq.expiry) > 0 {
-		wq.expiry = wq.expiry[:0]
-	} else {
-		wq.expiry = make([]*goWorker, 0, 8)
+	index := wq.binarySearch(now.Add(-duration))
+	if index == 0 {
+		return nil
 	}
 
-	var indices []int
-	expiredCount := 0
-
-	// 从头部开始检查过期的 worker
-	for !wq.isEmpty() {
-		w := wq.items[wq.head]
-		if w == nil || w.lastUsed.After(expiryTime) {
-			break
-		}
-
-		if indices == nil {
-			// 延迟分配，只在有过期 worker 时才分配
-			indices = make([]int, 0, 8)
-		}
-
-		indices = append(indices, wq.head)
-		wq.expiry = append(wq.expiry, w)
-		wq.items[wq.head] = nil
-		wq.head++
-
-		if wq.head == wq.size {
-			wq.head = 0
-		}
-
-		wq.isFull = false
-		expiredCount++
+	expired := make([]expiredWorker, index)
+	for i := range expired {
+		pos := (wq.head + i) % wq.size
+		expired[i] = newExpiredWorker(wq.items[pos], now)
+		wq.items[pos].expire()
+		wq.items[pos] = nil // 清空引用，帮助 GC
 	}
 
-	// 关闭过期的 worker（批量处理）
-	if expiredCount > 0 {
-		for i, w := range wq.expiry {
-			w.finish()
-			wq.expiry[i] = nil // 清空引用，帮助 GC
-		}
-	}
+	wq.head = (wq.head + index) % wq.size
+	wq.isFull = false
+
+	return expired
+}
 
-	return indices
+// binarySearch 返回从头部开始已过期（expiryTime 之后未被使用）的 worker 数量
+func (wq *loopQueue) binarySearch(expiryTime time.Time) int {
+	return sort.Search(wq.len(), func(i int) bool {
+		return wq.items[(wq.head+i)%wq.size].idleSince().After(expiryTime)
+	})
 }
 
 // reset 重置队列，清空所有 worker
@@ -180,7 +168,6 @@ type loopQueueWithFunc struct {
 	tail   int
 	size   int
 	isFull bool
-	expiry []*goWorkerWithFunc
 }
 
 // newWorkerLoopQueueWithFunc 创建一个新的函数池循环队列
@@ -249,61 +236,47 @@ func (wq *loopQueueWithFunc) detach() *goWorkerWithFunc {
 	return w
 }
 
+// each 从队列头部到尾部遍历所有 worker
+func (wq *loopQueueWithFunc) each(fn func(worker *goWorkerWithFunc)) {
+	n := wq.len()
+	for i := 0; i < n; i++ {
+		fn(wq.items[(wq.head+i)%wq.size])
+	}
+}
+
 // refresh 清理过期的 worker
-// 从队列头部开始检查，移除所有超过 duration 时间未使用的 worker
-// 返回被清理的 worker 索引列表
-// 优化：减少内存分配，批量处理过期 worker
-func (wq *loopQueueWithFunc) refresh(duration time.Duration) []int {
+// 队列中的 worker 按归还时间从头部到尾部排列，二分查找过期边界后
+// 一次性移除头部所有超过 duration 时间未使用的 worker
+// 返回被清理的 worker 的编号和空闲时长
+func (wq *loopQueueWithFunc) refresh(now time.Time, duration time.Duration) []expiredWorker {
 	if wq.isEmpty() {
 		return nil
 	}
 
-	expiryTime := time.Now().Add(-duration)
-
-	// 复用 expiry 切片
-	if cap(wq.expiry) > 0 {
-		wq.expiry = wq.expiry[:0]
-	} else {
-		wq.expiry = make([]*goWorkerWithFunc, 0, 8)
+	index := wq.binarySearch(now.Add(-duration))
+	if index == 0 {
+		return nil
 	}
 
-	var indices []int
-	expiredCount := 0
-
-	// 从头部开始检查过期的 worker
-	for !wq.isEmpty() {
-		w := wq.items[wq.head]
-		if w == nil || w.lastUsed.After(expiryTime) {
-			break
-		}
-
-		if indices == nil {
-			// 延迟分配，只在有过期 worker 时才分配
-			indices = make([]int, 0, 8)
-		}
-
-		indices = append(indices, wq.head)
-		wq.expiry = append(wq.expiry, w)
-		wq.items[wq.head] = nil
-		wq.head++
-
-		if wq.head == wq.size {
-			wq.head = 0
-		}
-
-		wq.isFull = false
-		expiredCount++
+	expired := make([]expiredWorker, index)
+	for i := range expired {
+		pos := (wq.head + i) % wq.size
+		expired[i] = newExpiredWorker(wq.items[pos], now)
+		wq.items[pos].expire()
+		wq.items[pos] = nil // 清空引用，帮助 GC
 	}
 
-	// 关闭过期的 worker（批量处理）
-	if expiredCount > 0 {
-		for i, w := range wq.expiry {
-			w.finish()
-			wq.expiry[i] = nil // 清空引用，帮助 GC
-		}
-	}
+	wq.head = (wq.head + index) % wq.size
+	wq.isFull = false
+
+	return expired
+}
 
-	return indices
+// binarySearch 返回从头部开始已过期（expiryTime 之后未被使用）的 worker 数量
+func (wq *loopQueueWithFunc) binarySearch(expiryTime time.Time) int {
+	return sort.Search(wq.len(), func(i int) bool {
+		return wq.items[(wq.head+i)%wq.size].idleSince().After(expiryTime)
+	})
 }
 
 // reset 重置队列，清空所有 worker
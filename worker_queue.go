@@ -1,6 +1,50 @@
 package laborer
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
+
+// QueueType 空闲 worker 队列使用的数据结构
+type QueueType int
+
+const (
+	// AutoQueue 按容量自动选择：无限容量或容量小于 QueueThreshold 时使用栈，否则使用循环队列
+	AutoQueue QueueType = iota
+
+	// Stack 栈（LIFO），优先复用最近归还的 worker，其栈和缓存仍然是热的
+	Stack
+
+	// LoopQueue 循环队列（FIFO），按归还顺序轮流复用 worker，负载分散到所有 worker 上
+	// 队列大小固定为池的容量，不能用于无限容量的池。
+	LoopQueue
+)
+
+// valid 返回队列类型是否为已定义的值
+func (t QueueType) valid() bool {
+	switch t {
+	case AutoQueue, Stack, LoopQueue:
+		return true
+	}
+	return false
+}
+
+// expiredWorker 清理 goroutine 回收的一个过期 worker，用于记录 worker_expired 事件
+type expiredWorker struct {
+	// id worker 在池内的编号
+	id int
+
+	// idleFor 被回收时已经空闲的时长
+	idleFor time.Duration
+}
+
+// newExpiredWorker 记录 w 在 now 时刻被回收时的编号和空闲时长
+func newExpiredWorker(w interface {
+	workerID() int
+	idleSince() time.Time
+}, now time.Time) expiredWorker {
+	return expiredWorker{id: w.workerID(), idleFor: now.Sub(w.idleSince())}
+}
 
 // workerQueue 定义了 worker 队列的接口
 // 用于管理空闲的 worker，支持高效的插入和获取操作
@@ -17,8 +61,11 @@ type workerQueue interface {
 	// detach 从队列中取出一个 worker
 	detach() *goWorker
 
-	// refresh 清理过期的 worker，返回被清理的 worker 索引列表
-	refresh(duration time.Duration) []int
+	// each 按队列顺序遍历所有 worker，调用方需持有池的锁
+	each(fn func(worker *goWorker))
+
+	// refresh 清理在 now 时已空闲超过 duration 的 worker，返回被清理的 worker 的编号和空闲时长
+	refresh(now time.Time, duration time.Duration) []expiredWorker
 
 	// reset 重置队列
 	reset()
@@ -39,9 +86,126 @@ type workerQueueWithFunc interface {
 	// detach 从队列中取出一个 worker
 	detach() *goWorkerWithFunc
 
-	// refresh 清理过期的 worker，返回被清理的 worker 索引列表
-	refresh(duration time.Duration) []int
+	// each 按队列顺序遍历所有 worker，调用方需持有池的锁
+	each(fn func(worker *goWorkerWithFunc))
+
+	// refresh 清理在 now 时已空闲超过 duration 的 worker，返回被清理的 worker 的编号和空闲时长
+	refresh(now time.Time, duration time.Duration) []expiredWorker
 
 	// reset 重置队列
 	reset()
 }
+
+// Worker 空闲队列中的一个 worker
+//
+// 自定义的 WorkerQueue 只需要保存、取出和遍历 worker，结束 worker 由池负责。
+// Pool 和 PoolWithFunc 的 worker 都实现了此接口，同一个 WorkerQueue 实现可以用于两者。
+type Worker interface {
+	// IdleSince 返回 worker 最后一次执行完任务的时间
+	IdleSince() time.Time
+}
+
+// WorkerQueue 空闲 worker 队列，通过 WithWorkerQueue 替换池内置的栈和循环队列
+//
+// 所有方法都在持有池的锁时调用，实现不需要额外加锁。
+// 用于尝试无锁队列、NUMA 感知的结构等，而不必 fork 整个包。
+type WorkerQueue interface {
+	// Len 返回队列中的 worker 数量
+	Len() int
+
+	// Insert 将归还的 worker 放入队列，返回错误时该 worker 退出
+	Insert(w Worker) error
+
+	// Detach 取出一个 worker，队列为空时返回 nil
+	Detach() Worker
+
+	// Each 遍历队列中的所有 worker
+	Each(fn func(w Worker))
+
+	// Refresh 移除并返回空闲超过 duration 的 worker，池会结束它们
+	Refresh(duration time.Duration) []Worker
+
+	// Reset 移除并返回所有 worker，池会结束它们
+	Reset() []Worker
+}
+
+// customQueue 将用户提供的 WorkerQueue 适配为池内部的队列接口
+type customQueue[W idleWorker] struct {
+	q WorkerQueue
+
+	// opts 所属池的选项，用于上报队列返回的无效 worker
+	opts *Options
+}
+
+// own 将队列返回的 worker 转换为池的 worker 类型
+// 队列返回了不属于此池的 worker 时上报 ErrWorkerQueue 并返回 false。
+func (c customQueue[W]) own(w Worker, op string) (W, bool) {
+	x, ok := w.(W)
+	if !ok && w != nil {
+		c.opts.reportError(fmt.Errorf("%w: %s returned a worker of another pool: %T", ErrWorkerQueue, op, w))
+	}
+	return x, ok
+}
+
+// len 返回队列中的 worker 数量
+func (c customQueue[W]) len() int {
+	return c.q.Len()
+}
+
+// isEmpty 检查队列是否为空
+func (c customQueue[W]) isEmpty() bool {
+	return c.q.Len() == 0
+}
+
+// insert 将 worker 插入队列
+func (c customQueue[W]) insert(w W) error {
+	return c.q.Insert(w)
+}
+
+// drop 转换已从队列中移除的 worker，不属于此池时还会结束它
+// 其他池的 worker 离开队列后不会再被取出，结束它使其 goroutine 退出并归还所属池的计数，
+// 而不是一直阻塞等待任务。
+func (c customQueue[W]) drop(w Worker, op string) (W, bool) {
+	x, ok := c.own(w, op)
+	if d, foreign := w.(interface{ discard() }); !ok && foreign {
+		d.discard()
+	}
+	return x, ok
+}
+
+// detach 从队列中取出一个 worker，队列为空或返回了其他池的 worker 时返回零值
+func (c customQueue[W]) detach() W {
+	w, _ := c.drop(c.q.Detach(), "Detach")
+	return w
+}
+
+// each 遍历队列中的所有 worker
+func (c customQueue[W]) each(fn func(w W)) {
+	c.q.Each(func(w Worker) {
+		if x, ok := c.own(w, "Each"); ok {
+			fn(x)
+		}
+	})
+}
+
+// refresh 结束 Refresh 返回的过期 worker，返回它们的编号和空闲时长
+func (c customQueue[W]) refresh(now time.Time, duration time.Duration) []expiredWorker {
+	workers := c.q.Refresh(duration)
+	expired := make([]expiredWorker, 0, len(workers))
+	for _, w := range workers {
+		if x, ok := c.drop(w, "Refresh"); ok {
+			expired = append(expired, newExpiredWorker(x, now))
+			x.expire()
+		}
+	}
+	return expired
+}
+
+// reset 结束队列中的所有 worker
+func (c customQueue[W]) reset() {
+	for _, w := range c.q.Reset() {
+		if x, ok := c.drop(w, "Reset"); ok {
+			x.finish()
+		}
+	}
+}
@@ -0,0 +1,284 @@
+package laborer
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newIdleWorker 创建一个最后使用时间为 lastUsed 的空闲 worker，不启动 goroutine
+func newIdleWorker(lastUsed time.Time) *goWorker {
+	w := &goWorker{task: make(chan taskItem, 1)}
+	w.lastUsed.Store(lastUsed.UnixNano())
+	return w
+}
+
+// TestWorkerQueueRefresh 测试栈和循环队列按归还时间二分查找过期边界
+func TestWorkerQueueRefresh(t *testing.T) {
+	now := time.Now()
+	queues := map[string]workerQueue{
+		"stack":     newWorkerStack(0),
+		"loopQueue": newWorkerLoopQueue(8),
+	}
+
+	for name, q := range queues {
+		// 循环队列先入队再出队几个，使之后的元素跨越数组末尾
+		for i := 0; i < 5; i++ {
+			_ = q.insert(newIdleWorker(now.Add(-time.Hour)))
+		}
+		for i := 0; i < 5; i++ {
+			q.detach()
+		}
+
+		var workers []*goWorker
+		for i := 0; i < 7; i++ {
+			// 前 4 个已过期，后 3 个未过期
+			w := newIdleWorker(now.Add(time.Duration(i-4) * time.Minute))
+			workers = append(workers, w)
+			_ = q.insert(w)
+		}
+
+		expired := q.refresh(time.Now(), 30*time.Second)
+		if len(expired) != 4 {
+			t.Errorf("%s: 期望清理 4 个 worker，实际 %d 个", name, len(expired))
+		}
+		for _, e := range expired {
+			if e.idleFor < 30*time.Second {
+				t.Errorf("%s: 过期 worker 的空闲时长为 %v", name, e.idleFor)
+			}
+		}
+		if q.len() != 3 {
+			t.Errorf("%s: 期望剩余 3 个 worker，实际 %d 个", name, q.len())
+		}
+		for i, w := range workers {
+			expired := atomic.LoadInt32(&w.expired) == 1
+			if expired != (i < 4) {
+				t.Errorf("%s: worker %d 过期状态为 %v", name, i, expired)
+			}
+		}
+
+		// 再次清理时没有过期的 worker
+		if q.refresh(time.Now(), 30*time.Second) != nil {
+			t.Errorf("%s: 期望没有更多过期 worker", name)
+		}
+	}
+}
+
+// TestWorkerLastUsedConcurrent 测试归还 worker、过期清理和 Workers 并发访问最后使用时间
+// 在 -race 下运行时不应报告数据竞争
+func TestWorkerLastUsedConcurrent(t *testing.T) {
+	for _, opts := range [][]Option{nil, {WithShardedLocking(true)}} {
+		opts = append(opts, WithExpiryDuration(time.Millisecond), WithCleanInterval(time.Millisecond))
+		pool, err := NewPool(8, opts...)
+		if err != nil {
+			t.Fatalf("创建池失败: %v", err)
+		}
+
+		deadline := time.Now().Add(50 * time.Millisecond)
+		for time.Now().Before(deadline) {
+			for i := 0; i < 8; i++ {
+				_ = pool.Submit(func() {})
+			}
+			for _, info := range pool.Workers() {
+				if info.IdleFor < 0 {
+					t.Errorf("空闲时长不应为负数: %v", info.IdleFor)
+				}
+			}
+		}
+		pool.Release()
+	}
+}
+
+// sliceQueue 测试用的自定义队列，按先进先出复用 worker
+type sliceQueue struct {
+	items   []Worker
+	inserts int
+}
+
+func (q *sliceQueue) Len() int { return len(q.items) }
+
+func (q *sliceQueue) Insert(w Worker) error {
+	q.inserts++
+	q.items = append(q.items, w)
+	return nil
+}
+
+func (q *sliceQueue) Detach() Worker {
+	if len(q.items) == 0 {
+		return nil
+	}
+	w := q.items[0]
+	q.items = q.items[1:]
+	return w
+}
+
+func (q *sliceQueue) Each(fn func(w Worker)) {
+	for _, w := range q.items {
+		fn(w)
+	}
+}
+
+func (q *sliceQueue) Refresh(duration time.Duration) []Worker {
+	var expired, kept []Worker
+	for _, w := range q.items {
+		if time.Since(w.IdleSince()) > duration {
+			expired = append(expired, w)
+		} else {
+			kept = append(kept, w)
+		}
+	}
+	q.items = kept
+	return expired
+}
+
+func (q *sliceQueue) Reset() []Worker {
+	all := q.items
+	q.items = nil
+	return all
+}
+
+// TestCustomWorkerQueue 测试使用自定义的空闲 worker 队列
+func TestCustomWorkerQueue(t *testing.T) {
+	q := &sliceQueue{}
+	pool, err := NewPool(4,
+		WithExpiryDuration(50*time.Millisecond),
+		WithWorkerQueue(func(size int) WorkerQueue {
+			if size != 4 {
+				t.Errorf("期望以容量 4 创建队列，实际 %d", size)
+			}
+			return q
+		}))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+
+	var counter int32
+	for i := 0; i < 20; i++ {
+		if err := pool.Submit(func() { atomic.AddInt32(&counter, 1) }); err != nil {
+			t.Fatalf("提交任务失败: %v", err)
+		}
+	}
+	waitFor(t, func() bool { return atomic.LoadInt32(&counter) == 20 })
+	waitFor(t, func() bool { return pool.IdleWorkers() == pool.Running() })
+
+	pool.lock.Lock()
+	inserts := q.inserts
+	pool.lock.Unlock()
+	if inserts == 0 {
+		t.Error("归还的 worker 应该放入自定义队列")
+	}
+	if len(pool.Workers()) != pool.IdleWorkers() {
+		t.Errorf("Workers 与 IdleWorkers 不一致: %d != %d", len(pool.Workers()), pool.IdleWorkers())
+	}
+
+	// 过期的 worker 由 Refresh 返回后被池结束
+	waitFor(t, func() bool { return pool.Running() == 0 })
+	pool.Release()
+
+	// 工厂返回 nil 时创建失败
+	if _, err := NewPoolWithFunc(4, func(interface{}) {}, WithWorkerQueue(func(int) WorkerQueue { return nil })); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("期望返回 ErrInvalidOption，实际返回: %v", err)
+	}
+}
+
+// rejectQueue 拒绝所有插入的队列，用于测试错误上报
+type rejectQueue struct {
+	sliceQueue
+}
+
+func (q *rejectQueue) Insert(w Worker) error {
+	return errors.New("queue is read-only")
+}
+
+// foreignWorker 不属于任何池的 worker
+type foreignWorker struct{}
+
+func (foreignWorker) IdleSince() time.Time { return time.Time{} }
+
+// TestErrorHandler 测试队列错误通过 ErrorHandler 上报，未设置时写入日志
+func TestErrorHandler(t *testing.T) {
+	var mu sync.Mutex
+	var reported []error
+	pool, err := NewPool(2,
+		WithName("queue"),
+		WithWorkerQueue(func(int) WorkerQueue { return &rejectQueue{} }),
+		WithErrorHandler(func(err error) {
+			mu.Lock()
+			reported = append(reported, err)
+			mu.Unlock()
+		}),
+	)
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	if err := pool.Submit(func() {}); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	waitFor(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(reported) > 0
+	})
+	mu.Lock()
+	got := reported[0]
+	mu.Unlock()
+	if !errors.Is(got, ErrWorkerQueue) || !strings.HasPrefix(got.Error(), `pool "queue": `) {
+		t.Errorf("期望上报带有池名称的 ErrWorkerQueue，实际: %v", got)
+	}
+	// 放不回队列的 worker 退出，不影响之后的提交
+	waitFor(t, func() bool { return pool.Running() == 0 })
+
+	// 自定义队列返回其他池的 worker 时，未设置处理函数则写入 pool_error 事件
+	events := &eventRecorder{}
+	foreign := &sliceQueue{items: []Worker{foreignWorker{}}}
+	other, err := NewPool(1, WithEventLogger(events), WithWorkerQueue(func(int) WorkerQueue { return foreign }))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer other.Release()
+	if err := other.Submit(func() {}); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	e, ok := events.find("pool_error")
+	if !ok || e.Level != LevelError || !errors.Is(e.Fields[0].Value.(error), ErrWorkerQueue) {
+		t.Errorf("期望记录 pool_error 事件，实际 %+v", e)
+	}
+}
+
+// TestCustomQueueForeignWorker 测试从自定义队列取出的其他池的 worker 被结束，所属池的计数随之归还
+func TestCustomQueueForeignWorker(t *testing.T) {
+	shared := &sliceQueue{}
+	factory := func(int) WorkerQueue { return shared }
+
+	funcPool, err := NewPoolWithFunc(1, func(interface{}) {}, WithWorkerQueue(factory), WithDisablePurge(true),
+		WithErrorHandler(func(error) {}))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer funcPool.Release()
+	pool, err := NewPool(1, WithWorkerQueue(factory), WithDisablePurge(true), WithErrorHandler(func(error) {}))
+	if err != nil {
+		t.Fatalf("创建池失败: %v", err)
+	}
+	defer pool.Release()
+
+	// 函数池的 worker 归还到共享的队列中
+	if err := funcPool.Invoke(nil); err != nil {
+		t.Fatalf("提交调用失败: %v", err)
+	}
+	waitFor(t, func() bool { return funcPool.IdleWorkers() == 1 })
+
+	// 通用池取出函数池的 worker，应该结束它而不是让它一直等待
+	if err := pool.Submit(func() {}); err != nil {
+		t.Fatalf("提交任务失败: %v", err)
+	}
+	waitFor(t, func() bool { return funcPool.Running() == 0 })
+	if n := funcPool.IdleWorkers(); n != 0 {
+		t.Errorf("期望函数池没有空闲 worker，实际 %d", n)
+	}
+}
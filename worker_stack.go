@@ -1,14 +1,16 @@
 package laborer
 
-import "time"
+import (
+	"sort"
+	"time"
+)
 
 // workerStack 使用栈（LIFO）结构实现 worker 队列
 // 适用于小容量场景（< 1000），优先使用最近使用的 worker（缓存友好）
 // 内存布局优化：将常用字段放在前面，提高缓存命中率
 type workerStack struct {
-	items  []*goWorker
-	size   int
-	expiry []*goWorker
+	items []*goWorker
+	size  int
 }
 
 // newWorkerStack 创建一个新的 worker 栈
@@ -56,33 +58,37 @@ func (wq *workerStack) detach() *goWorker {
 	return w
 }
 
+// each 从栈底到栈顶遍历所有 worker
+func (wq *workerStack) each(fn func(worker *goWorker)) {
+	for _, w := range wq.items {
+		fn(w)
+	}
+}
+
 // refresh 清理过期的 worker
-// 遍历栈中的所有 worker，将超过 duration 时间未使用的 worker 标记为过期
-// 返回被清理的 worker 在原栈中的索引列表
-// 优化：减少内存分配，复用 expiry 切片，使用更高效的算法
-func (wq *workerStack) refresh(duration time.Duration) []int {
+// 栈中的 worker 按归还时间从栈底到栈顶排列，二分查找过期边界，
+// 将栈底超过 duration 时间未使用的 worker 标记为过期
+// 返回被清理的 worker 的编号和空闲时长
+// 优化：二分查找边界，只为返回的结果分配一次内存
+func (wq *workerStack) refresh(now time.Time, duration time.Duration) []expiredWorker {
 	n := len(wq.items)
 	if n == 0 {
 		return nil
 	}
 
-	expiryTime := time.Now().Add(-duration)
-	index := 0
+	expiryTime := now.Add(-duration)
 
-	// 找到第一个未过期的 worker
-	for index < n && wq.items[index].lastUsed.Before(expiryTime) {
-		index++
-	}
+	// 二分查找第一个未过期的 worker
+	index := wq.binarySearch(expiryTime)
 
 	// 如果有过期的 worker
 	if index > 0 {
-		// 复用 expiry 切片，避免重新分配
-		if cap(wq.expiry) >= index {
-			wq.expiry = wq.expiry[:index]
-		} else {
-			wq.expiry = make([]*goWorker, index)
+		// 关闭过期的 worker，结果切片是唯一的内存分配
+		expired := make([]expiredWorker, index)
+		for i, w := range wq.items[:index] {
+			expired[i] = newExpiredWorker(w, now)
+			w.expire()
 		}
-		copy(wq.expiry, wq.items[:index])
 
 		// 移动未过期的 worker 到前面（优化：使用 copy 一次性完成）
 		m := copy(wq.items, wq.items[index:])
@@ -93,24 +99,19 @@ func (wq *workerStack) refresh(duration time.Duration) []int {
 		}
 		wq.items = wq.items[:m]
 
-		// 关闭过期的 worker（在返回前执行，减少持锁时间）
-		for i, w := range wq.expiry {
-			w.finish()
-			// 直接使用索引，避免额外的切片分配
-			wq.expiry[i] = nil
-		}
-
-		// 返回过期 worker 的索引列表（优化：预分配固定大小）
-		indices := make([]int, index)
-		for i := range indices {
-			indices[i] = i
-		}
-		return indices
+		return expired
 	}
 
 	return nil
 }
 
+// binarySearch 返回栈底已过期（expiryTime 之前最后一次使用）的 worker 数量
+func (wq *workerStack) binarySearch(expiryTime time.Time) int {
+	return sort.Search(len(wq.items), func(i int) bool {
+		return !wq.items[i].idleSince().Before(expiryTime)
+	})
+}
+
 // reset 重置栈，清空所有 worker
 func (wq *workerStack) reset() {
 	// 关闭所有 worker
@@ -130,9 +131,8 @@ func (wq *workerStack) reset() {
 // 适用于小容量场景（< 1000），优先使用最近使用的 worker（缓存友好）
 // 内存布局优化：将常用字段放在前面，提高缓存命中率
 type workerStackWithFunc struct {
-	items  []*goWorkerWithFunc
-	size   int
-	expiry []*goWorkerWithFunc
+	items []*goWorkerWithFunc
+	size  int
 }
 
 // newWorkerStackWithFunc 创建一个新的函数池 worker 栈
@@ -180,33 +180,37 @@ func (wq *workerStackWithFunc) detach() *goWorkerWithFunc {
 	return w
 }
 
+// each 从栈底到栈顶遍历所有 worker
+func (wq *workerStackWithFunc) each(fn func(worker *goWorkerWithFunc)) {
+	for _, w := range wq.items {
+		fn(w)
+	}
+}
+
 // refresh 清理过期的 worker
-// 遍历栈中的所有 worker，将超过 duration 时间未使用的 worker 标记为过期
-// 返回被清理的 worker 在原栈中的索引列表
-// 优化：减少内存分配，复用 expiry 切片，使用更高效的算法
-func (wq *workerStackWithFunc) refresh(duration time.Duration) []int {
+// 栈中的 worker 按归还时间从栈底到栈顶排列，二分查找过期边界，
+// 将栈底超过 duration 时间未使用的 worker 标记为过期
+// 返回被清理的 worker 的编号和空闲时长
+// 优化：二分查找边界，只为返回的结果分配一次内存
+func (wq *workerStackWithFunc) refresh(now time.Time, duration time.Duration) []expiredWorker {
 	n := len(wq.items)
 	if n == 0 {
 		return nil
 	}
 
-	expiryTime := time.Now().Add(-duration)
-	index := 0
+	expiryTime := now.Add(-duration)
 
-	// 找到第一个未过期的 worker
-	for index < n && wq.items[index].lastUsed.Before(expiryTime) {
-		index++
-	}
+	// 二分查找第一个未过期的 worker
+	index := wq.binarySearch(expiryTime)
 
 	// 如果有过期的 worker
 	if index > 0 {
-		// 复用 expiry 切片，避免重新分配
-		if cap(wq.expiry) >= index {
-			wq.expiry = wq.expiry[:index]
-		} else {
-			wq.expiry = make([]*goWorkerWithFunc, index)
+		// 关闭过期的 worker，结果切片是唯一的内存分配
+		expired := make([]expiredWorker, index)
+		for i, w := range wq.items[:index] {
+			expired[i] = newExpiredWorker(w, now)
+			w.expire()
 		}
-		copy(wq.expiry, wq.items[:index])
 
 		// 移动未过期的 worker 到前面（优化：使用 copy 一次性完成）
 		m := copy(wq.items, wq.items[index:])
@@ -217,24 +221,19 @@ func (wq *workerStackWithFunc) refresh(duration time.Duration) []int {
 		}
 		wq.items = wq.items[:m]
 
-		// 关闭过期的 worker（在返回前执行，减少持锁时间）
-		for i, w := range wq.expiry {
-			w.finish()
-			// 直接使用索引，避免额外的切片分配
-			wq.expiry[i] = nil
-		}
-
-		// 返回过期 worker 的索引列表（优化：预分配固定大小）
-		indices := make([]int, index)
-		for i := range indices {
-			indices[i] = i
-		}
-		return indices
+		return expired
 	}
 
 	return nil
 }
 
+// binarySearch 返回栈底已过期（expiryTime 之前最后一次使用）的 worker 数量
+func (wq *workerStackWithFunc) binarySearch(expiryTime time.Time) int {
+	return sort.Search(len(wq.items), func(i int) bool {
+		return !wq.items[i].idleSince().Before(expiryTime)
+	})
+}
+
 // reset 重置栈，清空所有 worker
 func (wq *workerStackWithFunc) reset() {
 	// 关闭所有 worker